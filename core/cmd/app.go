@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"time"
 
 	"github.com/urfave/cli"
 
@@ -31,10 +32,112 @@ func NewApp(client *Client) *cli.App {
 			Name:  "json, j",
 			Usage: "json output as opposed to table",
 		},
+		cli.IntFlag{
+			Name:  "retries",
+			Usage: "number of times to retry remote requests which failed under one of the conditions of --retry-on; requests which may change the node, such as create and configure, are not resent after a timeout or lost connection, as the node may have applied them, and rate limited requests are not retried if the node asks to wait more than a minute",
+		},
+		cli.StringFlag{
+			Name:  "retry-on",
+			Usage: "comma-separated conditions under which --retries retries a request, options: [5xx, 429, timeout, connection]; each item of the bulk chain commands is retried before --on-error applies",
+			Value: defaultRetryOn,
+		},
+		cli.BoolFlag{
+			Name:  "assume-yes, y",
+			Usage: "answer yes to every confirmation prompt of the chain commands, like their --force and --yes flags; this disables all of their safety prompts",
+		},
+		cli.BoolFlag{
+			Name:  "check",
+			Usage: "read-only mode for the chain commands: nothing is changed on the node, and create, configure and delete report what they would do",
+		},
+		cli.StringFlag{
+			Name:  "chain-body-format",
+			Usage: "shape of the bodies of chain create and update requests, options: [plain, jsonapi]; jsonapi wraps the attributes in a {\"data\": {\"type\", \"attributes\"}} document",
+			Value: "plain",
+		},
+		cli.BoolFlag{
+			Name:  "no-follow-redirects",
+			Usage: "fail remote requests redirected by the node, instead of following redirects to the same host with a warning; redirects to other hosts are always refused",
+		},
+		cli.StringFlag{
+			Name:   "auth-token",
+			Usage:  "API token of the form <access key>:<secret> to authenticate remote requests with, for headless use; the node authenticates by the token when it is valid, and falls back to the session cookie of 'admin login' or " + SessionCookieEnv + " otherwise",
+			EnvVar: AuthTokenEnv,
+		},
+		cli.BoolFlag{
+			Name:  "compact-json",
+			Usage: "with --json or --output=json, write JSON on a single line without indentation, as export already writes its JSON lines",
+		},
+		cli.StringFlag{
+			Name:  "table-style",
+			Usage: "style of the borders of tables and lists, options: [default, borderless, heavy]; borderless separates table columns with tabs, for piping into other tools",
+			Value: tableStyleDefault,
+		},
+		cli.BoolFlag{
+			Name:  "pretty-errors",
+			Usage: "append hints on how to fix common mistakes to errors, such as the valid values of a rejected field or checking the node URL when it is unreachable",
+		},
+		cli.BoolFlag{
+			Name:  "http-stats",
+			Usage: "print the number of remote requests, how many used HTTP/2, and how many connections were opened and reused to stderr on exit",
+		},
 	}
+	var httpStats *HTTPStats
 	app.Before = func(c *cli.Context) error {
+		client.CompactJSON = c.Bool("compact-json")
+		client.PrettyErrors = c.Bool("pretty-errors")
 		if c.Bool("json") {
-			client.Renderer = RendererJSON{Writer: os.Stdout}
+			client.Renderer = RendererJSON{Writer: os.Stdout, Compact: client.CompactJSON}
+		}
+		if v := c.String("auth-token"); v != "" {
+			token, err := parseAuthToken(v)
+			if err != nil {
+				return err
+			}
+			if h, ok := client.HTTP.(tokenHTTPClient); ok {
+				client.HTTP = h.WithAuthToken(token)
+			}
+		}
+		if c.Bool("no-follow-redirects") {
+			if h, ok := client.HTTP.(redirectHTTPClient); ok {
+				client.HTTP = h.WithoutRedirects()
+			}
+		}
+		if c.Bool("http-stats") {
+			if h, ok := client.HTTP.(statsHTTPClient); ok {
+				httpStats = &HTTPStats{}
+				client.HTTP = h.WithStats(httpStats)
+			}
+		}
+		retryOn, err := parseRetryConditions(c.String("retry-on"))
+		if err != nil {
+			return err
+		}
+		if retries := c.Int("retries"); retries > 0 {
+			client.HTTP = NewRetryingHTTPClient(client.HTTP, retries, retryOn)
+		}
+		client.CheckMode = c.Bool("check")
+		client.AssumeYes = c.Bool("assume-yes")
+		if _, ok := chainBodyEncoders[c.String("chain-body-format")]; !ok {
+			return fmt.Errorf("unsupported --chain-body-format '%s', options: [plain, jsonapi]", c.String("chain-body-format"))
+		}
+		client.ChainBodyFormat = c.String("chain-body-format")
+		style := c.String("table-style")
+		var valid bool
+		for _, s := range tableStyles {
+			valid = valid || s == style
+		}
+		if !valid {
+			return fmt.Errorf("unsupported --table-style '%s', options: [default, borderless, heavy]", style)
+		}
+		if rt, ok := client.Renderer.(RendererTable); ok {
+			rt.Style = style
+			client.Renderer = rt
+		}
+		return nil
+	}
+	app.After = func(c *cli.Context) error {
+		if httpStats != nil {
+			httpStats.Write(os.Stderr)
 		}
 		return nil
 	}
@@ -1017,40 +1120,267 @@ func NewApp(client *Client) *cli.App {
 			Name:  "chains",
 			Usage: "Commands for handling chain configuration",
 			Subcommands: cli.Commands{
+				{
+					Name:   "self-test",
+					Usage:  "Run the Solana chain commands against an in-process mock node, to check that the CLI works end to end",
+					Hidden: true,
+					Action: client.ChainsSelfTest,
+				},
+				{
+					Name:   "ping",
+					Usage:  "Measure the latency of the node API",
+					Action: client.chainAction(client.PingNode),
+					Flags: []cli.Flag{
+						cli.IntFlag{
+							Name:  "count",
+							Usage: "number of requests to send, reporting their min/avg/max round-trip time",
+							Value: 1,
+						},
+						cli.DurationFlag{
+							Name:  "interval",
+							Usage: "time to wait between requests",
+							Value: time.Second,
+						},
+						cli.StringFlag{
+							Name:  "proxy",
+							Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+						},
+					},
+				},
+				{
+					Name:   "count",
+					Usage:  "Count the chains of one or more families",
+					Action: client.chainAction(client.CountChains),
+					Flags: []cli.Flag{
+						cli.StringSliceFlag{
+							Name:  "family",
+							Usage: "chain family to count, may be repeated (options: evm, solana, terra)",
+						},
+						cli.BoolFlag{
+							Name:  "all-families",
+							Usage: "count the chains of every family",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "output format, options: [table, json, markdown]",
+						},
+						cli.StringFlag{
+							Name:  "proxy",
+							Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+						},
+					},
+				},
+				{
+					Name:      "create",
+					Usage:     "Create a chain of any family, detected from its config unless --type is given",
+					ArgsUsage: "[JSON blob | JSON filepath]",
+					Action:    client.chainAction(client.CreateChain),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "type",
+							Usage: "chain family, options: [evm, solana, terra]; required if the config's fields do not identify a single family",
+						},
+						cli.StringFlag{
+							Name:  "id",
+							Usage: "chain ID",
+						},
+						cli.StringFlag{
+							Name:  "proxy",
+							Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+						},
+					},
+				},
+				{
+					Name:   "list",
+					Usage:  "List the chains of every family",
+					Action: client.chainAction(client.ListChains),
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "group-by-family",
+							Usage: "list the chains in a section per family, with its count; with -o json, as an object keyed by family",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Usage: "output format, options: [table, json, markdown]",
+						},
+						cli.StringFlag{
+							Name:  "proxy",
+							Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+						},
+					},
+				},
 				{
 					Name:  "evm",
 					Usage: "Commands for handling EVM chains",
 					Subcommands: cli.Commands{
 						{
-							Name:   "create",
-							Usage:  "Create a new EVM chain",
-							Action: client.CreateEVMChain,
+							Name:    "create",
+							Aliases: []string{"add"},
+							Usage:   "Create a new EVM chain",
+							Action:  client.chainAction(client.CreateEVMChain),
 							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
 								cli.Int64Flag{
 									Name:  "id",
 									Usage: "chain ID",
 								},
+								cli.BoolFlag{
+									Name:  "no-family-check",
+									Usage: "do not warn if the config looks like the config of another chain family",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
 							},
 						},
 						{
-							Name:   "delete",
-							Usage:  "Delete an EVM chain",
-							Action: client.RemoveEVMChain,
+							Name:    "delete",
+							Aliases: []string{"rm", "remove"},
+							Usage:   "Delete an EVM chain",
+							Action:  client.chainAction(client.RemoveEVMChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+							},
 						},
 						{
-							Name:   "list",
-							Usage:  "List all EVM chains",
-							Action: client.IndexEVMChains,
+							Name:   "ids",
+							Usage:  "Print the IDs of all Solana chains, one per line, fetching only the fields needed",
+							Action: client.chainAction(client.SolanaChainIDs),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.BoolFlag{
+									Name:  "enabled",
+									Usage: "only print the IDs of enabled chains",
+								},
+								cli.BoolFlag{
+									Name:  "disabled",
+									Usage: "only print the IDs of disabled chains",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"ls"},
+							Usage:   "List all EVM chains",
+							Action:  client.chainAction(client.IndexEVMChains),
+							Flags: []cli.Flag{
+								cli.BoolFlag{
+									Name:  "raw",
+									Usage: "print the response body of the node as received, bypassing formatting",
+								},
+								cli.BoolFlag{
+									Name:  "with-links",
+									Usage: "with --json or -o json, wrap the page in {\"data\": [...], \"links\": {...}} with the pagination links of the node",
+								},
+								cli.BoolFlag{
+									Name:  "wide",
+									Usage: "add the chain family, node count and a config hash, which is equal for chains with identical configs",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "select",
+									Usage: "comma separated, possibly nested, fields to write as tab separated values, e.g. ID,Config.TxTimeout",
+								},
+								cli.BoolFlag{
+									Name:  "headers",
+									Usage: "write a header row with --select",
+								},
+								cli.BoolFlag{
+									Name:  "no-header",
+									Usage: "omit the field labels of the chain list, leaving only the values",
+								},
+								cli.IntFlag{
+									Name:  "page-size",
+									Usage: "number of chains to request per page (default 25)",
+								},
+								cli.StringFlag{
+									Name:  "jq",
+									Usage: "write a line of JSON for each chain, projected with a subset of jq: a path or an object of paths, e.g. '{id: .id, commitment: .config.Commitment}'",
+								},
+								cli.StringFlag{
+									Name:  "template-file",
+									Usage: "`FILE` containing a Go text/template to execute for each chain, e.g. {{.ID}}: {{.Config}}",
+								},
+								cli.BoolFlag{
+									Name:  "template-once",
+									Usage: "execute --template-file once over the list of all chains instead of for each chain",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
 						},
 						{
-							Name:   "configure",
-							Usage:  "Configure an EVM chain",
-							Action: client.ConfigureEVMChain,
+							Name:    "configure",
+							Aliases: []string{"set"},
+							Usage:   "Configure an EVM chain",
+							Action:  client.chainAction(client.ConfigureEVMChain),
 							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
 								cli.Int64Flag{
 									Name:  "id",
 									Usage: "chain ID",
 								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
 							},
 						},
 					},
@@ -1060,72 +1390,1218 @@ func NewApp(client *Client) *cli.App {
 					Usage: "Commands for handling Solana chains",
 					Subcommands: cli.Commands{
 						{
-							Name:   "create",
-							Usage:  "Create a new Solana chain",
-							Action: client.CreateSolanaChain,
+							Name:      "create",
+							Aliases:   []string{"add"},
+							Usage:     "Create a new Solana chain",
+							ArgsUsage: "[JSON blob | JSON filepath] (deprecated, use --config-json or --config-file)",
+							Action:    client.chainAction(client.CreateSolanaChain),
 							Flags: []cli.Flag{
 								cli.StringFlag{
-									Name:  "id",
-									Usage: "chain ID, options: [mainnet, testnet, devnet, localnet]",
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+								cli.BoolFlag{
+									Name:  "no-secret-warnings",
+									Usage: "do not warn about config fields which look like secrets being sent in plaintext",
+								},
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON config",
+								},
+								cli.BoolFlag{
+									Name:  "strict-json",
+									Usage: "reject config JSON that repeats a key within the same object instead of keeping the last value",
+								},
+								cli.StringFlag{
+									Name:  "dump-request",
+									Usage: "`FILE` to write the JSON body of each request to before it is sent, e.g. for bug reports; config values are only redacted if --redact is set",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "build the request without sending it, writing its body to --dump-request or stdout",
+								},
+								cli.BoolFlag{
+									Name:  "check-version",
+									Usage: "warn if the node's build differs from the CLI's, as their chain config fields may not match",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
 								},
-							},
-						},
-						{
-							Name:   "delete",
-							Usage:  "Delete a Solana chain",
-							Action: client.RemoveSolanaChain,
-						},
-						{
-							Name:   "list",
-							Usage:  "List all Solana chains",
-							Action: client.IndexSolanaChains,
-						},
-						{
-							Name:   "configure",
-							Usage:  "Configure a Solana chain",
-							Action: client.ConfigureSolanaChain,
-							Flags: []cli.Flag{
 								cli.StringFlag{
 									Name:  "id",
 									Usage: "chain ID, options: [mainnet, testnet, devnet, localnet]",
 								},
+								cli.StringFlag{
+									Name:  "config-json",
+									Usage: "chain config as a JSON blob, used verbatim",
+								},
+								cli.StringSliceFlag{
+									Name:  "config-file",
+									Usage: "`FILE` containing the chain config as JSON, may be repeated to deep-merge later files over earlier ones",
+								},
+								cli.StringFlag{
+									Name:  "config-url",
+									Usage: "http(s) `URL` to fetch the JSON config from, e.g. from a central config server, of at most 1 MiB",
+								},
+								cli.StringSliceFlag{
+									Name:  "config-url-header",
+									Usage: "'Name: value' header to send when fetching --config-url, e.g. for auth, may be repeated",
+								},
+								cli.BoolFlag{
+									Name:  "verbose",
+									Usage: "report how many keys each config file contributed to the final config, and how many fields --trim-config removed",
+								},
+								cli.BoolFlag{
+									Name:  "trim-config",
+									Usage: "remove null fields, and the objects left empty, from the config before creating the chain; explicit nulls are kept otherwise",
+								},
+								cli.BoolFlag{
+									Name:  "no-family-check",
+									Usage: "do not warn if the config looks like the config of another chain family",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+								cli.BoolFlag{
+									Name:  "no-render",
+									Usage: "do not render the chain on success",
+								},
+								cli.BoolFlag{
+									Name:  "show-effective",
+									Usage: "also render the config fields the node filled in or normalized, against the submitted config",
+								},
 							},
 						},
-					},
-				},
-				{
-					Name:  "terra",
-					Usage: "Commands for handling Terra chains",
-					Subcommands: cli.Commands{
 						{
-							Name:   "create",
-							Usage:  "Create a new Terra chain",
-							Action: client.CreateTerraChain,
+							Name:    "delete",
+							Aliases: []string{"rm", "remove"},
+							Usage:   "Delete one or more Solana chains",
+							Action:  client.chainAction(client.RemoveSolanaChain),
 							Flags: []cli.Flag{
 								cli.StringFlag{
-									Name:  "id",
-									Usage: "chain ID",
+									Name:  "on-error",
+									Usage: "what to do when a chain fails, options: [continue, abort]; continue attempts every chain and reports all failures, abort skips the chains not started yet, while those in progress with --max-concurrency finish",
+									Value: "continue",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.IntFlag{
+									Name:  "max-concurrency",
+									Usage: "delete up to `N` chains at a time, at most 16",
+									Value: 1,
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "delete the chains whose IDs match the glob `PATTERN`, e.g. 'testnet-*', instead of those given as arguments",
+								},
+								cli.BoolFlag{
+									Name:  "ids-stdin",
+									Usage: "delete the chains whose IDs are read from stdin as a JSON array of strings, e.g. '[\"devnet\",\"testnet\"]', after confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "confirm-exists",
+									Usage: "check that each chain exists and show its config, then ask for confirmation before deleting any",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "skip the confirmation prompt of --match, --ids-stdin and --confirm-exists",
+								},
+								cli.BoolFlag{
+									Name:  "ignore-not-found",
+									Usage: "treat chains which do not exist as already deleted instead of failing",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json]",
+								},
+								cli.BoolFlag{
+									Name:  "quiet, q",
+									Usage: "suppress progress output",
 								},
 							},
 						},
 						{
-							Name:   "delete",
-							Usage:  "Delete a Terra chain",
-							Action: client.RemoveTerraChain,
-						},
-						{
-							Name:   "list",
-							Usage:  "List all Terra chains",
-							Action: client.IndexTerraChains,
-						},
-						{
-							Name:   "configure",
-							Usage:  "Configure a Terra chain",
-							Action: client.ConfigureTerraChain,
+							Name:    "list",
+							Aliases: []string{"ls"},
+							Usage:   "List all Solana chains",
+							Action:  client.chainAction(client.IndexSolanaChains),
 							Flags: []cli.Flag{
 								cli.StringFlag{
-									Name:  "id",
-									Usage: "chain ID",
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+								cli.IntFlag{
+									Name:  "max-rows",
+									Usage: "only render the first `N` chains, after any filtering and sorting, noting how many more there are on stderr; JSON output is truncated to N elements",
+								},
+								cli.StringFlag{
+									Name:  "output-file",
+									Usage: "`FILE` to write the output to atomically instead of stdout, leaving stderr for warnings",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "overwrite the --output-file if it already exists",
+								},
+								cli.BoolFlag{
+									Name:  "names-only",
+									Usage: "only print each chain as 'ID (Name)', the name from the Name field of its config, or just its ID if it has none",
+								},
+								cli.BoolFlag{
+									Name:  "raw",
+									Usage: "print the response body of the node as received, bypassing formatting",
+								},
+								cli.BoolFlag{
+									Name:  "with-links",
+									Usage: "with --json or -o json, wrap the page in {\"data\": [...], \"links\": {...}} with the pagination links of the node",
+								},
+								cli.BoolFlag{
+									Name:  "wide",
+									Usage: "add the chain family, node count and a config hash, which is equal for chains with identical configs",
+								},
+								cli.BoolFlag{
+									Name:  "only-unhealthy",
+									Usage: "only list chains with at least one node failing its health check, if the node reports them",
+								},
+								cli.StringFlag{
+									Name:  "enabled",
+									Usage: "only list enabled (true) or disabled (false) chains; sent to the node as a query filter, and applied by the CLI too for nodes ignoring it",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "select",
+									Usage: "comma separated, possibly nested, fields to write as tab separated values, e.g. ID,Config.TxTimeout",
+								},
+								cli.BoolFlag{
+									Name:  "headers",
+									Usage: "write a header row with --select",
+								},
+								cli.BoolFlag{
+									Name:  "no-header",
+									Usage: "omit the field labels of the chain list, leaving only the values",
+								},
+								cli.IntFlag{
+									Name:  "page-size",
+									Usage: "number of chains to request per page (default 100)",
+								},
+								cli.StringFlag{
+									Name:  "after",
+									Usage: "`CURSOR` printed as the next cursor of a previous listing, to continue after it",
+								},
+								cli.StringFlag{
+									Name:  "before",
+									Usage: "`CURSOR` printed as the previous cursor of a previous listing, to list the page before it",
+								},
+								cli.StringFlag{
+									Name:  "jq",
+									Usage: "write a line of JSON for each chain, projected with a subset of jq: a path or an object of paths, e.g. '{id: .id, commitment: .config.Commitment}'",
+								},
+								cli.StringFlag{
+									Name:  "template-file",
+									Usage: "`FILE` containing a Go text/template to execute for each chain, e.g. {{.ID}}: {{.Config}}",
+								},
+								cli.BoolFlag{
+									Name:  "template-once",
+									Usage: "execute --template-file once over the list of all chains instead of for each chain",
+								},
+								cli.StringFlag{
+									Name:  "sort",
+									Usage: "sort chains by key, prefix with '-' for descending order, ties are broken by ID (options: id, enabled, created, updated)",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
+						},
+						{
+							Name:   "export",
+							Usage:  "Export all Solana chains as JSON lines, streamed page by page",
+							Action: client.chainAction(client.ExportSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "output-file",
+									Usage: "`FILE` to write the output to atomically instead of stdout, leaving stderr for warnings",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "overwrite the --output-file if it already exists",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "file",
+									Usage: "`FILE` to write the chains to, defaults to stdout",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [jsonl]",
+									Value: "jsonl",
+								},
+							},
+						},
+						{
+							Name:   "apply",
+							Usage:  "Create or update Solana chains to match a file written by export, summarizing what changed",
+							Action: client.chainAction(client.ApplySolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "file",
+									Usage: "`FILE` of chains as JSON lines, as written by export, or - for stdin",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json]; json writes only the summary counts",
+								},
+							},
+						},
+						{
+							Name:   "enable",
+							Usage:  "Enable one or more Solana chains",
+							Action: client.chainAction(client.EnableSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "on-error",
+									Usage: "what to do when a chain fails, options: [continue, abort]; continue attempts every chain and reports all failures, abort stops at the first failure",
+									Value: "continue",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "all",
+									Usage: "enable every Solana chain, after confirmation",
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "enable the chains whose IDs match the glob `PATTERN`, e.g. 'testnet-*', after confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "ids-stdin",
+									Usage: "enable the chains whose IDs are read from stdin as a JSON array of strings, e.g. '[\"devnet\",\"testnet\"]', after confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "skip the confirmation prompt of --all, --match and --ids-stdin",
+								},
+							},
+						},
+						{
+							Name:   "disable",
+							Usage:  "Disable one or more Solana chains",
+							Action: client.chainAction(client.DisableSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "on-error",
+									Usage: "what to do when a chain fails, options: [continue, abort]; continue attempts every chain and reports all failures, abort stops at the first failure",
+									Value: "continue",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "all",
+									Usage: "disable every Solana chain, after confirmation",
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "disable the chains whose IDs match the glob `PATTERN`, e.g. 'testnet-*', after confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "ids-stdin",
+									Usage: "disable the chains whose IDs are read from stdin as a JSON array of strings, e.g. '[\"devnet\",\"testnet\"]', after confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "skip the confirmation prompt of --all, --match and --ids-stdin",
+								},
+							},
+						},
+						{
+							Name:   "set-enabled",
+							Usage:  "Enable or disable a Solana chain according to --value, leaving the rest of it unchanged",
+							Action: client.chainAction(client.SetSolanaChainEnabled),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.StringFlag{
+									Name:  "value",
+									Usage: "whether the chain is enabled, one of true, false, yes, no, on, off, 1 or 0",
+								},
+							},
+						},
+						{
+							Name:   "touch",
+							Usage:  "FOR TESTING: re-send a Solana chain unchanged so that its updatedAt is bumped, e.g. to exercise monitoring of config changes; this is never needed in production",
+							Action: client.chainAction(client.TouchSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+							},
+						},
+						{
+							Name:   "get-config",
+							Usage:  "Write the config of a Solana chain as JSON, e.g. to back it up for 'create --config-file'",
+							Action: client.chainAction(client.GetSolanaChainConfig),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.StringFlag{
+									Name:  "file",
+									Usage: "`FILE` to write the config to atomically, defaults to stdout",
+								},
+							},
+						},
+						{
+							Name:   "config-hash",
+							Usage:  "Print the SHA-256 of the canonical JSON config of a Solana chain, for detecting config drift",
+							Action: client.chainAction(client.SolanaChainConfigHash),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "all",
+									Usage: "print the hash and ID of every chain, a line each",
+								},
+							},
+						},
+						{
+							Name:   "config-get",
+							Usage:  "Write a single config value of a Solana chain as plain text, falling back to --default if it is not set",
+							Action: client.chainAction(client.GetSolanaChainConfigValue),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.StringFlag{
+									Name:  "key",
+									Usage: "dotted `PATH` of the config field, e.g. RPC.URL; array elements are selected by index",
+								},
+								cli.StringFlag{
+									Name:  "default",
+									Usage: "`VALUE` to write if the field is missing or null",
+								},
+								cli.BoolFlag{
+									Name:  "required",
+									Usage: "fail if the field is missing or null, rather than writing an empty line",
+								},
+							},
+						},
+						{
+							Name:   "reconcile",
+							Usage:  "Make the Solana chains of the node match a directory of <chain ID>.json config files, creating missing chains, updating drifted ones and, with --prune, deleting the rest",
+							Action: client.chainAction(client.ReconcileSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "dir",
+									Usage: "`DIR` of the desired chain configs, one <chain ID>.json file per chain",
+								},
+								cli.BoolFlag{
+									Name:  "prune",
+									Usage: "delete the chains without a config file in --dir",
+								},
+								cli.BoolFlag{
+									Name:  "yes",
+									Usage: "apply the plan without asking for confirmation",
+								},
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON configs",
+								},
+							},
+						},
+						{
+							Name:   "stats",
+							Usage:  "Summarize all Solana chains: their number, enabled and disabled counts, average config size, and the oldest and newest",
+							Action: client.chainAction(client.StatsSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.BoolFlag{
+									Name:  "health",
+									Usage: "also count the chains without a healthy node, from the health checks of the node",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+							},
+						},
+						{
+							Name:   "wait",
+							Usage:  "Wait until a Solana chain has at least one healthy node",
+							Action: client.chainAction(client.WaitSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.DurationFlag{
+									Name:  "timeout",
+									Usage: "how long to wait for the chain before failing",
+									Value: 2 * time.Minute,
+								},
+								cli.DurationFlag{
+									Name:  "interval",
+									Usage: "how long to wait between polls",
+									Value: 5 * time.Second,
+								},
+							},
+						},
+						{
+							Name:   "history",
+							Usage:  "List the past configs of a Solana chain, if the node records chain config history",
+							Action: client.chainAction(client.HistorySolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
+						},
+						{
+							Name:   "show",
+							Usage:  "Show a Solana chain",
+							Action: client.chainAction(client.ShowSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "output-file",
+									Usage: "`FILE` to write the output to atomically instead of stdout, leaving stderr for warnings",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "overwrite the --output-file if it already exists",
+								},
+								cli.BoolFlag{
+									Name:  "fields-json",
+									Usage: "print the config as a flat JSON object keyed by dotted paths, e.g. {\"TxTimeout\": \"1m0s\"}",
+								},
+								cli.BoolFlag{
+									Name:  "no-header",
+									Usage: "omit the field labels of the chain, leaving only the values",
+								},
+								cli.StringFlag{
+									Name:  "jq",
+									Usage: "write the chain as a line of JSON, projected with a subset of jq: a path or an object of paths, e.g. '{id: .id, commitment: .config.Commitment}'",
+								},
+								cli.BoolFlag{
+									Name:  "raw",
+									Usage: "print the response body of the node as received, bypassing formatting",
+								},
+								cli.StringFlag{
+									Name:  "template-file",
+									Usage: "`FILE` containing a Go text/template to execute for the chain, e.g. {{.ID}}: {{.Config}}",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID, options: [mainnet, testnet, devnet, localnet]",
+								},
+								cli.StringFlag{
+									Name:  "field",
+									Usage: "only print the config value at this dotted path, e.g. Nodes.0.URL",
+								},
+								cli.StringFlag{
+									Name:  "expect-config",
+									Usage: "`FILE` with the expected JSON config, exits non-zero and prints the differences if the chain's config does not match",
+								},
+								cli.BoolFlag{
+									Name:  "expect-subset",
+									Usage: "with --expect-config, only compare the fields present in the expected config",
+								},
+								cli.StringFlag{
+									Name:  "config-diff",
+									Usage: "diff the config against the baseline JSON config `FILE`, ignoring key order, failing if they differ; with --match, or if FILE is a directory, against FILE/<chain ID>.json",
+								},
+								cli.BoolFlag{
+									Name:  "explain-diff",
+									Usage: "with --config-diff, follow each differing field with its documentation, as explain shows it",
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "with --config-diff, diff the chains whose IDs match the glob `PATTERN`, e.g. 'testnet-*', instead of --id",
+								},
+								cli.StringFlag{
+									Name:  "include",
+									Usage: "related resources to fetch with the chain, options: [nodes]",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown, env, tree, hcl]; env writes the config as sourceable CONFIG_<KEY> shell variables, the dotted field paths uppercased with dots replaced by underscores, tree as an indented tree of its keys, and hcl as an HCL block for e.g. Terraform, omitting null fields and keeping durations as strings",
+								},
+								cli.StringFlag{
+									Name:  "env-prefix",
+									Usage: "with --output env, `PREFIX` of the variable names, e.g. SOLANA_ for SOLANA_CONFIG_RPC_URL, to source the configs of several chains together",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
+						},
+						{
+							Name:      "normalize",
+							Usage:     "Print the canonical form of a Solana chain config file, with defaults filled in, without talking to a node",
+							ArgsUsage: "FILE",
+							Action:    client.NormalizeSolanaChainConfig,
+							Flags: []cli.Flag{
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON config",
+								},
+								cli.BoolFlag{
+									Name:  "no-defaults",
+									Usage: "leave unset fields unset instead of filling in the defaults",
+								},
+							},
+						},
+						{
+							Name:      "explain",
+							Usage:     "Print the type, default and documentation of a Solana chain config field",
+							ArgsUsage: "FIELD",
+							Action:    client.ExplainSolanaChainConfigField,
+						},
+						{
+							Name:   "schema",
+							Usage:  "Print the JSON Schema of Solana chain configs, with the documentation of each field",
+							Action: client.SolanaChainConfigSchema,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "only-fields",
+									Usage: "comma-separated top-level `FIELDS` to restrict the schema to, e.g. TxTimeout,Commitment",
+								},
+							},
+						},
+						{
+							Name:      "lint",
+							Usage:     "Check a Solana chain config file for unknown fields and invalid values, without talking to a node",
+							ArgsUsage: "FILE",
+							Action:    client.LintSolanaChainConfig,
+							Flags: []cli.Flag{
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON config",
+								},
+								cli.BoolFlag{
+									Name:  "strict",
+									Usage: "fail on warnings as well as errors",
+								},
+							},
+						},
+						{
+							Name:  "config-template",
+							Usage: "Commands for the preset Solana chain configs",
+							Subcommands: cli.Commands{
+								{
+									Name:      "render",
+									Usage:     "Print the config of a preset with overrides applied, linted, without talking to a node",
+									ArgsUsage: "[KEY=VALUE...]",
+									Action:    client.RenderSolanaChainConfigTemplate,
+									Flags: []cli.Flag{
+										cli.StringFlag{
+											Name:  "preset",
+											Usage: "`NAME` of the preset to render, options: [devnet, localnet, mainnet-base]",
+										},
+										cli.StringFlag{
+											Name:  "output, o",
+											Usage: "output format, options: [json, yaml]",
+											Value: "json",
+										},
+									},
+								},
+							},
+						},
+						{
+							Name:   "check-roundtrip",
+							Usage:  "Report the config fields of a Solana chain which change when the CLI decodes and encodes them, as configure does",
+							Action: client.chainAction(client.CheckSolanaChainRoundTrip),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+							},
+						},
+						{
+							Name:   "validate-all",
+							Usage:  "Check the config of every Solana chain for unknown fields, deprecated fields and invalid values",
+							Action: client.chainAction(client.ValidateAllSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+								cli.BoolFlag{
+									Name:  "strict",
+									Usage: "fail on warnings as well as errors",
+								},
+							},
+						},
+						{
+							Name:   "doctor",
+							Usage:  "Diagnose problems with the Solana chain commands",
+							Action: client.chainAction(client.DoctorSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+							},
+						},
+						{
+							Name:   "rename",
+							Usage:  "Change the ID of a Solana chain, moving its nodes over (not atomic)",
+							Action: client.chainAction(client.RenameSolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "current chain ID",
+								},
+								cli.StringFlag{
+									Name:  "new-id",
+									Usage: "new chain ID",
+								},
+							},
+						},
+						{
+							Name:      "copy",
+							Usage:     "Create a Solana chain with the config of an existing one",
+							ArgsUsage: "[key1=value1 key2=value2 ...] (config overrides, parsed by the type of their field; a value may end in a type hint, one of :string, :int, :float, :bool, :duration or :json)",
+							Action:    client.chainAction(client.CopySolanaChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "from",
+									Usage: "ID of the chain to copy the config of",
+								},
+								cli.StringFlag{
+									Name:  "to",
+									Usage: "ID of the chain to create",
+								},
+							},
+						},
+						{
+							Name:      "config-unset",
+							Usage:     "Remove one or more config fields of a Solana chain, leaving the rest of its config unchanged",
+							ArgsUsage: "key1 [key2 ...] (dotted paths of the fields, e.g. RPC.Timeout)",
+							Action:    client.chainAction(client.UnsetSolanaChainConfig),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "ignore-missing",
+									Usage: "skip fields which are not set, rather than failing",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+							},
+						},
+						{
+							Name:   "config-apply",
+							Usage:  "Deep-merge a JSON Merge Patch read from stdin into the config of a Solana chain, showing the changes",
+							Action: client.chainAction(client.ApplySolanaChainPatch),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "patch-from-stdin",
+									Usage: "read the patch, a JSON object whose null fields unset the field, from stdin",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "only show the changes, without applying them",
+								},
+							},
+						},
+						{
+							Name:   "config-set-many",
+							Usage:  "Deep-merge a JSON config patch into the config of every Solana chain matching a pattern, after confirmation",
+							Action: client.chainAction(client.ConfigSetManySolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "on-error",
+									Usage: "what to do when a chain fails, options: [continue, abort]; continue attempts every chain and reports all failures, abort skips the chains not started yet, while those in progress with --max-concurrency finish",
+									Value: "continue",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "patch",
+									Usage: "`FILE` with the JSON object to deep-merge into the config of each chain",
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "glob `pattern` of the IDs of the chains to patch, e.g. 'testnet-*'",
+								},
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON patch",
+								},
+								cli.IntFlag{
+									Name:  "max-concurrency",
+									Usage: "update up to `N` chains at a time, at most 16",
+									Value: 1,
+								},
+								cli.BoolFlag{
+									Name:  "yes, y",
+									Usage: "skip the confirmation prompt",
+								},
+							},
+						},
+						{
+							Name:      "configure",
+							Aliases:   []string{"set"},
+							Usage:     "Configure one or more Solana chains",
+							ArgsUsage: "[key1=value1 key2=value2 ...] (values are parsed by the type of their field; a value may end in a type hint, one of :string, :int, :float, :bool, :duration or :json, and may reference another field of the current config, e.g. WSURL={{.RPC.URL}}; an empty value, null or __unset__ removes the field, e.g. TxTimeout=)",
+							Action:    client.chainAction(client.ConfigureSolanaChain),
+							Flags: []cli.Flag{
+								cli.BoolFlag{
+									Name:  "no-secret-warnings",
+									Usage: "do not warn about config fields which look like secrets being sent in plaintext",
+								},
+								cli.StringFlag{
+									Name:  "config-file",
+									Usage: "`FILE` containing a partial JSON config to apply, overridden by key=value arguments",
+								},
+								cli.BoolFlag{
+									Name:  "jsonc",
+									Usage: "allow // and /* */ comments and trailing commas in the JSON config",
+								},
+								cli.BoolFlag{
+									Name:  "strict-json",
+									Usage: "reject config JSON that repeats a key within the same object instead of keeping the last value",
+								},
+								cli.StringFlag{
+									Name:  "dump-request",
+									Usage: "`FILE` to write the JSON body of each request to before it is sent, e.g. for bug reports; config values are only redacted if --redact is set",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "build the request without sending it, writing its body to --dump-request or stdout",
+								},
+								cli.BoolFlag{
+									Name:  "check-version",
+									Usage: "warn if the node's build differs from the CLI's, as their chain config fields may not match",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringSliceFlag{
+									Name:  "id",
+									Usage: "chain ID, options: [mainnet, testnet, devnet, localnet], may be repeated to configure several chains",
+								},
+								cli.StringFlag{
+									Name:  "match",
+									Usage: "configure the chains whose IDs match the glob `PATTERN`, e.g. 'testnet-*', instead of --id",
+								},
+								cli.BoolFlag{
+									Name:  "force",
+									Usage: "skip the confirmation prompt of --match or several -id, like --yes",
+								},
+								cli.BoolFlag{
+									Name:  "interactive",
+									Usage: "confirm each changed config field before applying it",
+								},
+								cli.BoolFlag{
+									Name:  "yes, y",
+									Usage: "skip the confirmation prompt of --match or several -id and, with --interactive, apply all changes without prompting, as required without a terminal",
+								},
+								cli.BoolFlag{
+									Name:  "stdin",
+									Usage: "read additional key=value pairs from stdin, one per line",
+								},
+								cli.BoolFlag{
+									Name:  "changed-only",
+									Usage: "only show the config fields changed by the server",
+								},
+								cli.BoolFlag{
+									Name:  "assert-applied",
+									Usage: "fail, listing the fields, if the chain returned by the node lacks any of the changes sent, such as fields the node silently ignored",
+								},
+								cli.StringFlag{
+									Name:  "from-show",
+									Usage: "`ID` of a chain whose whole config to copy onto the chains, overridden by key=value arguments; the changes are shown instead of the updated chains",
+								},
+								cli.StringSliceFlag{
+									Name:  "reset-to-default",
+									Usage: "config `FIELD` to set back to the default of the node, may be repeated",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+								cli.BoolFlag{
+									Name:  "no-render",
+									Usage: "do not render the chain on success",
+								},
+							},
+						},
+						{
+							Name:   "diff",
+							Usage:  "Compare the Solana chains of two nodes",
+							Action: client.chainAction(client.DiffSolanaChains),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "from",
+									Usage: "URL of the node to compare from",
+								},
+								cli.StringFlag{
+									Name:  "to",
+									Usage: "URL of the node to compare to",
+								},
+								cli.StringFlag{
+									Name:  "file, f",
+									Usage: "text file holding the API email and password used to log in to both nodes",
+								},
+								cli.StringFlag{
+									Name:  "output, o",
+									Usage: "output format, options: [table, json, markdown]",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:  "terra",
+					Usage: "Commands for handling Terra chains",
+					Subcommands: cli.Commands{
+						{
+							Name:    "create",
+							Aliases: []string{"add"},
+							Usage:   "Create a new Terra chain",
+							Action:  client.chainAction(client.CreateTerraChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "no-family-check",
+									Usage: "do not warn if the config looks like the config of another chain family",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"rm", "remove"},
+							Usage:   "Delete a Terra chain",
+							Action:  client.chainAction(client.RemoveTerraChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"ls"},
+							Usage:   "List all Terra chains",
+							Action:  client.chainAction(client.IndexTerraChains),
+							Flags: []cli.Flag{
+								cli.BoolFlag{
+									Name:  "raw",
+									Usage: "print the response body of the node as received, bypassing formatting",
+								},
+								cli.BoolFlag{
+									Name:  "with-links",
+									Usage: "with --json or -o json, wrap the page in {\"data\": [...], \"links\": {...}} with the pagination links of the node",
+								},
+								cli.BoolFlag{
+									Name:  "wide",
+									Usage: "add the chain family, node count and a config hash, which is equal for chains with identical configs",
+								},
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "select",
+									Usage: "comma separated, possibly nested, fields to write as tab separated values, e.g. ID,Config.TxTimeout",
+								},
+								cli.BoolFlag{
+									Name:  "headers",
+									Usage: "write a header row with --select",
+								},
+								cli.BoolFlag{
+									Name:  "no-header",
+									Usage: "omit the field labels of the chain list, leaving only the values",
+								},
+								cli.IntFlag{
+									Name:  "page-size",
+									Usage: "number of chains to request per page (default 100)",
+								},
+								cli.StringFlag{
+									Name:  "jq",
+									Usage: "write a line of JSON for each chain, projected with a subset of jq: a path or an object of paths, e.g. '{id: .id, commitment: .config.Commitment}'",
+								},
+								cli.StringFlag{
+									Name:  "template-file",
+									Usage: "`FILE` containing a Go text/template to execute for each chain, e.g. {{.ID}}: {{.Config}}",
+								},
+								cli.BoolFlag{
+									Name:  "template-once",
+									Usage: "execute --template-file once over the list of all chains instead of for each chain",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
+								},
+							},
+						},
+						{
+							Name:    "configure",
+							Aliases: []string{"set"},
+							Usage:   "Configure a Terra chain",
+							Action:  client.chainAction(client.ConfigureTerraChain),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "proxy",
+									Usage: "`URL` of the proxy to send all requests through, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "chain ID",
+								},
+								cli.BoolFlag{
+									Name:  "show-secrets",
+									Usage: "show sensitive config values instead of redacting them",
+								},
+								cli.BoolFlag{
+									Name:  "sort-config-keys",
+									Usage: "sort the keys of chain configs recursively, for stable output (default true with --json)",
+								},
+								cli.StringFlag{
+									Name:  "format-config",
+									Usage: "how to format chain configs in tables, options: [pretty, minified, compact]; compact lists only the keys that are set",
+									Value: "pretty",
+								},
+								cli.StringSliceFlag{
+									Name:  "redact",
+									Usage: "case-insensitive pattern of the config keys to redact, matching the keys including its words, e.g. key matches APIKey but not Monkey; may be repeated (default: secret, key, password, token)",
 								},
 							},
 						},
@@ -27,7 +27,7 @@ func (p *BridgePresenter) RenderTable(rt RendererTable) error {
 		p.FriendlyConfirmations(),
 		p.OutgoingToken,
 	})
-	render("Bridge", table)
+	rt.render("Bridge", table)
 	return nil
 }
 
@@ -44,7 +44,7 @@ func (ps BridgePresenters) RenderTable(rt RendererTable) error {
 		})
 	}
 
-	render("Bridges", table)
+	rt.render("Bridges", table)
 	return nil
 }
 
@@ -0,0 +1,4650 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/pkg/errors"
+	clipkg "github.com/urfave/cli"
+	"go.uber.org/multierr"
+	"golang.org/x/term"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
+
+	solanadb "github.com/smartcontractkit/chainlink-solana/pkg/solana/db"
+	terradb "github.com/smartcontractkit/chainlink-terra/pkg/terra/db"
+
+	evmtypes "github.com/smartcontractkit/chainlink/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web"
+)
+
+// redactedValue replaces the values of sensitive chain config fields.
+const redactedValue = "***"
+
+// defaultSecretPatterns are the case-insensitive key patterns identifying
+// chain config fields whose values are redacted when rendered, see
+// isSecretKey.
+var defaultSecretPatterns = []string{"secret", "key", "password", "token"}
+
+// chainHeaders are the fields of the chain presenters of every family.
+var chainHeaders = []string{"ID", "Enabled", "Config", "Created", "Updated"}
+
+// chainColumnTypes right-justifies the Enabled field of chainHeaders.
+var chainColumnTypes = []columnType{columnText, columnNumeric, columnText, columnText, columnText}
+
+// setChainRenderOpts applies the chain rendering flags set on c to the
+// client's renderer.
+func (cli *Client) setChainRenderOpts(c *clipkg.Context) {
+	switch r := cli.Renderer.(type) {
+	case RendererTable:
+		r.ShowSecrets = c.Bool("show-secrets")
+		r.NoHeader = c.Bool("no-header")
+		r.SortConfigKeys = c.Bool("sort-config-keys")
+		r.ConfigFormat = c.String("format-config")
+		if c.IsSet("redact") {
+			r.SecretPatterns = c.StringSlice("redact")
+		}
+		cli.Renderer = r
+	case RendererJSON:
+		// JSON is usually compared or diffed, so keys are sorted by default.
+		// Redacted configs are generic objects, so they are sorted as well.
+		showSecrets := c.Bool("show-secrets")
+		if !showSecrets || !c.IsSet("sort-config-keys") || c.Bool("sort-config-keys") {
+			sr := sortedKeysRenderer{RendererJSON: r}
+			if !showSecrets {
+				sr.SecretPatterns = secretPatternsFlag(c)
+			}
+			cli.Renderer = sr
+		}
+	}
+}
+
+// sortedKeysRenderer renders JSON with the keys of every object sorted, so
+// that the output is stable however the presenters order their fields.
+// Arrays keep their order. Unless SecretPatterns is nil, the chain configs
+// are redacted like in tables.
+type sortedKeysRenderer struct {
+	RendererJSON
+	SecretPatterns []string
+}
+
+// Render writes v as JSON with sorted object keys.
+func (r sortedKeysRenderer) Render(v interface{}, headers ...string) error {
+	generic, err := toGenericConfig(v)
+	if err != nil {
+		return err
+	}
+	if r.SecretPatterns != nil {
+		redactChainConfigs(generic, r.SecretPatterns)
+	}
+	return r.RendererJSON.Render(generic, headers...)
+}
+
+// redactChainConfigs redacts, in place, the chain configs found anywhere in
+// v, a value decoded by toGenericConfig, under the config key of the chain
+// presenters.
+func redactChainConfigs(v interface{}, patterns []string) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, val := range typed {
+			if k == "config" {
+				typed[k] = redactValue(val, patterns)
+				continue
+			}
+			redactChainConfigs(val, patterns)
+		}
+	case []interface{}:
+		for _, val := range typed {
+			redactChainConfigs(val, patterns)
+		}
+	}
+}
+
+// chainConfigFormats are the values of --format-config: pretty prints chain
+// configs indented, minified on a single line, and compact only lists the
+// keys which are set.
+var chainConfigFormats = []string{"pretty", "minified", "compact"}
+
+// checkChainConfigFormat validates the --format-config value format.
+func checkChainConfigFormat(format string) error {
+	for _, f := range chainConfigFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return errors.Errorf("invalid --format-config '%s', options: [%s]", format, strings.Join(chainConfigFormats, ", "))
+}
+
+// formatChainConfig formats a chain config for table output as set by
+// rt.ConfigFormat, redacting sensitive fields unless rt.ShowSecrets is set.
+func (rt RendererTable) formatChainConfig(config interface{}) (string, error) {
+	var err error
+	if !rt.ShowSecrets {
+		if config, err = redactConfig(config, rt.secretPatterns()); err != nil {
+			return "", err
+		}
+	} else if rt.SortConfigKeys {
+		// Generic objects are marshaled with sorted keys
+		if config, err = toGenericConfig(config); err != nil {
+			return "", err
+		}
+	}
+	format := rt.ConfigFormat
+	if rt.Markdown && format != "compact" {
+		format = "minified"
+	}
+	switch format {
+	case "compact":
+		return compactChainConfig(config)
+	case "minified":
+		b, err := json.Marshal(config)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	// NOTE: it's impossible to omitempty null fields when serializing to JSON: https://github.com/golang/go/issues/11939
+	b, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// compactChainConfig summarizes config as its sorted top-level keys which are
+// set, e.g. "Commitment, TxTimeout", or "(empty)" if none are.
+func compactChainConfig(config interface{}) (string, error) {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return "", err
+	}
+	m, ok := generic.(map[string]interface{})
+	if !ok {
+		return formatConfigValue(generic), nil
+	}
+	var keys []string
+	for k, v := range m {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "(empty)", nil
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", "), nil
+}
+
+// formatConfigField formats the value of the config field at the dotted path
+// key for table output, redacting it if sensitive unless rt.ShowSecrets is set.
+func (rt RendererTable) formatConfigField(key string, v interface{}) string {
+	if !rt.ShowSecrets && v != nil && isSecretKey(key, rt.secretPatterns()) {
+		return redactedValue
+	}
+	return formatConfigValue(v)
+}
+
+func (rt RendererTable) secretPatterns() []string {
+	if rt.SecretPatterns == nil {
+		return defaultSecretPatterns
+	}
+	return rt.SecretPatterns
+}
+
+// chainNameKeys are the conventional config keys holding a human-friendly
+// chain name, in order of precedence.
+var chainNameKeys = []string{"Name", "name", "DisplayName", "displayName"}
+
+// chainDisplayName returns "ID (Name)" for the chain with chainID, the name
+// being the first of chainNameKeys set to a string in its config, or just the
+// ID if there is none.
+func chainDisplayName(chainID string, config interface{}) string {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return chainID
+	}
+	m, _ := generic.(map[string]interface{})
+	for _, k := range chainNameKeys {
+		if name, ok := m[k].(string); ok && strings.TrimSpace(name) != "" {
+			return fmt.Sprintf("%s (%s)", chainID, name)
+		}
+	}
+	return chainID
+}
+
+// redactConfig returns a generic copy of config, with the value of every key
+// matching one of patterns replaced by redactedValue. Null values are kept
+// as-is, since there is nothing to hide.
+func redactConfig(config interface{}, patterns []string) (interface{}, error) {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return redactValue(generic, patterns), nil
+}
+
+func redactValue(v interface{}, patterns []string) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, val := range typed {
+			if val != nil && isSecretKey(k, patterns) {
+				typed[k] = redactedValue
+				continue
+			}
+			typed[k] = redactValue(val, patterns)
+		}
+	case []interface{}:
+		for i, val := range typed {
+			typed[i] = redactValue(val, patterns)
+		}
+	}
+	return v
+}
+
+// nonSecretConfigKeys are config fields whose names match the default secret
+// patterns, but which hold no secret themselves: KeySpecific holds the config
+// overrides of each key, whose fields are checked on their own.
+var nonSecretConfigKeys = map[string]bool{"KeySpecific": true}
+
+// isSecretKey returns whether the config field at the dotted path key is
+// sensitive by patterns: whether the words of its name include the words of a
+// pattern, case-insensitively, the last one possibly plural. So key matches
+// APIKey and private_keys, but not Monkey. nonSecretConfigKeys never match.
+func isSecretKey(key string, patterns []string) bool {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	if nonSecretConfigKeys[key] {
+		return false
+	}
+	words := keyWords(key)
+	for _, p := range patterns {
+		if containsKeyWords(words, keyWords(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKeyWords returns whether words include the run of pattern words,
+// ignoring a plural s on the last of them.
+func containsKeyWords(words, pattern []string) bool {
+	if len(pattern) == 0 {
+		return false
+	}
+	last := len(pattern) - 1
+	for i := 0; i+len(pattern) <= len(words); i++ {
+		run := words[i : i+len(pattern)]
+		if strings.Join(run[:last], " ") == strings.Join(pattern[:last], " ") && strings.TrimSuffix(run[last], "s") == strings.TrimSuffix(pattern[last], "s") {
+			return true
+		}
+	}
+	return false
+}
+
+// keyWords splits the config key name into its lowercase words, at
+// separators and at camel case boundaries, keeping acronyms together: the
+// words of APIKey are api and key.
+func keyWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// toGenericConfig round trips config through JSON, returning it as nested
+// maps, slices and scalars. Numbers are preserved as json.Number.
+func toGenericConfig(config interface{}) (interface{}, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var generic interface{}
+	if err = d.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// stdout returns the writer of the client's renderer, to which chain commands
+// write all of their output, so that it is redirected and captured along with
+// rendered output. It falls back to os.Stdout for renderers without one.
+func (cli *Client) stdout() io.Writer {
+	var w io.Writer
+	switch r := cli.Renderer.(type) {
+	case RendererTable:
+		w = r.Writer
+	case RendererJSON:
+		w = r.Writer
+	case sortedKeysRenderer:
+		w = r.Writer
+	}
+	if w == nil {
+		return os.Stdout
+	}
+	return w
+}
+
+// outputRenderer returns the renderer selected by the --output flag on c,
+// falling back to the client's renderer.
+func (cli *Client) outputRenderer(c *clipkg.Context) (Renderer, error) {
+	switch format := c.String("output"); format {
+	case "", "table":
+		return cli.Renderer, nil
+	case "json":
+		return RendererJSON{Writer: cli.stdout(), Compact: cli.CompactJSON}, nil
+	case "markdown":
+		rt, ok := cli.Renderer.(RendererTable)
+		if !ok {
+			rt = RendererTable{Writer: cli.stdout()}
+		}
+		rt.Markdown = true
+		return rt, nil
+	default:
+		return nil, errors.Errorf("unsupported output format %q (options: table, json, markdown)", format)
+	}
+}
+
+// useOutputRenderer makes the renderer of --output the client's renderer, for
+// the commands which render through cli.Render on many paths, so that they
+// all honor it. The returned func restores the previous renderer, for the
+// command to defer.
+func (cli *Client) useOutputRenderer(c *clipkg.Context) (restore func(), err error) {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return nil, usageError(c, err)
+	}
+	orig := cli.Renderer
+	cli.Renderer = r
+	return func() { cli.Renderer = orig }, nil
+}
+
+// stderr returns the writer of the client's status messages and warnings,
+// which are kept out of the rendered output of chain commands.
+func (cli *Client) stderr() io.Writer {
+	if cli.Stderr == nil {
+		return os.Stderr
+	}
+	return cli.Stderr
+}
+
+// commandWarnings collects the non-fatal warnings of a command. In human mode
+// they are written to stderr as they occur. With --output=json they are kept
+// instead, and attached to the next output rendered by renderer, so that
+// automation sees them.
+type commandWarnings struct {
+	stderr  io.Writer
+	collect bool
+	msgs    []string
+}
+
+// newCommandWarnings returns the warnings collector for the command run on c,
+// writing the warnings which are not collected to stderr.
+func newCommandWarnings(c *clipkg.Context, stderr io.Writer) *commandWarnings {
+	return &commandWarnings{stderr: stderr, collect: c.String("output") == "json"}
+}
+
+// Write implements io.Writer, so that the collector can be passed to the
+// helpers which write warnings. Each line written is a warning, collected
+// without its WARNING: prefix.
+func (w *commandWarnings) Write(p []byte) (int, error) {
+	if !w.collect {
+		return w.stderr.Write(p)
+	}
+	for _, line := range strings.Split(string(p), "\n") {
+		if line = strings.TrimPrefix(strings.TrimSpace(line), "WARNING: "); line != "" {
+			w.msgs = append(w.msgs, line)
+		}
+	}
+	return len(p), nil
+}
+
+// flush writes the warnings not attached to any output to stderr, so that
+// they are not lost when a command fails before rendering.
+func (w *commandWarnings) flush() {
+	for _, msg := range w.msgs {
+		fmt.Fprintln(w.stderr, "WARNING: "+msg)
+	}
+	w.msgs = nil
+}
+
+// renderer wraps r to attach the warnings collected so far to what it
+// renders.
+func (w *commandWarnings) renderer(r Renderer) Renderer {
+	if !w.collect {
+		return r
+	}
+	return warningsRenderer{Renderer: r, warnings: w}
+}
+
+// warningsRenderer renders with a "warnings" array added to the output
+// object. Outputs which are not objects are nested under "data". Outputs
+// without warnings are rendered as they are.
+type warningsRenderer struct {
+	Renderer
+	warnings *commandWarnings
+}
+
+// Render renders v with the warnings collected since the last render.
+func (r warningsRenderer) Render(v interface{}, headers ...string) error {
+	if len(r.warnings.msgs) == 0 {
+		return r.Renderer.Render(v, headers...)
+	}
+	generic, err := toGenericConfig(v)
+	if err != nil {
+		return err
+	}
+	obj, ok := generic.(map[string]interface{})
+	if !ok {
+		obj = map[string]interface{}{"data": generic}
+	}
+	obj["warnings"] = r.warnings.msgs
+	r.warnings.msgs = nil
+	return r.Renderer.Render(obj, headers...)
+}
+
+// flattenConfig flattens a chain config into a map of dotted key paths to
+// scalar values. Array elements are keyed by their index, and empty objects
+// and arrays are kept as leaves so that they are not lost.
+func flattenConfig(config interface{}) (map[string]interface{}, error) {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenValue("", generic, flat)
+	return flat, nil
+}
+
+// writeFieldsJSON writes config to w as a flat JSON object, keyed by the
+// dotted paths of its values, with array elements keyed by index.
+func writeFieldsJSON(w io.Writer, config interface{}) error {
+	flat, err := flattenConfig(config)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(flat)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+func flattenValue(prefix string, v interface{}, flat map[string]interface{}) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 && prefix != "" {
+			flat[prefix] = typed
+		}
+		for k, val := range typed {
+			flattenValue(join(k), val, flat)
+		}
+	case []interface{}:
+		if len(typed) == 0 && prefix != "" {
+			flat[prefix] = typed
+		}
+		for i, val := range typed {
+			flattenValue(join(strconv.Itoa(i)), val, flat)
+		}
+	default:
+		flat[prefix] = v
+	}
+}
+
+// envVarUnsafe matches the characters which are not allowed in shell
+// variable names.
+var envVarUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// configEnvName returns the shell variable name of the config field at the
+// dotted path key: CONFIG_ followed by the path uppercased, with dots and any
+// other characters not allowed in variable names replaced by underscores, e.g.
+// RPC.URL is CONFIG_RPC_URL and Nodes.0.Name is CONFIG_NODES_0_NAME.
+func configEnvName(key string) string {
+	return "CONFIG_" + strings.ToUpper(envVarUnsafe.ReplaceAllString(key, "_"))
+}
+
+// shellQuote single-quotes s for POSIX shells, so that it is taken literally.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// envPrefixPattern matches the valid values of --env-prefix, which must start
+// a shell variable name.
+var envPrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checkEnvPrefix returns an error if prefix cannot start a shell variable
+// name. The empty prefix is valid.
+func checkEnvPrefix(prefix string) error {
+	if prefix != "" && !envPrefixPattern.MatchString(prefix) {
+		return errors.Errorf("invalid --env-prefix '%s': must start with a letter or underscore, followed by letters, digits or underscores", prefix)
+	}
+	return nil
+}
+
+// writeConfigEnv writes config to w as shell variable assignments, one per
+// line sorted by name, suitable for eval or source. Fields are flattened as by
+// flattenConfig and named by configEnvName, after prefix, so that the configs
+// of several chains can be sourced together. Strings are written as they are,
+// other values as JSON and nulls as empty strings, all single-quoted. The
+// values of sensitive keys are redacted unless showSecrets is set. Fields
+// whose names collide once flattened are an error, as one would be lost.
+func writeConfigEnv(w io.Writer, config interface{}, prefix string, showSecrets bool, secretPatterns []string) error {
+	flat, err := flattenConfig(config)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(flat))
+	for key := range flat {
+		paths = append(paths, key)
+	}
+	sort.Strings(paths)
+	keys := map[string]string{}
+	names := make([]string, 0, len(flat))
+	for _, key := range paths {
+		name := prefix + configEnvName(key)
+		if other, ok := keys[name]; ok {
+			return errors.Errorf("config fields %s and %s are both named %s", other, key, name)
+		}
+		keys[name] = key
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := keys[name]
+		value := ""
+		switch v := flat[key]; {
+		case v == nil:
+		case !showSecrets && isSecretKey(key, secretPatterns):
+			value = redactedValue
+		default:
+			value = formatScalar(v)
+		}
+		if _, err = fmt.Fprintf(w, "%s=%s\n", name, shellQuote(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigFieldDiff is a single differing field between two chain configs.
+type ConfigFieldDiff struct {
+	Key  string      `json:"key"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// diffConfigs compares two chain configs field by field, returning the
+// differing fields ordered by key.
+func diffConfigs(from, to interface{}) ([]ConfigFieldDiff, error) {
+	fromFlat, err := flattenConfig(from)
+	if err != nil {
+		return nil, err
+	}
+	toFlat, err := flattenConfig(to)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]struct{}{}
+	for k := range fromFlat {
+		keys[k] = struct{}{}
+	}
+	for k := range toFlat {
+		keys[k] = struct{}{}
+	}
+	var diffs []ConfigFieldDiff
+	for k := range keys {
+		f, t := fromFlat[k], toFlat[k]
+		if !reflect.DeepEqual(f, t) {
+			diffs = append(diffs, ConfigFieldDiff{Key: k, From: f, To: t})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs, nil
+}
+
+// unappliedConfigFields returns the keys of the fields changed from the config
+// before to sent which don't have their sent value in the config returned by
+// the server, ordered by key, so that silently ignored changes are caught.
+// Fields which were not changed are not checked.
+func unappliedConfigFields(before, sent, returned interface{}) ([]string, error) {
+	intended, err := diffConfigs(before, sent)
+	if err != nil {
+		return nil, err
+	}
+	mismatched, err := diffConfigs(sent, returned)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]struct{}{}
+	for _, f := range intended {
+		changed[f.Key] = struct{}{}
+	}
+	var keys []string
+	for _, f := range mismatched {
+		if _, ok := changed[f.Key]; ok {
+			keys = append(keys, f.Key)
+		}
+	}
+	return keys, nil
+}
+
+// formatConfigValue formats a generic config value for human readable output.
+func formatConfigValue(v interface{}) string {
+	switch typed := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(typed)
+	case json.Number:
+		return typed.String()
+	default:
+		b, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprintf("%v", typed)
+		}
+		return string(b)
+	}
+}
+
+// ChainConfigDiff implements TableRenderer for the differing config fields
+// of a chain.
+type ChainConfigDiff struct {
+	ID     string            `json:"id"`
+	Fields []ConfigFieldDiff `json:"fields"`
+}
+
+// RenderTable implements TableRenderer
+func (p ChainConfigDiff) RenderTable(rt RendererTable) error {
+	if len(p.Fields) == 0 {
+		_, err := fmt.Fprintf(rt, "No config changes for chain %s\n", p.ID)
+		return err
+	}
+	table := rt.newTable([]string{"Config", "Old Value", "New Value"})
+	for _, f := range p.Fields {
+		table.Append([]string{
+			f.Key,
+			rt.formatConfigField(f.Key, f.From),
+			rt.formatConfigField(f.Key, f.To),
+		})
+	}
+	rt.render(fmt.Sprintf("Config Changes for chain %s", p.ID), table)
+	return nil
+}
+
+// EffectiveChainConfig implements TableRenderer for the config fields of a
+// created chain which the node filled in or normalized, From holding the
+// submitted values and To the effective ones.
+type EffectiveChainConfig struct {
+	ID     string            `json:"id"`
+	Fields []ConfigFieldDiff `json:"fields"`
+}
+
+// RenderTable implements TableRenderer
+func (p EffectiveChainConfig) RenderTable(rt RendererTable) error {
+	if len(p.Fields) == 0 {
+		_, err := fmt.Fprintf(rt, "The node applied the config of chain %s as submitted\n", p.ID)
+		return err
+	}
+	table := rt.newTable([]string{"Config", "Submitted", "Effective"})
+	for _, f := range p.Fields {
+		table.Append([]string{
+			f.Key,
+			rt.formatConfigField(f.Key, f.From),
+			rt.formatConfigField(f.Key, f.To),
+		})
+	}
+	rt.render(fmt.Sprintf("Effective Config of chain %s", p.ID), table)
+	return nil
+}
+
+// RoundTripIssue is a config field which changes when the config returned
+// by the node is decoded and encoded again by the CLI, as configure does.
+type RoundTripIssue struct {
+	Key   string      `json:"key"`
+	Node  interface{} `json:"node"`
+	CLI   interface{} `json:"cli"`
+	Issue string      `json:"issue"`
+}
+
+// ConfigRoundTrip implements TableRenderer for the config fields of a chain
+// which do not round-trip through the CLI.
+type ConfigRoundTrip struct {
+	ID     string           `json:"id"`
+	Issues []RoundTripIssue `json:"issues"`
+}
+
+// RenderTable implements TableRenderer
+func (p ConfigRoundTrip) RenderTable(rt RendererTable) error {
+	if len(p.Issues) == 0 {
+		_, err := fmt.Fprintf(rt, "The config of chain %s round-trips\n", p.ID)
+		return err
+	}
+	table := rt.newTable([]string{"Config", "Node", "CLI", "Issue"})
+	for _, issue := range p.Issues {
+		table.Append([]string{
+			issue.Key,
+			rt.formatConfigField(issue.Key, issue.Node),
+			rt.formatConfigField(issue.Key, issue.CLI),
+			issue.Issue,
+		})
+	}
+	rt.render(fmt.Sprintf("Config Round-Trip of chain %s", p.ID), table)
+	return nil
+}
+
+// roundTripIssues decodes the config raw, as returned by the node, into the
+// config struct cfg and encodes it again, returning the fields which do not
+// survive: fields dropped as unknown to the CLI, values coerced to another
+// type, numbers losing precision, and values encoded differently, such as
+// durations. Null and absent fields are equivalent. A change in the order of
+// the top-level keys is reported as an issue of the key "(order)".
+func roundTripIssues(raw json.RawMessage, cfg interface{}) ([]RoundTripIssue, error) {
+	decoded := reflect.New(reflect.TypeOf(cfg))
+	if err := json.Unmarshal(raw, decoded.Interface()); err != nil {
+		return nil, errors.Wrap(err, "failed to decode config")
+	}
+	encoded, err := json.Marshal(decoded.Elem().Interface())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode config")
+	}
+	before, err := flattenConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flattenConfig(json.RawMessage(encoded))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var issues []RoundTripIssue
+	for _, k := range keys {
+		from, to := before[k], after[k]
+		var issue string
+		switch _, known := after[k]; {
+		case from == nil && to == nil, reflect.DeepEqual(from, to):
+			continue
+		case to == nil && !known:
+			issue = "dropped: unknown to the CLI"
+		case to == nil:
+			issue = "dropped: not decoded by the CLI"
+		case from == nil:
+			issue = "added by the CLI"
+		case jsonKind(from) != jsonKind(to):
+			issue = fmt.Sprintf("type changed from %s to %s", jsonKind(from), jsonKind(to))
+		case jsonKind(from) == "number":
+			issue = "number precision changed"
+		default:
+			issue = "value re-encoded"
+		}
+		issues = append(issues, RoundTripIssue{Key: k, Node: from, CLI: to, Issue: issue})
+	}
+
+	fromOrder, toOrder := topLevelKeys(raw, after), topLevelKeys(encoded, before)
+	if strings.Join(fromOrder, ",") != strings.Join(toOrder, ",") {
+		issues = append(issues, RoundTripIssue{Key: "(order)", Node: strings.Join(fromOrder, ", "), CLI: strings.Join(toOrder, ", "), Issue: "top-level keys reordered"})
+	}
+	return issues, nil
+}
+
+// jsonKind returns the JSON type of the generic config value v.
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// topLevelKeys returns the top-level keys of the JSON object b in order, of
+// those which are set in both b and the flattened config other.
+func topLevelKeys(b []byte, other map[string]interface{}) []string {
+	d := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := d.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for d.More() {
+		tok, err := d.Token()
+		if err != nil {
+			return keys
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err = d.Decode(&value); err != nil {
+			return keys
+		}
+		if string(value) == "null" {
+			continue
+		}
+		for k, v := range other {
+			if v != nil && (k == key || strings.HasPrefix(k, key+".")) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// chainFamilies maps each chain family to its chains endpoint.
+var chainFamilies = map[string]string{
+	"evm":    "/v2/chains/evm",
+	"solana": "/v2/chains/solana",
+	"terra":  "/v2/chains/terra",
+}
+
+// chainPageSizes are the default page sizes of the chain list commands per
+// family, overridden by --page-size. Nodes typically have a handful of EVM
+// chains, so those use the node's default of 25, while Solana and Terra
+// chains are cheap to add and are requested in larger pages to save
+// round-trips.
+var chainPageSizes = map[string]int{
+	"evm":    web.PaginationDefault,
+	"solana": 100,
+	"terra":  100,
+}
+
+// chainsPageURI returns the URI of the chains of family, with the page size
+// given by --page-size, or the family's default.
+func chainsPageURI(c *clipkg.Context, family string) (string, error) {
+	size := chainPageSizes[family]
+	if c.IsSet("page-size") {
+		if size = c.Int("page-size"); size < 1 {
+			return "", usageError(c, errors.Errorf("invalid --page-size %d: must be at least 1", size))
+		}
+	}
+	return fmt.Sprintf("%s?size=%d", chainFamilies[family], size), nil
+}
+
+// enabledFilter returns the state of chains to list of --enabled, or nil if
+// it is not set.
+//
+// The filter is pushed to the node as the 'enabled' query parameter, to
+// transfer fewer chains. Nodes which do not support it ignore the parameter,
+// so the chains are filtered client-side as well.
+func enabledFilter(c *clipkg.Context) (*bool, error) {
+	if !c.IsSet("enabled") {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(c.String("enabled"))
+	if err != nil {
+		return nil, usageError(c, errors.Errorf("invalid --enabled '%s': must be true or false", c.String("enabled")))
+	}
+	return &enabled, nil
+}
+
+// parseBoolLiteral parses the boolean literals true, false, yes, no, on, off,
+// 1 and 0, ignoring case. Unlike strconv.ParseBool, abbreviations such as 't'
+// are rejected, as a typo should not silently pick a state.
+func parseBoolLiteral(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, errors.Errorf("'%s' is not a boolean, must be one of true, false, yes, no, on, off, 1 or 0", s)
+}
+
+// withQueryParam returns requestURI with the query parameter key set to value.
+func withQueryParam(requestURI, key, value string) (string, error) {
+	uri, err := url.Parse(requestURI)
+	if err != nil {
+		return "", err
+	}
+	q := uri.Query()
+	q.Set(key, value)
+	uri.RawQuery = q.Encode()
+	return uri.String(), nil
+}
+
+// pageCursorPrefix marks the cursors standing in for page numbers, for nodes
+// which do not paginate by cursor.
+const pageCursorPrefix = "page:"
+
+// withPageCursor returns requestURI continuing after the cursor of --after,
+// or before the cursor of --before. Cursors from nodes paginating by page
+// number only select that page.
+func withPageCursor(c *clipkg.Context, requestURI string) (string, error) {
+	param, cursor := "after", c.String("after")
+	if cursor == "" {
+		param, cursor = "before", c.String("before")
+	}
+	if cursor == "" {
+		return requestURI, nil
+	}
+	uri, err := url.Parse(requestURI)
+	if err != nil {
+		return "", err
+	}
+	q := uri.Query()
+	if strings.HasPrefix(cursor, pageCursorPrefix) {
+		page, err := strconv.Atoi(strings.TrimPrefix(cursor, pageCursorPrefix))
+		if err != nil || page < 1 {
+			return "", usageError(c, errors.Errorf("invalid --%s cursor '%s'", param, cursor))
+		}
+		q.Set("page", strconv.Itoa(page))
+	} else {
+		q.Set(param, cursor)
+	}
+	uri.RawQuery = q.Encode()
+	return uri.String(), nil
+}
+
+// pageCursor returns the cursor of the pagination link named name, read from
+// its param query parameter, or derived from its page number if the node does
+// not paginate by cursor. It is empty if there is no such link.
+func pageCursor(links jsonapi.Links, name, param string) (cursor string, isPage bool) {
+	link, ok := links[name]
+	if !ok || link.Href == "" {
+		return "", false
+	}
+	uri, err := url.Parse(link.Href)
+	if err != nil {
+		return "", false
+	}
+	q := uri.Query()
+	if cursor = q.Get(param); cursor != "" {
+		return cursor, false
+	}
+	if page := q.Get("page"); page != "" {
+		return pageCursorPrefix + page, true
+	}
+	return "", false
+}
+
+// renderPageWithCursors renders the page of requestURI into model, then
+// writes the cursors of the neighbouring pages to w, for use with --after
+// and --before. They are written if the node paginates by cursor, or if the
+// page was itself selected with a cursor.
+func (cli *Client) renderPageWithCursors(c *clipkg.Context, w io.Writer, requestURI string, model interface{}) error {
+	links, err := cli.fetchPage(requestURI, c.Int("page"), model)
+	if err != nil {
+		return err
+	}
+	if err = cli.Render(model); err != nil {
+		return err
+	}
+	next, nextIsPage := pageCursor(links, web.KeyNextLink, "after")
+	prev, prevIsPage := pageCursor(links, web.KeyPreviousLink, "before")
+	if c.String("after") == "" && c.String("before") == "" && (next == "" || nextIsPage) && (prev == "" || prevIsPage) {
+		return nil
+	}
+	if c.String("before") != "" {
+		if prev != "" {
+			fmt.Fprintf(w, "Previous cursor: %s\n", prev)
+		} else {
+			fmt.Fprintln(w, "No previous pages")
+		}
+	}
+	if next != "" {
+		fmt.Fprintf(w, "Next cursor: %s\n", next)
+	} else {
+		fmt.Fprintln(w, "No more pages")
+	}
+	return nil
+}
+
+// ChainFamilyCount is the number of chains of a family. Count is nil if the
+// family's endpoint was unavailable.
+type ChainFamilyCount struct {
+	Family string `json:"family"`
+	Count  *int   `json:"count"`
+}
+
+// ChainCountsPresenter implements TableRenderer for the number of chains per
+// family.
+type ChainCountsPresenter struct {
+	Families []ChainFamilyCount `json:"families"`
+	Total    int                `json:"total"`
+}
+
+// RenderTable implements TableRenderer
+func (p ChainCountsPresenter) RenderTable(rt RendererTable) error {
+	table := rt.newTable([]string{"Family", "Chains"})
+	for _, f := range p.Families {
+		count := "n/a"
+		if f.Count != nil {
+			count = strconv.Itoa(*f.Count)
+		}
+		table.Append([]string{f.Family, count})
+	}
+	table.SetFooter([]string{"Total", strconv.Itoa(p.Total)})
+	rt.render("Chains", table)
+	return nil
+}
+
+// PingNode measures the latency of the node API by requesting its build info,
+// --count times, --interval apart, and reports the round-trip time and node
+// version of each reply, then their min/avg/max. It fails if the node never
+// replies.
+func (cli *Client) PingNode(c *clipkg.Context) error {
+	count := c.Int("count")
+	if count < 1 {
+		return cli.errorOut(errors.Errorf("invalid --count %d: must be at least 1", count))
+	}
+	return cli.errorOut(cli.pingNode(cli.stdout(), count, c.Duration("interval")))
+}
+
+func (cli *Client) pingNode(w io.Writer, count int, interval time.Duration) error {
+	var rtts []time.Duration
+	var lastErr error
+	for i := 1; i <= count; i++ {
+		if i > 1 {
+			time.Sleep(interval)
+		}
+		start := time.Now()
+		info, err := cli.fetchNodeBuild()
+		rtt := time.Since(start)
+		if err != nil {
+			lastErr = err
+			fmt.Fprintf(w, "Request %d failed: %v\n", i, err)
+			continue
+		}
+		rtts = append(rtts, rtt)
+		fmt.Fprintf(w, "Reply %d from node %s@%s: time=%s\n", i, info.Version, info.CommitSHA, rtt.Round(time.Microsecond))
+	}
+	fmt.Fprintf(w, "%d sent, %d received", count, len(rtts))
+	if len(rtts) == 0 {
+		fmt.Fprintln(w)
+		return errors.Wrap(lastErr, "node is unreachable")
+	}
+	min, max, total := rtts[0], rtts[0], time.Duration(0)
+	for _, rtt := range rtts {
+		if rtt < min {
+			min = rtt
+		}
+		if rtt > max {
+			max = rtt
+		}
+		total += rtt
+	}
+	avg := total / time.Duration(len(rtts))
+	fmt.Fprintf(w, ", round-trip min/avg/max = %s/%s/%s\n", min.Round(time.Microsecond), avg.Round(time.Microsecond), max.Round(time.Microsecond))
+	return nil
+}
+
+// CountChains counts the chains of the families given with --family, or of
+// every family with --all-families. A family whose endpoint is unavailable,
+// e.g. not found on older nodes or failing with a server error, is reported
+// as n/a with a warning rather than failing the whole count. Authentication
+// errors still fail it, as they affect every family.
+func (cli *Client) CountChains(c *clipkg.Context) error {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	warn := newCommandWarnings(c, cli.stderr())
+	defer warn.flush()
+	r = warn.renderer(r)
+	families := c.StringSlice("family")
+	if c.Bool("all-families") {
+		families = nil
+		for family := range chainFamilies {
+			families = append(families, family)
+		}
+		sort.Strings(families)
+	}
+	if len(families) == 0 {
+		return cli.errorOut(errors.New("must pass --family or --all-families"))
+	}
+
+	var p ChainCountsPresenter
+	for _, family := range families {
+		uri, ok := chainFamilies[family]
+		if !ok {
+			return cli.errorOut(errors.Errorf("unknown chain family %q (options: evm, solana, terra)", family))
+		}
+		fc := ChainFamilyCount{Family: family}
+		count, err := cli.countResources(uri)
+		switch {
+		case err == nil:
+			fc.Count = &count
+			p.Total += count
+		case exitCode(err) == ExitCodeAuth:
+			// Rejected credentials fail the count of every family alike
+			return cli.errorOut(errors.Wrapf(err, "failed to count %s chains", family))
+		default:
+			fmt.Fprintf(warn, "WARNING: failed to count %s chains: %v\n", family, err)
+		}
+		p.Families = append(p.Families, fc)
+	}
+	return cli.errorOut(r.Render(&p))
+}
+
+// newChainPresenters returns a pointer to an empty slice of the chain
+// presenters of family, to list its chains into.
+func newChainPresenters(family string) interface{} {
+	switch family {
+	case "evm":
+		return &EVMChainPresenters{}
+	case "solana":
+		return &SolanaChainPresenters{}
+	default:
+		return &TerraChainPresenters{}
+	}
+}
+
+// FamilyChain is a chain listed along with those of the other families. The
+// chain presenters omit their ID from JSON, hence its own field. Family is
+// empty when the chains are grouped by family.
+type FamilyChain struct {
+	ID     string            `json:"id"`
+	Family string            `json:"family,omitempty"`
+	Chain  chainRowPresenter `json:"chain"`
+}
+
+// FamilyChainsPresenter implements TableRenderer for the chains of every
+// family.
+type FamilyChainsPresenter []FamilyChain
+
+// RenderTable implements TableRenderer
+func (ps FamilyChainsPresenter) RenderTable(rt RendererTable) error {
+	headers := append(append([]string{}, chainHeaders...), "Family")
+	types := append(append([]columnType{}, chainColumnTypes...), columnText)
+	rows := [][]string{}
+	var failed rowErrors
+	for _, p := range ps {
+		row, err := p.Chain.Row(rt)
+		if err != nil {
+			failed.add(p.ID, err)
+			continue
+		}
+		rows = append(rows, append(row, p.Family))
+	}
+	rt.renderTypedList(headers, types, rows)
+	return failed.report(rt)
+}
+
+// ChainsByFamilyPresenter implements TableRenderer for the chains of every
+// family with --group-by-family, keyed by family.
+type ChainsByFamilyPresenter map[string][]FamilyChain
+
+// RenderTable implements TableRenderer
+func (p ChainsByFamilyPresenter) RenderTable(rt RendererTable) error {
+	var families []string
+	for family := range p {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	var failed rowErrors
+	for i, family := range families {
+		if i > 0 {
+			fmt.Fprintln(rt)
+		}
+		fmt.Fprintf(rt, "%s (%d)\n", chainFamilyNames[family], len(p[family]))
+		var rows [][]string
+		for _, chain := range p[family] {
+			row, err := chain.Chain.Row(rt)
+			if err != nil {
+				failed.add(chain.ID, err)
+				continue
+			}
+			rows = append(rows, row)
+		}
+		rt.renderTypedList(chainHeaders, chainColumnTypes, rows)
+		fmt.Fprintln(rt)
+	}
+	return failed.report(rt)
+}
+
+// ListChains lists the chains of every family in one table, or in a section
+// per family with --group-by-family. Families without chains are omitted.
+func (cli *Client) ListChains(c *clipkg.Context) error {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var families []string
+	for family := range chainFamilies {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+
+	var all FamilyChainsPresenter
+	grouped := ChainsByFamilyPresenter{}
+	for _, family := range families {
+		chains := newChainPresenters(family)
+		if err = cli.getAllPages(cli.HTTP, chainFamilies[family], chains); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to list %s chains", chainFamilyNames[family]))
+		}
+		rv := reflect.ValueOf(chains).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			chain := rv.Index(i).Addr().Interface().(chainRowPresenter)
+			all = append(all, FamilyChain{ID: chain.GetID(), Family: family, Chain: chain})
+			grouped[family] = append(grouped[family], FamilyChain{ID: chain.GetID(), Chain: chain})
+		}
+	}
+	if c.Bool("group-by-family") {
+		return cli.errorOut(r.Render(&grouped))
+	}
+	return cli.errorOut(r.Render(&all))
+}
+
+// CreateChain creates a chain with the create command of the family given
+// with --type. Without --type, the family is detected from the fields of the
+// config, and reported so the operator can confirm it; configs whose fields
+// are shared by several families, or by none, require --type.
+func (cli *Client) CreateChain(c *clipkg.Context) error {
+	family := c.String("type")
+	if family == "" {
+		if !c.Args().Present() {
+			return cli.errorOut(usageError(c, errors.New("must pass in the chain's parameters [--type string] [-id string] [JSON blob | JSON filepath]")))
+		}
+		buf, err := getBufferFromJSON(c.Args().First())
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		detected, candidates := detectChainFamily(buf.Bytes())
+		if detected == "" {
+			if len(candidates) == 0 {
+				return cli.errorOut(usageError(c, errors.New("cannot detect the chain family of the config: pass --type (options: evm, solana, terra)")))
+			}
+			names := make([]string, len(candidates))
+			for i, f := range candidates {
+				names[i] = chainFamilyNames[f]
+			}
+			return cli.errorOut(usageError(c, errors.Errorf("the config could be a %s config: pass --type to choose", strings.Join(names, " or "))))
+		}
+		family = detected
+		fmt.Fprintf(os.Stderr, "Detected a %s chain config\n", chainFamilyNames[family])
+	}
+	switch family {
+	case "evm":
+		return cli.CreateEVMChain(c)
+	case "solana":
+		return cli.CreateSolanaChain(c)
+	case "terra":
+		return cli.CreateTerraChain(c)
+	default:
+		return cli.errorOut(usageError(c, errors.Errorf("unknown chain family %q (options: evm, solana, terra)", family)))
+	}
+}
+
+// countResources returns the total count of the paginated resources at
+// requestURI, as reported in the response meta. Failed requests carry the
+// exit code of their status, see checkResponse.
+func (cli *Client) countResources(requestURI string) (count int, err error) {
+	resp, err := cli.HTTP.Get(requestURI + "?size=1")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	b, err := cli.checkResponse(resp)
+	if err != nil {
+		return 0, responseError(resp, err)
+	}
+	var doc struct {
+		Meta struct {
+			Count *int `json:"count"`
+		} `json:"meta"`
+	}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return 0, err
+	}
+	if doc.Meta.Count == nil {
+		return 0, errors.New("response is missing meta.count")
+	}
+	return *doc.Meta.Count, nil
+}
+
+// DoctorCheck is the result of a single diagnostic check.
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// Statuses of a DoctorCheck.
+const (
+	doctorPass = "pass"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// DoctorPresenter implements TableRenderer for a list of diagnostic checks.
+type DoctorPresenter struct {
+	Checks []DoctorCheck `json:"checks"`
+}
+
+func (p *DoctorPresenter) pass(name, detail string) {
+	p.Checks = append(p.Checks, DoctorCheck{Name: name, Status: doctorPass, Detail: detail})
+}
+
+func (p *DoctorPresenter) fail(name, detail, hint string) {
+	p.Checks = append(p.Checks, DoctorCheck{Name: name, Status: doctorFail, Detail: detail, Hint: hint})
+}
+
+func (p *DoctorPresenter) skip(name, detail string) {
+	p.Checks = append(p.Checks, DoctorCheck{Name: name, Status: doctorSkip, Detail: detail})
+}
+
+// Failed returns the number of failed checks.
+func (p DoctorPresenter) Failed() int {
+	failed := 0
+	for _, c := range p.Checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// RenderTable implements TableRenderer
+func (p DoctorPresenter) RenderTable(rt RendererTable) error {
+	for _, c := range p.Checks {
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(c.Status), c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		if _, err := fmt.Fprintln(rt, line); err != nil {
+			return err
+		}
+		if c.Hint != "" {
+			if _, err := fmt.Fprintf(rt, "       hint: %s\n", c.Hint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// configHash returns a short, stable hash of a chain config, for spotting
+// chains with identical configs, the prefix of its configDigest.
+func configHash(config interface{}) (string, error) {
+	digest, err := configDigest(config)
+	if err != nil {
+		return "", err
+	}
+	return digest[:12], nil
+}
+
+// configDigest returns the hex SHA-256 of a chain config in its canonical
+// JSON form, minified with object keys sorted, so that neither key order nor
+// whitespace matter.
+func configDigest(config interface{}) (string, error) {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return "", err
+	}
+	// encoding/json sorts map keys
+	b, err := json.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// configStats returns the number of top-level keys of config which are not
+// null, and its size serialized as JSON, in bytes.
+func configStats(config interface{}) (keys, size int, err error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return 0, 0, err
+	}
+	var obj map[string]interface{}
+	if err = json.Unmarshal(b, &obj); err != nil {
+		return 0, 0, err
+	}
+	for _, v := range obj {
+		if v != nil {
+			keys++
+		}
+	}
+	return keys, len(b), nil
+}
+
+// chainRowPresenter is implemented by the chain presenters of every family.
+type chainRowPresenter interface {
+	GetID() string
+	Row(rt RendererTable) ([]string, error)
+}
+
+// rowErrors collects the rows of a table which failed to render, so that the
+// table still shows all the others.
+type rowErrors []string
+
+func (e *rowErrors) add(id string, err error) {
+	*e = append(*e, fmt.Sprintf("%s: %v", id, err))
+}
+
+// report appends a note listing the failed rows to w, and returns an error if
+// there are any, so that the command fails despite the partial output.
+func (e rowErrors) report(w io.Writer) error {
+	if len(e) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\n%d row(s) failed to render: %s\n", len(e), strings.Join(e, "; "))
+	return errors.Errorf("%d row(s) failed to render", len(e))
+}
+
+// WideChain is a chain with the extra fields of --wide listings. Nodes is nil
+// if the chain's nodes could not be counted. ConfigKeys is the number of
+// top-level config keys which are not null, and ConfigSize the size of the
+// config serialized as JSON, in bytes, to spot empty or bloated configs.
+type WideChain struct {
+	Chain      chainRowPresenter `json:"chain"`
+	Family     string            `json:"family"`
+	Nodes      *int              `json:"nodes"`
+	ConfigHash string            `json:"configHash"`
+	ConfigKeys int               `json:"configKeys"`
+	ConfigSize int               `json:"configSize"`
+}
+
+// WideChainsPresenter implements TableRenderer for --wide chain listings.
+type WideChainsPresenter []WideChain
+
+// RenderTable implements TableRenderer
+func (ps WideChainsPresenter) RenderTable(rt RendererTable) error {
+	headers := append(append([]string{}, chainHeaders...), "Family", "Nodes", "Config Hash", "Keys", "Size")
+	types := append(append([]columnType{}, chainColumnTypes...), columnText, columnNumeric, columnText, columnNumeric, columnNumeric)
+	rows := [][]string{}
+	var failed rowErrors
+	for _, p := range ps {
+		nodes := "n/a"
+		if p.Nodes != nil {
+			nodes = strconv.Itoa(*p.Nodes)
+		}
+		row, err := p.Chain.Row(rt)
+		if err != nil {
+			failed.add(p.Chain.GetID(), err)
+			continue
+		}
+		rows = append(rows, append(row, p.Family, nodes, p.ConfigHash, strconv.Itoa(p.ConfigKeys), strconv.Itoa(p.ConfigSize)))
+	}
+	rt.renderTypedList(headers, types, rows)
+	return failed.report(rt)
+}
+
+// wideChains returns the chains of family, a slice of chain presenters, with
+// their node counts and config hashes and stats.
+func (cli *Client) wideChains(family string, chains interface{}) (WideChainsPresenter, error) {
+	rv := reflect.ValueOf(chains)
+	ps := WideChainsPresenter{}
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		chain := elem.Addr().Interface().(chainRowPresenter)
+		config := elem.FieldByName("Config").Interface()
+		hash, err := configHash(config)
+		if err != nil {
+			return nil, err
+		}
+		keys, size, err := configStats(config)
+		if err != nil {
+			return nil, err
+		}
+		p := WideChain{Chain: chain, Family: family, ConfigHash: hash, ConfigKeys: keys, ConfigSize: size}
+		if count, err := cli.countResources(chainFamilies[family] + "/" + chain.GetID() + "/nodes"); err == nil {
+			p.Nodes = &count
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// deprecatedConfigKeys maps each chain family to its deprecated config keys,
+// as dotted paths, and their replacements, if any. Add keys here when fields
+// are deprecated, so that create, configure and lint warn about them before
+// they are removed.
+var deprecatedConfigKeys = map[string]map[string]string{
+	"evm":    {},
+	"solana": {},
+	"terra":  {},
+}
+
+// deprecatedKeyWarnings returns a warning for each key of deprecated present
+// in config, including as the parent of nested keys.
+func deprecatedKeyWarnings(deprecated map[string]string, config interface{}) ([]string, error) {
+	if len(deprecated) == 0 {
+		return nil, nil
+	}
+	flat, err := flattenConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for key, replacement := range deprecated {
+		present := false
+		for k := range flat {
+			if k == key || strings.HasPrefix(k, key+".") {
+				present = true
+				break
+			}
+		}
+		if !present {
+			continue
+		}
+		msg := fmt.Sprintf("config key '%s' is deprecated", key)
+		if replacement != "" {
+			msg += fmt.Sprintf("; use '%s'", replacement)
+		}
+		warnings = append(warnings, msg)
+	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+// warnDeprecatedConfigKeys writes a warning to w for each deprecated key of
+// family present in the config payload.
+func warnDeprecatedConfigKeys(w io.Writer, family string, config interface{}) {
+	warnings, err := deprecatedKeyWarnings(deprecatedConfigKeys[family], config)
+	if err != nil {
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(w, "WARNING: "+warning)
+	}
+}
+
+// warnSecretConfigKeys writes a warning to w for each set field of the config
+// payload whose key looks like a secret by patterns, since chain configs are
+// sent and stored in plaintext.
+func warnSecretConfigKeys(w io.Writer, config interface{}, patterns []string) {
+	flat, err := flattenConfig(config)
+	if err != nil {
+		return
+	}
+	var keys []string
+	for k, v := range flat {
+		if v != nil && isSecretKey(k, patterns) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "WARNING: field '%s' looks like a secret and is sent in plaintext; consider using a secret reference (silence with --no-secret-warnings)\n", k)
+	}
+}
+
+// secretPatternsFlag returns the --redact key patterns of c identifying
+// sensitive config fields, or the default ones.
+func secretPatternsFlag(c *clipkg.Context) []string {
+	if c.IsSet("redact") {
+		return c.StringSlice("redact")
+	}
+	return defaultSecretPatterns
+}
+
+// configLintIssue is a problem found by lintChainConfig.
+type configLintIssue struct {
+	warning bool
+	field   string
+	msg     string
+}
+
+func (i configLintIssue) String() string {
+	severity := "error"
+	if i.warning {
+		severity = "warning"
+	}
+	if i.field == "" {
+		return fmt.Sprintf("%s: %s", severity, i.msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", severity, i.field, i.msg)
+}
+
+// configFields returns the fields of the config struct cfg by their JSON key.
+func configFields(cfg interface{}) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	t := reflect.TypeOf(cfg)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// chainConfigTypes are the config structs of each chain family.
+var chainConfigTypes = map[string]interface{}{
+	"evm":    evmtypes.ChainCfg{},
+	"solana": solanadb.ChainCfg{},
+	"terra":  terradb.ChainCfg{},
+}
+
+// chainFamilyNames are the display names of the chain families.
+var chainFamilyNames = map[string]string{
+	"evm":    "EVM",
+	"solana": "Solana",
+	"terra":  "Terra",
+}
+
+// chainFamilyFieldMatches returns, for each chain family, how many keys of the
+// JSON config raw are fields of its config, or nil if raw is not a non-empty
+// object. Keys are matched case-insensitively, like encoding/json does.
+func chainFamilyFieldMatches(raw []byte) map[string]int {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil || len(obj) == 0 {
+		return nil
+	}
+	matches := map[string]int{}
+	for family, cfg := range chainConfigTypes {
+		for name := range configFields(cfg) {
+			for k := range obj {
+				if strings.EqualFold(name, k) {
+					matches[family]++
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// guessChainFamily returns the chain family the JSON config raw most likely
+// belongs to if none of its keys are fields of family's config, but some are
+// fields of another family's, or "" otherwise.
+func guessChainFamily(family string, raw []byte) string {
+	matches := chainFamilyFieldMatches(raw)
+	if matches == nil || matches[family] > 0 {
+		return ""
+	}
+	var families []string
+	for f := range chainConfigTypes {
+		families = append(families, f)
+	}
+	sort.Strings(families)
+	guess, best := "", 0
+	for _, f := range families {
+		if n := matches[f]; f != family && n > best {
+			guess, best = f, n
+		}
+	}
+	return guess
+}
+
+// detectChainFamily returns the chain family whose config has the most fields
+// among the keys of the JSON config raw. If several families tie, family is
+// empty and candidates lists them; if none match, both are empty.
+func detectChainFamily(raw []byte) (family string, candidates []string) {
+	matches := chainFamilyFieldMatches(raw)
+	best := 0
+	for _, n := range matches {
+		if n > best {
+			best = n
+		}
+	}
+	if best == 0 {
+		return "", nil
+	}
+	for f, n := range matches {
+		if n == best {
+			candidates = append(candidates, f)
+		}
+	}
+	sort.Strings(candidates)
+	if len(candidates) > 1 {
+		return "", candidates
+	}
+	return candidates[0], candidates
+}
+
+// warnChainFamilyMismatch writes a warning to w if the JSON config raw looks
+// like the config of another chain family than family, the family of the
+// command it was passed to, unless --no-family-check is set. The check is a
+// heuristic, so it does not stop the command.
+func warnChainFamilyMismatch(c *clipkg.Context, w io.Writer, family string, raw []byte) {
+	if c.Bool("no-family-check") {
+		return
+	}
+	if guess := guessChainFamily(family, raw); guess != "" {
+		fmt.Fprintf(w, "WARNING: this config looks like a %s config but you're using the %s command\n", chainFamilyNames[guess], chainFamilyNames[family])
+	}
+}
+
+// lintChainConfig checks the JSON chain config raw against the fields of the
+// config struct cfg, without talking to a node. Unknown fields and values
+// which do not decode into their field's type are errors. Keys only matching
+// a field case-insensitively, and the keys of deprecated, which maps them to
+// their replacement, are warnings.
+func lintChainConfig(raw []byte, cfg interface{}, deprecated map[string]string) []configLintIssue {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return []configLintIssue{{msg: "config must be a JSON object"}}
+	}
+
+	fields := configFields(cfg)
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var issues []configLintIssue
+	for _, k := range keys {
+		if replacement, ok := deprecated[k]; ok {
+			msg := "deprecated"
+			if replacement != "" {
+				msg += fmt.Sprintf(", use '%s'", replacement)
+			}
+			issues = append(issues, configLintIssue{warning: true, field: k, msg: msg})
+		}
+		f, ok := fields[k]
+		if !ok {
+			// encoding/json matches keys case-insensitively
+			for name, cf := range fields {
+				if strings.EqualFold(name, k) {
+					issues = append(issues, configLintIssue{warning: true, field: k, msg: fmt.Sprintf("only matches field %s case-insensitively", name)})
+					f, ok = cf, true
+					break
+				}
+			}
+		}
+		if !ok {
+			issues = append(issues, configLintIssue{field: k, msg: "unknown field"})
+			continue
+		}
+		if err := json.Unmarshal(obj[k], reflect.New(f.Type).Interface()); err != nil {
+			issues = append(issues, configLintIssue{field: k, msg: fmt.Sprintf("invalid value %s: %v", obj[k], err)})
+		}
+	}
+	return issues
+}
+
+// ChainConfigIssue is an issue found in the config of a chain of the node.
+type ChainConfigIssue struct {
+	ChainID  string `json:"chainID"`
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Issue    string `json:"issue"`
+}
+
+// ChainConfigIssuesPresenter implements TableRenderer for the config issues
+// of the chains of a node.
+type ChainConfigIssuesPresenter []ChainConfigIssue
+
+// RenderTable implements TableRenderer
+func (ps ChainConfigIssuesPresenter) RenderTable(rt RendererTable) error {
+	if len(ps) == 0 {
+		_, err := fmt.Fprintln(rt, "No config issues found")
+		return err
+	}
+	table := rt.newTable([]string{"Chain ID", "Severity", "Field", "Issue"})
+	for _, p := range ps {
+		table.Append([]string{p.ChainID, p.Severity, p.Field, p.Issue})
+	}
+	rt.render("Chain Config Issues", table)
+	return nil
+}
+
+// validateChainConfigs lints the raw config of each chain, by chain ID, like
+// lintChainConfig, returning the issues in chain ID order and the IDs of the
+// chains with errors, or with strict, warnings.
+func validateChainConfigs(configs map[string]json.RawMessage, cfg interface{}, deprecated map[string]string, strict bool) (ChainConfigIssuesPresenter, []string) {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	issues := ChainConfigIssuesPresenter{}
+	var failed []string
+	for _, id := range ids {
+		fail := false
+		for _, issue := range lintChainConfig(configs[id], cfg, deprecated) {
+			severity := "error"
+			if issue.warning {
+				severity = "warning"
+			}
+			fail = fail || !issue.warning || strict
+			issues = append(issues, ChainConfigIssue{ChainID: id, Severity: severity, Field: issue.field, Issue: issue.msg})
+		}
+		if fail {
+			failed = append(failed, id)
+		}
+	}
+	return issues, failed
+}
+
+// lintChainConfigFile lints the chain config in the file given as the first
+// argument, writing the issues found to w. It fails if there are any errors,
+// or with --strict, any warnings.
+func lintChainConfigFile(c *clipkg.Context, w io.Writer, cfg interface{}, deprecated map[string]string) error {
+	if !c.Args().Present() {
+		return errors.New("must pass the path of the config file to lint")
+	}
+	path := c.Args().First()
+	raw, err := chainConfigFile(path, c.Bool("jsonc"))
+	if err != nil {
+		return err
+	}
+	var errs, warnings int
+	for _, issue := range lintChainConfig(raw, cfg, deprecated) {
+		if issue.warning {
+			warnings++
+		} else {
+			errs++
+		}
+		fmt.Fprintf(w, "%s: %s\n", path, issue)
+	}
+	if errs > 0 || (warnings > 0 && c.Bool("strict")) {
+		return errors.Errorf("%s: %d error(s), %d warning(s)", path, errs, warnings)
+	}
+	return nil
+}
+
+// configFieldDocs documents the config fields of each chain family, by the
+// dotted path of the field. A `doc` struct tag on the field takes precedence.
+var configFieldDocs = map[string]map[string]string{
+	"evm": {},
+	"solana": {
+		"BalancePollPeriod":   "How often the balances of the node's Solana keys are polled for monitoring.",
+		"ConfirmPollPeriod":   "How often the confirmation status of broadcast transactions is polled.",
+		"OCR2CachePollPeriod": "How often the OCR2 state and transmissions caches are refreshed from the chain.",
+		"OCR2CacheTTL":        "How old cached OCR2 state may be before it is considered stale and no longer used.",
+		"TxTimeout":           "How long to wait for a broadcast transaction to be confirmed before giving up on it.",
+		"SkipPreflight":       "Whether to skip the RPC node's preflight simulation of transactions before sending them.",
+		"Commitment":          "The commitment level for reading chain state, one of processed, confirmed or finalized.",
+	},
+	"terra": {},
+}
+
+// explainConfigField writes the type, default and documentation of the field
+// at the dotted path of the config struct cfg. The defaults are read from the
+// config struct defaults, and docs is consulted for fields without a `doc`
+// tag. Unknown fields are reported with similarly named fields.
+func explainConfigField(w io.Writer, cfg, defaults interface{}, docs map[string]string, path string) error {
+	name, field, t, err := lookupConfigField(cfg, path)
+	if err != nil {
+		return err
+	}
+	doc := configFieldDoc(field, docs, name)
+	if doc == "" {
+		doc = "(undocumented)"
+	}
+
+	def := "(none)"
+	if flat, err := flattenConfig(defaults); err == nil {
+		if v, ok := flat[name]; ok && v != nil {
+			if str, isStr := v.(string); isStr {
+				def = str
+			} else if b, err := json.Marshal(v); err == nil {
+				def = string(b)
+			}
+		}
+	}
+	fmt.Fprintf(w, "Field:    %s\n", name)
+	fmt.Fprintf(w, "Type:     %s\n", configTypeName(t))
+	fmt.Fprintf(w, "Default:  %s\n", def)
+	fmt.Fprintf(w, "Doc:      %s\n", doc)
+	return nil
+}
+
+// lookupConfigField returns the canonical dotted path, struct field and
+// dereferenced type of the field at the dotted path of the config struct cfg,
+// matching keys case-insensitively as encoding/json does. Unknown fields are
+// reported with similarly named fields.
+func lookupConfigField(cfg interface{}, path string) (name string, field reflect.StructField, t reflect.Type, err error) {
+	t = reflect.TypeOf(cfg)
+	var canonical []string
+	for i, seg := range strings.Split(path, ".") {
+		if i > 0 && !isConfigObject(t) {
+			return "", field, nil, errors.Errorf("field %s has no fields, so %s is not a field", strings.Join(canonical, "."), path)
+		}
+		fields := configFields(reflect.New(t).Elem().Interface())
+		f, ok := fields[seg]
+		if !ok {
+			for name, cf := range fields {
+				if strings.EqualFold(name, seg) {
+					f, ok, seg = cf, true, name
+					break
+				}
+			}
+		}
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			msg := fmt.Sprintf("unknown field '%s'", strings.Join(append(canonical, seg), "."))
+			if similar := similarFieldNames(seg, names); len(similar) > 0 {
+				msg += "; did you mean " + strings.Join(similar, ", ") + "?"
+			}
+			return "", field, nil, errors.New(msg)
+		}
+		canonical = append(canonical, seg)
+		field, t = f, f.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return strings.Join(canonical, "."), field, t, nil
+}
+
+// configFieldDoc returns the documentation of field, at the canonical dotted
+// path name, from its `doc` tag or else docs.
+func configFieldDoc(field reflect.StructField, docs map[string]string, name string) string {
+	if doc := field.Tag.Get("doc"); doc != "" {
+		return doc
+	}
+	return docs[name]
+}
+
+// configSchema returns a JSON Schema of the config struct cfg, with the
+// descriptions of its fields from their `doc` tags or docs, as explain shows
+// them. If only is not empty, just those top-level fields are included, and
+// unknown ones are reported with the valid set.
+func configSchema(cfg interface{}, docs map[string]string, only []string) (map[string]interface{}, error) {
+	schema := configTypeSchema(reflect.TypeOf(cfg), docs, "")
+	if len(only) == 0 {
+		return schema, nil
+	}
+	properties := schema["properties"].(map[string]interface{})
+	selected := map[string]interface{}{}
+	for _, name := range only {
+		property, ok := properties[name]
+		if !ok {
+			names := make([]string, 0, len(properties))
+			for n := range properties {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, errors.Errorf("unknown field '%s' in --only-fields, valid fields: %s", name, strings.Join(names, ", "))
+		}
+		selected[name] = property
+	}
+	schema["properties"] = selected
+	return schema, nil
+}
+
+// configTypeSchema returns the JSON Schema of config fields of type t, at the
+// dotted path prefix.
+func configTypeSchema(t reflect.Type, docs map[string]string, prefix string) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	schema := map[string]interface{}{}
+	if t == reflect.TypeOf(models.Duration{}) {
+		schema["type"], schema["format"] = "string", "duration"
+		return schema
+	}
+	switch name := configTypeName(t); name {
+	case "bool":
+		schema["type"] = "boolean"
+	case "int":
+		schema["type"] = "integer"
+	case "float":
+		schema["type"] = "number"
+	case "string", "array":
+		schema["type"] = name
+	case "object":
+		schema["type"] = name
+		if isConfigObject(t) {
+			properties := map[string]interface{}{}
+			for fieldName, f := range configFields(reflect.New(t).Elem().Interface()) {
+				path := prefix + fieldName
+				property := configTypeSchema(f.Type, docs, path+".")
+				doc := f.Tag.Get("doc")
+				if doc == "" {
+					doc = docs[path]
+				}
+				if doc != "" {
+					property["description"] = doc
+				}
+				properties[fieldName] = property
+			}
+			schema["properties"] = properties
+		}
+	}
+	return schema
+}
+
+// isConfigObject reports whether config fields of type t hold JSON objects
+// with fields of their own, as opposed to scalar values like durations which
+// are structs only in Go.
+func isConfigObject(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, marshals := reflect.New(t).Interface().(json.Marshaler)
+	return !marshals
+}
+
+// configTypeName returns the name of the config field type t, as it is
+// written in JSON.
+func configTypeName(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(models.Duration{}):
+		return "duration (e.g. 30s)"
+	case reflect.TypeOf(null.Bool{}):
+		return "bool"
+	case reflect.TypeOf(null.String{}):
+		return "string"
+	case reflect.TypeOf(null.Int{}):
+		return "int"
+	case reflect.TypeOf(null.Float{}):
+		return "float"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "object"
+	case reflect.Struct:
+		if isConfigObject(t) {
+			return "object"
+		}
+	}
+	return t.String()
+}
+
+// similarFieldNames returns the names within a small edit distance of name,
+// ignoring case, closest first.
+func similarFieldNames(name string, names []string) []string {
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, other := range names {
+		d := editDistance(strings.ToLower(name), strings.ToLower(other))
+		if d <= maxDistance || strings.Contains(strings.ToLower(other), strings.ToLower(name)) {
+			candidates = append(candidates, candidate{other, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	var similar []string
+	for i := 0; i < len(candidates) && i < maxSimilarChainIDs; i++ {
+		similar = append(similar, candidates[i].name)
+	}
+	return similar
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// ChainsDiffPresenter implements TableRenderer for the differences between
+// the chains of two nodes.
+type ChainsDiffPresenter struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	OnlyInFrom []string          `json:"onlyInFrom"`
+	OnlyInTo   []string          `json:"onlyInTo"`
+	Changed    []ChainConfigDiff `json:"changed"`
+}
+
+// Empty returns true if no differences were found.
+func (p ChainsDiffPresenter) Empty() bool {
+	return len(p.OnlyInFrom) == 0 && len(p.OnlyInTo) == 0 && len(p.Changed) == 0
+}
+
+// RenderTable implements TableRenderer
+func (p ChainsDiffPresenter) RenderTable(rt RendererTable) error {
+	var b strings.Builder
+	if p.Empty() {
+		b.WriteString("No differences found\n")
+	}
+	if len(p.OnlyInFrom) > 0 {
+		fmt.Fprintf(&b, "Only on %s: %s\n", p.From, strings.Join(p.OnlyInFrom, ", "))
+	}
+	if len(p.OnlyInTo) > 0 {
+		fmt.Fprintf(&b, "Only on %s: %s\n", p.To, strings.Join(p.OnlyInTo, ", "))
+	}
+	for _, c := range p.Changed {
+		fmt.Fprintf(&b, "Config differs for chain %s:\n", c.ID)
+		for _, f := range c.Fields {
+			fmt.Fprintf(&b, "    %s: %s -> %s\n", f.Key, rt.formatConfigField(f.Key, f.From), rt.formatConfigField(f.Key, f.To))
+		}
+	}
+	_, err := rt.Write([]byte(b.String()))
+	return err
+}
+
+// diffChains compares two sets of chain configs keyed by chain ID.
+func diffChains(from, to map[string]interface{}) (p ChainsDiffPresenter, err error) {
+	for id := range from {
+		if _, ok := to[id]; !ok {
+			p.OnlyInFrom = append(p.OnlyInFrom, id)
+		}
+	}
+	for id, toCfg := range to {
+		fromCfg, ok := from[id]
+		if !ok {
+			p.OnlyInTo = append(p.OnlyInTo, id)
+			continue
+		}
+		var fields []ConfigFieldDiff
+		if fields, err = diffConfigs(fromCfg, toCfg); err != nil {
+			return p, errors.Wrapf(err, "failed to diff config of chain %s", id)
+		}
+		if len(fields) > 0 {
+			p.Changed = append(p.Changed, ChainConfigDiff{ID: id, Fields: fields})
+		}
+	}
+	sort.Strings(p.OnlyInFrom)
+	sort.Strings(p.OnlyInTo)
+	sort.Slice(p.Changed, func(i, j int) bool { return p.Changed[i].ID < p.Changed[j].ID })
+	return p, nil
+}
+
+// configTypeHint matches a :type suffix of a config value.
+var configTypeHint = regexp.MustCompile(`:([a-z]+)$`)
+
+// configValueTypes coerces config values by their :type suffix.
+var configValueTypes = map[string]func(s string) (interface{}, error){
+	"string": func(s string) (interface{}, error) { return s, nil },
+	"int": func(s string) (interface{}, error) {
+		return strconv.ParseInt(s, 10, 64)
+	},
+	"float": func(s string) (interface{}, error) {
+		return strconv.ParseFloat(s, 64)
+	},
+	"bool": func(s string) (interface{}, error) {
+		return strconv.ParseBool(s)
+	},
+	"duration": func(s string) (interface{}, error) {
+		if _, err := time.ParseDuration(s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	},
+	"json": func(s string) (interface{}, error) {
+		var v interface{}
+		err := json.Unmarshal([]byte(s), &v)
+		return v, err
+	},
+}
+
+// unsetConfigValue is the value of a key=value config argument which unsets
+// the field, like null or an empty value.
+const unsetConfigValue = "__unset__"
+
+// parseConfigParams parses key=value config arguments into a partial config
+// map. Values which are valid JSON are decoded, anything else is treated as a
+// string. A value may end in a type hint to force its type, one of :string,
+// :int, :float, :bool, :duration (a string checked to be a duration) or :json,
+// e.g. Name=8080:string. Values ending in another lowercase :suffix must use
+// :string to be taken literally. An empty value, or __unset__, unsets the
+// field, as null does, see withoutUnsetConfigKeys; Name=:string is the empty
+// string.
+func parseConfigParams(args []string) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid parameter: %v", arg)
+		}
+
+		if m := configTypeHint.FindStringSubmatchIndex(parts[1]); m != nil {
+			typ := parts[1][m[2]:m[3]]
+			coerce, ok := configValueTypes[typ]
+			if !ok {
+				return nil, errors.Errorf("invalid parameter %v: unknown type %q (options: bool, duration, float, int, json, string)", arg, typ)
+			}
+			value, err := coerce(parts[1][:m[0]])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid parameter %v: not a valid %s", arg, typ)
+			}
+			params[parts[0]] = value
+			continue
+		}
+
+		if parts[1] == "" || parts[1] == unsetConfigValue {
+			params[parts[0]] = nil
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(parts[1]), &value); err != nil {
+			// treat it as a string
+			value = parts[1]
+		}
+		params[parts[0]] = value
+	}
+	return params, nil
+}
+
+// configFieldDefaults returns the defaults of the config fields keys of the
+// config struct cfg, taken from defaults, which is cfg with its defaults
+// filled in. Keys match the fields case-insensitively. Unknown fields, and
+// fields without a known default, are an error.
+func configFieldDefaults(keys []string, cfg, defaults interface{}) (map[string]interface{}, error) {
+	fields := configFields(cfg)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	generic, err := toGenericConfig(defaults)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := generic.(map[string]interface{})
+	resets := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		name := key
+		if _, ok := fields[name]; !ok {
+			name = ""
+			for _, n := range names {
+				if strings.EqualFold(n, key) {
+					name = n
+					break
+				}
+			}
+		}
+		if name == "" {
+			msg := fmt.Sprintf("unknown config field '%s'", key)
+			if similar := similarFieldNames(key, names); len(similar) > 0 {
+				msg += "; did you mean " + strings.Join(similar, ", ") + "?"
+			}
+			return nil, errors.New(msg)
+		}
+		value, ok := values[name]
+		if !ok || value == nil {
+			return nil, errors.Errorf("config field %s has no known default", name)
+		}
+		resets[name] = value
+	}
+	return resets, nil
+}
+
+// withoutUnsetConfigKeys returns config as a generic map, without the
+// top-level fields which the partial config updates unset, so that configure
+// removes them from the config it sends rather than sending them as null.
+// Fields which are set again, e.g. when a change is declined with
+// --interactive, are kept.
+func withoutUnsetConfigKeys(config interface{}, updates json.RawMessage) (interface{}, error) {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(updates, &params); err != nil {
+		return nil, err
+	}
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := generic.(map[string]interface{})
+	if !ok {
+		return generic, nil
+	}
+	for key, value := range params {
+		if string(value) == "null" && m[key] == nil {
+			delete(m, key)
+		}
+	}
+	return m, nil
+}
+
+// parseTypedConfigParams is like parseConfigParams, but coerces each value by
+// the type of its field in the config struct cfg rather than guessing it from
+// the value, e.g. Commitment=123 is the string "123" and TxTimeout=5s is
+// checked to be a duration. Type hints still take precedence, null, an empty
+// value and __unset__ unset a field, and values with {{...}} references are
+// left to interpolateConfigUpdates. Unknown fields are an error.
+func parseTypedConfigParams(args []string, cfg interface{}) (map[string]interface{}, error) {
+	fields := configFields(cfg)
+	params := map[string]interface{}{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid parameter: %v", arg)
+		}
+		key, s := parts[0], parts[1]
+		field, ok := fields[key]
+		if !ok {
+			for name, f := range fields {
+				if strings.EqualFold(name, key) {
+					field, ok, key = f, true, name
+					break
+				}
+			}
+		}
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			msg := fmt.Sprintf("invalid parameter %v: unknown field '%s'", arg, key)
+			if similar := similarFieldNames(key, names); len(similar) > 0 {
+				msg += "; did you mean " + strings.Join(similar, ", ") + "?"
+			}
+			return nil, errors.New(msg)
+		}
+		if s == "" || s == unsetConfigValue || s == "null" || configTypeHint.MatchString(s) || strings.Contains(s, "{{") {
+			untyped, err := parseConfigParams([]string{key + "=" + s})
+			if err != nil {
+				return nil, err
+			}
+			params[key] = untyped[key]
+			continue
+		}
+		typ := configValueType(field.Type)
+		value, err := configValueTypes[typ](s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid parameter %v: not a valid %s", arg, typ)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
+
+// configValueType returns the configValueTypes entry coercing values of
+// config fields of type t. Arrays and objects are given as JSON.
+func configValueType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	typ := strings.Fields(configTypeName(t))[0]
+	if _, ok := configValueTypes[typ]; ok {
+		return typ
+	}
+	return "json"
+}
+
+// interpolateConfigUpdates resolves references to other config fields in the
+// string values of the partial config updates, e.g. WSURL={{.RPC.URL}}, against
+// current. Values without {{...}} are left as they are. Like untyped argument
+// values, resolved values which are valid JSON are decoded. Referencing a field
+// which is missing or null in current is an error.
+func interpolateConfigUpdates(updates json.RawMessage, current interface{}) (json.RawMessage, error) {
+	if !bytes.Contains(updates, []byte("{{")) {
+		return updates, nil
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(updates, &params); err != nil {
+		return nil, err
+	}
+	data, err := toGenericConfig(current)
+	if err != nil {
+		return nil, err
+	}
+	data = dropNulls(data)
+	for key, value := range params {
+		s, ok := value.(string)
+		if !ok || !strings.Contains(s, "{{") {
+			continue
+		}
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid reference in %s=%s", key, s)
+		}
+		var b strings.Builder
+		if err = tmpl.Execute(&b, data); err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve %s=%s", key, s)
+		}
+		var resolved interface{}
+		if err = json.Unmarshal([]byte(b.String()), &resolved); err != nil {
+			resolved = b.String()
+		}
+		params[key] = resolved
+	}
+	return json.Marshal(params)
+}
+
+// dropNulls returns the generic config v without its null fields, so that
+// templates executed with missingkey=error treat them as missing.
+func dropNulls(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for k, field := range m {
+		if field == nil {
+			delete(m, k)
+			continue
+		}
+		m[k] = dropNulls(field)
+	}
+	return m
+}
+
+// hclIdentifier matches the keys which can be written as HCL attribute and
+// block names.
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// writeConfigHCL writes the chain with id and enabled state, and its config,
+// to w as an HCL block of type blockType, e.g. for Terraform. Objects become
+// nested blocks, and scalars and arrays of scalars attributes, in sorted order.
+// HCL has no equivalent of some JSON, so:
+//   - null fields are omitted, as HCL has no null literal outside expressions
+//   - arrays of objects become repeated blocks, losing their position among
+//     the other elements
+//   - durations and other text encoded values stay strings
+//   - keys which are not HCL identifiers are an error
+func writeConfigHCL(w io.Writer, blockType, id string, enabled bool, config interface{}) error {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"enabled": enabled}
+	if generic = dropNulls(generic); generic != nil {
+		body["config"] = generic
+	}
+	fmt.Fprintf(w, "%s %s {\n", blockType, hclString(id))
+	if err = writeHCLBody(w, body, "  "); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "}")
+	return nil
+}
+
+// writeHCLBody writes the fields of obj as the body of an HCL block, its
+// attributes first with their equals signs aligned, then its blocks.
+func writeHCLBody(w io.Writer, obj map[string]interface{}, indent string) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if !hclIdentifier.MatchString(k) {
+			return errors.Errorf("config key %q is not a valid HCL identifier", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var attrs, blocks []string
+	width := 0
+	for _, k := range keys {
+		if isHCLBlock(obj[k]) {
+			blocks = append(blocks, k)
+			continue
+		}
+		attrs = append(attrs, k)
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	for _, k := range attrs {
+		fmt.Fprintf(w, "%s%-*s = %s\n", indent, width, k, hclValue(obj[k]))
+	}
+	for _, k := range blocks {
+		elems, ok := obj[k].([]interface{})
+		if !ok {
+			elems = []interface{}{obj[k]}
+		}
+		for _, elem := range elems {
+			fmt.Fprintf(w, "%s%s {\n", indent, k)
+			if err := writeHCLBody(w, elem.(map[string]interface{}), indent+"  "); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s}\n", indent)
+		}
+	}
+	return nil
+}
+
+// isHCLBlock reports whether v is written as blocks: objects, and arrays
+// holding only objects.
+func isHCLBlock(v interface{}) bool {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, elem := range typed {
+			if _, ok := elem.(map[string]interface{}); !ok {
+				return false
+			}
+		}
+		return len(typed) > 0
+	}
+	return false
+}
+
+// hclValue formats a generic config value as an HCL expression. Objects
+// left inside arrays of mixed elements are written as HCL objects.
+func hclValue(v interface{}) string {
+	switch typed := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return hclString(typed)
+	case []interface{}:
+		elems := make([]string, len(typed))
+		for i, elem := range typed {
+			elems[i] = hclValue(elem)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for k := range typed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]string, len(keys))
+		for i, k := range keys {
+			fields[i] = hclString(k) + " = " + hclValue(typed[k])
+		}
+		return "{ " + strings.Join(fields, ", ") + " }"
+	default:
+		return formatConfigValue(typed)
+	}
+}
+
+// hclString quotes s as an HCL string, escaping template sequences, which
+// HCL would otherwise interpolate.
+func hclString(s string) string {
+	q := strconv.Quote(s)
+	q = strings.ReplaceAll(q, "${", "$${")
+	return strings.ReplaceAll(q, "%{", "%%{")
+}
+
+// writeConfigYAML writes config to w as a YAML document, with the keys of its
+// mappings in sorted order. Keys and strings that YAML would read as another
+// type, such as true or null, are quoted.
+func writeConfigYAML(w io.Writer, config interface{}) error {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return err
+	}
+	node, err := yamlConfigNode(generic)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// yaml11Bools are the words that YAML 1.1 parsers read as booleans. yaml.v3
+// quotes them as values, but not as mapping keys.
+var yaml11Bools = map[string]bool{"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true}
+
+// yamlConfigNode returns the YAML node of a value decoded by toGenericConfig.
+// Empty mappings and sequences are written in flow style, as {} and [].
+func yamlConfigNode(v interface{}) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if len(v) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, err := yamlConfigNode(v[k])
+			if err != nil {
+				return nil, err
+			}
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}
+			if yaml11Bools[strings.ToLower(k)] {
+				key.Style = yaml.DoubleQuotedStyle
+			}
+			node.Content = append(node.Content, key, value)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if len(v) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, e := range v {
+			value, err := yamlConfigNode(e)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
+
+// renderConfigPreset returns the config of the preset name, with the
+// key=value overrides of args applied on top. Overrides are coerced by the
+// fields of cfg like configure's, and null removes a field of the preset.
+func renderConfigPreset(presets map[string]map[string]interface{}, name string, args []string, cfg interface{}) (map[string]interface{}, error) {
+	preset, ok := presets[name]
+	if !ok {
+		names := make([]string, 0, len(presets))
+		for n := range presets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, errors.Errorf("unknown preset '%s', options: %s", name, strings.Join(names, ", "))
+	}
+	overrides, err := parseTypedConfigParams(args, cfg)
+	if err != nil {
+		return nil, err
+	}
+	config := make(map[string]interface{}, len(preset)+len(overrides))
+	for k, v := range preset {
+		config[k] = v
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(config, k)
+			continue
+		}
+		config[k] = v
+	}
+	return config, nil
+}
+
+// writeConfigTree writes config to w as a tree of its keys, in sorted order
+// and indented by depth, with scalar values inline after their key and array
+// elements marked by their index. Null fields are omitted.
+func writeConfigTree(w io.Writer, config interface{}, indent string) error {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return err
+	}
+	writeTreeValue(w, dropNulls(generic), indent)
+	return nil
+}
+
+func writeTreeValue(w io.Writer, v interface{}, indent string) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 {
+			fmt.Fprintf(w, "%s(empty)\n", indent)
+			return
+		}
+		keys := make([]string, 0, len(typed))
+		for k := range typed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeTreeEntry(w, k, typed[k], indent)
+		}
+	case []interface{}:
+		if len(typed) == 0 {
+			fmt.Fprintf(w, "%s(empty)\n", indent)
+			return
+		}
+		for i, elem := range typed {
+			writeTreeEntry(w, fmt.Sprintf("[%d]", i), elem, indent)
+		}
+	default:
+		fmt.Fprintf(w, "%s%s\n", indent, formatScalar(typed))
+	}
+}
+
+func writeTreeEntry(w io.Writer, label string, v interface{}, indent string) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		fmt.Fprintf(w, "%s%s\n", indent, label)
+		writeTreeValue(w, v, indent+"  ")
+	default:
+		fmt.Fprintf(w, "%s%s: %s\n", indent, label, formatScalar(v))
+	}
+}
+
+// readKeyValueLines reads key=value config parameters from r, one per line.
+// Blank lines and lines starting with # are skipped.
+func readKeyValueLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// chainApplyOutcome is what applying the desired state of a chain did.
+type chainApplyOutcome int
+
+const (
+	chainApplyFailed chainApplyOutcome = iota
+	chainCreated
+	chainUpdated
+	chainUnchanged
+	chainDeleted
+)
+
+// ChainApplySummary counts the outcomes of applying chains, as written by the
+// apply commands at the end of a run, or alone with --output json.
+type ChainApplySummary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	// Deleted is only counted by reconcile --prune.
+	Deleted int `json:"deleted,omitempty"`
+	Failed  int `json:"failed"`
+}
+
+func (s ChainApplySummary) String() string {
+	if s.Deleted > 0 {
+		return fmt.Sprintf("%d created, %d updated, %d unchanged, %d deleted, %d failed", s.Created, s.Updated, s.Unchanged, s.Deleted, s.Failed)
+	}
+	return fmt.Sprintf("%d created, %d updated, %d unchanged, %d failed", s.Created, s.Updated, s.Unchanged, s.Failed)
+}
+
+// add counts outcome, reporting it for chainID to w unless w is nil.
+func (s *ChainApplySummary) add(w io.Writer, chainID string, outcome chainApplyOutcome, err error) {
+	var what string
+	switch outcome {
+	case chainCreated:
+		s.Created++
+		what = "created"
+	case chainUpdated:
+		s.Updated++
+		what = "updated"
+	case chainUnchanged:
+		s.Unchanged++
+		what = "unchanged"
+	case chainDeleted:
+		s.Deleted++
+		what = "deleted"
+	default:
+		s.Failed++
+		what = fmt.Sprintf("failed: %v", err)
+	}
+	if w != nil {
+		fmt.Fprintf(w, "Chain %s %s\n", chainID, what)
+	}
+}
+
+// report writes the summary to w, as a line of text or with jsonOutput as a
+// JSON object, and returns an error if any chain failed to apply.
+func (s ChainApplySummary) report(w io.Writer, jsonOutput bool) error {
+	if jsonOutput {
+		b, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+	} else {
+		fmt.Fprintln(w, s)
+	}
+	if s.Failed > 0 {
+		return errors.Errorf("failed to apply %d of %d chains", s.Failed, s.Created+s.Updated+s.Unchanged+s.Deleted+s.Failed)
+	}
+	return nil
+}
+
+// reservedChainIDs are chain IDs which would be interpreted as relative path
+// segments in the chain resource URLs.
+var reservedChainIDs = map[string]struct{}{".": {}, "..": {}}
+
+// validateChainID trims the surrounding whitespace from a new chain ID and
+// checks that it is usable as a URL path segment.
+func validateChainID(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", errors.New("missing chain ID [-id string]")
+	}
+	if _, ok := reservedChainIDs[id]; ok {
+		return "", errors.Errorf("invalid chain ID %q: reserved path segment", id)
+	}
+	if strings.ContainsAny(id, "/?#") {
+		return "", errors.Errorf("invalid chain ID %q: must not contain any of '/', '?' or '#'", id)
+	}
+	return id, nil
+}
+
+// lookupConfigPath returns the value at the dotted path in config. Path
+// segments index into objects by key, and into arrays by position, e.g.
+// Nodes.0.URL.
+func lookupConfigPath(config interface{}, path string) (interface{}, error) {
+	v, err := toGenericConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(path, ".")
+	for i, seg := range segments {
+		traversed := strings.Join(segments[:i+1], ".")
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			val, ok := typed[seg]
+			if !ok {
+				return nil, errors.Errorf("config field %s not found", traversed)
+			}
+			v = val
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(typed) {
+				return nil, errors.Errorf("config field %s not found: index out of range [0, %d)", traversed, len(typed))
+			}
+			v = typed[idx]
+		default:
+			return nil, errors.Errorf("config field %s not found: %s is not an object or array", traversed, strings.Join(segments[:i], "."))
+		}
+	}
+	return v, nil
+}
+
+// unsetConfigPaths returns config as a generic object without the fields at
+// the dotted paths, e.g. RPC.Timeout. Fields which are missing or null fail
+// with an error, unless ignoreMissing is set.
+func unsetConfigPaths(config interface{}, paths []string, ignoreMissing bool) (map[string]interface{}, error) {
+	generic, err := toGenericConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("config is not an object")
+	}
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		parent := root
+		for i, seg := range segments[:len(segments)-1] {
+			v := parent[seg]
+			child, ok := v.(map[string]interface{})
+			if !ok && v != nil {
+				return nil, errors.Errorf("config field %s cannot be unset: %s is not an object", path, strings.Join(segments[:i+1], "."))
+			}
+			parent = child
+			if parent == nil {
+				break
+			}
+		}
+		last := segments[len(segments)-1]
+		if parent == nil || parent[last] == nil {
+			if ignoreMissing {
+				continue
+			}
+			return nil, errors.Errorf("config field %s is not set", path)
+		}
+		delete(parent, last)
+	}
+	return root, nil
+}
+
+// formatScalar formats a generic config value as plain text for scripting.
+// Unlike formatConfigValue, strings are not quoted.
+func formatScalar(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return formatConfigValue(v)
+}
+
+// expectChainConfig compares config to the expected JSON config in file,
+// ignoring key order, and writes any mismatched fields to w. Fields missing
+// from either side are treated as null. When subset is true, only the fields
+// present in the expected config are compared.
+func expectChainConfig(w io.Writer, chainID string, config interface{}, file string, subset bool) error {
+	expected, err := chainConfigFile(file, false)
+	if err != nil {
+		return err
+	}
+	diffs, err := diffConfigs(expected, config)
+	if err != nil {
+		return err
+	}
+	if subset {
+		expectedFlat, err := flattenConfig(expected)
+		if err != nil {
+			return err
+		}
+		var filtered []ConfigFieldDiff
+		for _, d := range diffs {
+			if _, ok := expectedFlat[d.Key]; ok {
+				filtered = append(filtered, d)
+			}
+		}
+		diffs = filtered
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "Config of chain %s does not match %s:\n", chainID, file)
+	for _, d := range diffs {
+		fmt.Fprintf(w, "  %s: expected %s, got %s\n", d.Key, formatConfigValue(d.From), formatConfigValue(d.To))
+	}
+	return errors.Errorf("config of chain %s does not match %s", chainID, file)
+}
+
+// writeConfigDiff writes the differences between the configs from and to,
+// labeled fromName and toName, to w in a unified diff style, a line per
+// differing field. Key order and formatting are ignored, and missing fields
+// are treated as null. If explain is not nil, the documentation it returns
+// for each differing field is written after it as a # comment. It reports
+// whether the configs differ.
+func writeConfigDiff(w io.Writer, fromName, toName string, from, to interface{}, explain func(key string) string) (bool, error) {
+	diffs, err := diffConfigs(from, to)
+	if err != nil || len(diffs) == 0 {
+		return false, err
+	}
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", fromName, toName)
+	for _, d := range diffs {
+		fmt.Fprintf(w, "-%s: %s\n+%s: %s\n", d.Key, formatConfigValue(d.From), d.Key, formatConfigValue(d.To))
+		if explain != nil {
+			if doc := explain(d.Key); doc != "" {
+				fmt.Fprintf(w, "# %s: %s\n", d.Key, doc)
+			}
+		}
+	}
+	return true, nil
+}
+
+// configFieldExplainer returns the documentation of the fields of the config
+// struct cfg by their dotted keys, as explain shows it, for writeConfigDiff.
+// Unknown and undocumented fields have none.
+func configFieldExplainer(cfg interface{}, docs map[string]string) func(key string) string {
+	return func(key string) string {
+		name, field, _, err := lookupConfigField(cfg, key)
+		if err != nil {
+			return ""
+		}
+		return configFieldDoc(field, docs, name)
+	}
+}
+
+// selectFields returns the fields requested with --select, if any.
+func selectFields(c *clipkg.Context) []string {
+	var fields []string
+	for _, f := range strings.Split(c.String("select"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// tsvEscaper keeps values on a single TSV cell.
+var tsvEscaper = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// PageWithLinks is a page of resources with the pagination links of the
+// response, as rendered by the list commands with --with-links.
+type PageWithLinks struct {
+	Data  interface{}       `json:"data"`
+	Links map[string]string `json:"links"`
+}
+
+// renderPageWithLinks renders the given page of requestURI, deserialized into
+// model, together with its pagination links, so tools can page through
+// programmatically. It requires JSON output.
+func (cli *Client) renderPageWithLinks(c *clipkg.Context, requestURI string, model interface{}) error {
+	switch cli.Renderer.(type) {
+	case RendererJSON, sortedKeysRenderer:
+	default:
+		return usageError(c, errors.New("--with-links requires JSON output (--json)"))
+	}
+	links, err := cli.fetchPage(requestURI, c.Int("page"), model)
+	if err != nil {
+		return err
+	}
+	page := PageWithLinks{Data: model, Links: map[string]string{}}
+	for name, link := range links {
+		page.Links[name] = link.Href
+	}
+	return cli.Render(page)
+}
+
+// writeRaw writes the body of the response to a GET of requestURI, at the
+// given page if positive, to w exactly as received, bypassing deserialization
+// and presenters. An error status is still returned after the body is written,
+// so the exit code reflects it.
+func (cli *Client) writeRaw(w io.Writer, requestURI string, page int) (err error) {
+	uri, err := url.Parse(requestURI)
+	if err != nil {
+		return err
+	}
+	if page > 0 {
+		q := uri.Query()
+		q.Set("page", strconv.Itoa(page))
+		uri.RawQuery = q.Encode()
+	}
+	resp, err := cli.HTTP.Get(uri.String())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	b, err := parseResponse(resp)
+	if _, werr := w.Write(b); werr != nil {
+		return multierr.Append(err, werr)
+	}
+	if err != nil {
+		return errors.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// executeTemplateFile executes the text/template in the file at path for each
+// resource in the slice resources, or once over the whole slice if once is
+// set, writing the output to w. Templates control their own separators, so
+// per resource templates usually end with a newline. Parse and execution
+// errors include the file name and line.
+func executeTemplateFile(w io.Writer, path string, resources interface{}, once bool) error {
+	buf, err := fromFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read template file '%s'", path)
+	}
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(buf.String())
+	if err != nil {
+		return errors.Wrap(err, "failed to parse template")
+	}
+	if once {
+		return errors.Wrap(tmpl.Execute(w, resources), "failed to execute template")
+	}
+	v := reflect.ValueOf(resources)
+	for i := 0; i < v.Len(); i++ {
+		if err = tmpl.Execute(w, v.Index(i).Interface()); err != nil {
+			return errors.Wrapf(err, "failed to execute template for item %d", i)
+		}
+	}
+	return nil
+}
+
+// writeSelected writes the selected fields of each resource in the slice
+// resources to w as tab separated values, one row per resource. Fields are
+// dotted paths matched case-insensitively against a resource's JSON form, with
+// ID resolving to its JSON API ID. Missing values are written as empty cells.
+func writeSelected(w io.Writer, resources interface{}, fields []string, headers bool) error {
+	if headers {
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	rv := reflect.ValueOf(resources)
+	for i := 0; i < rv.Len(); i++ {
+		obj, err := resourceObject(rv.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		cells := make([]string, len(fields))
+		for j, f := range fields {
+			if v, ok := selectPath(obj, f); ok && v != nil {
+				cells[j] = tsvEscaper.Replace(formatScalar(v))
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceObject returns resource as a generic JSON object, including its ID.
+func resourceObject(resource interface{}) (map[string]interface{}, error) {
+	generic, err := toGenericConfig(resource)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("cannot select fields from %T", resource)
+	}
+	if r, ok := resource.(interface{ GetID() string }); ok {
+		obj["id"] = r.GetID()
+	}
+	return obj, nil
+}
+
+// projection is a parsed --jq expression: a subset of jq consisting of a
+// path, such as .config.Commitment, or an object of paths, such as
+// {id: .id, commitment: .config.Commitment}. A path of just . is the whole
+// resource.
+type projection struct {
+	keys  []string   // keys of the object, nil for a single path
+	paths [][]string // path segments of each key
+}
+
+var projectionKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseProjection parses a --jq expression.
+func parseProjection(expr string) (*projection, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "{") {
+		segs, err := parseProjectionPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &projection{paths: [][]string{segs}}, nil
+	}
+	if !strings.HasSuffix(expr, "}") {
+		return nil, errors.Errorf("invalid --jq expression '%s': missing closing '}'", expr)
+	}
+	p := &projection{keys: []string{}}
+	inner := strings.TrimSpace(expr[1 : len(expr)-1])
+	if inner == "" {
+		return p, nil
+	}
+	for _, entry := range strings.Split(inner, ",") {
+		parts := strings.SplitN(entry, ":", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || !projectionKey.MatchString(key) {
+			return nil, errors.Errorf("invalid --jq expression '%s': expected key: .path, got '%s'", expr, strings.TrimSpace(entry))
+		}
+		segs, err := parseProjectionPath(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		p.keys = append(p.keys, key)
+		p.paths = append(p.paths, segs)
+	}
+	return p, nil
+}
+
+func parseProjectionPath(path string) ([]string, error) {
+	if path == "." {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, ".") {
+		return nil, errors.Errorf("invalid --jq path '%s': must start with '.'", path)
+	}
+	segs := strings.Split(path[1:], ".")
+	for _, seg := range segs {
+		if seg == "" || strings.ContainsAny(seg, " \t{}[]|") {
+			return nil, errors.Errorf("invalid --jq path '%s': only .field segments are supported", path)
+		}
+	}
+	return segs, nil
+}
+
+// apply evaluates the projection against obj. Like jq, missing fields are
+// null, but indexing a value which is not an object fails.
+func (p *projection) apply(obj interface{}) (json.RawMessage, error) {
+	if p.keys == nil {
+		v, err := projectPath(obj, p.paths[0])
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range p.keys {
+		v, err := projectPath(obj, p.paths[i])
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%q:%s", key, vb)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func projectPath(v interface{}, segs []string) (interface{}, error) {
+	for i, seg := range segs {
+		switch typed := v.(type) {
+		case nil:
+			return nil, nil
+		case map[string]interface{}:
+			v = typed[seg]
+		default:
+			return nil, errors.Errorf("cannot index %s with '%s' at .%s", jsonTypeName(v), seg, strings.Join(segs[:i+1], "."))
+		}
+	}
+	return v, nil
+}
+
+// jsonTypeName returns the JSON type of a generic JSON value.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jqFlag returns the parsed --jq expression, or nil if it is not set.
+func jqFlag(c *clipkg.Context) (*projection, error) {
+	expr := c.String("jq")
+	if expr == "" {
+		return nil, nil
+	}
+	p, err := parseProjection(expr)
+	if err != nil {
+		return nil, usageError(c, err)
+	}
+	return p, nil
+}
+
+// writeProjected writes the projection of each of resources, a slice of
+// presenters, to w as a line of JSON.
+func writeProjected(w io.Writer, resources interface{}, p *projection) error {
+	rv := reflect.ValueOf(resources)
+	for i := 0; i < rv.Len(); i++ {
+		obj, err := resourceObject(rv.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		b, err := p.apply(obj)
+		if err != nil {
+			return errors.Wrapf(err, "--jq failed for chain %v", obj["id"])
+		}
+		if _, err = fmt.Fprintf(w, "%s\n", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectPath is like lookupConfigPath, but matches object keys
+// case-insensitively, preferring an exact match, and reports missing values
+// instead of failing.
+func selectPath(v interface{}, path string) (interface{}, bool) {
+	for _, seg := range strings.Split(path, ".") {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			val, ok := typed[seg]
+			if !ok {
+				for k, kv := range typed {
+					if strings.EqualFold(k, seg) {
+						val, ok = kv, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, false
+			}
+			v = val
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(typed) {
+				return nil, false
+			}
+			v = typed[idx]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// progress reports the per item progress of bulk operations on stderr.
+// It is safe for concurrent use.
+type progress struct {
+	w     io.Writer
+	total int
+
+	mu sync.Mutex
+	n  int
+}
+
+// writeFileAtomic writes data to a temporary file next to path, then renames
+// it over path, so that readers see either the old or the new contents in
+// full. Like ioutil.TempFile, the file is only accessible to the user, as
+// chain configs can contain secrets.
+func writeFileAtomic(path string, data []byte) (err error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// chainBodyEncoders encode the attributes of chain create and update requests
+// as their bodies, by --chain-body-format: plain sends the attributes as the
+// JSON object itself, and jsonapi wraps them in the data of a JSON API
+// document of resourceType.
+var chainBodyEncoders = map[string]func(resourceType string, attributes map[string]interface{}) ([]byte, error){
+	"plain": func(_ string, attributes map[string]interface{}) ([]byte, error) {
+		return json.Marshal(attributes)
+	},
+	"jsonapi": func(resourceType string, attributes map[string]interface{}) ([]byte, error) {
+		return json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"type":       resourceType,
+				"attributes": attributes,
+			},
+		})
+	},
+}
+
+// encodeChainBody encodes attributes as the body of a chain request for
+// resources of resourceType, in the shape set by cli.ChainBodyFormat.
+func (cli *Client) encodeChainBody(resourceType string, attributes map[string]interface{}) ([]byte, error) {
+	format := cli.ChainBodyFormat
+	if format == "" {
+		format = "plain"
+	}
+	encode, ok := chainBodyEncoders[format]
+	if !ok {
+		return nil, errors.Errorf("unsupported chain body format '%s', options: [plain, jsonapi]", format)
+	}
+	return encode(resourceType, attributes)
+}
+
+// requestDump writes the JSON bodies of the requests a command sends to the
+// file of --dump-request, one per line, so that the exact payload can be
+// attached to a bug report or replayed. With --dry-run and no file, they are
+// written to stdout. Config values are only redacted if --redact is set.
+type requestDump struct {
+	w        io.Writer
+	file     *os.File
+	redact   bool
+	patterns []string
+}
+
+// openRequestDump returns the dump of the command's requests, or nil if
+// neither --dump-request nor --dry-run is set. It must be closed.
+func openRequestDump(c *clipkg.Context, stdout io.Writer) (*requestDump, error) {
+	d := &requestDump{w: stdout, redact: c.IsSet("redact"), patterns: c.StringSlice("redact")}
+	path := c.String("dump-request")
+	if path == "" {
+		if !c.Bool("dry-run") {
+			return nil, nil
+		}
+		return d, nil
+	}
+	// Like the config files written by get-config, this can contain secrets
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open --dump-request file")
+	}
+	d.w, d.file = f, f
+	return d, nil
+}
+
+// redactBodyConfig redacts the config field of the request body, or of the
+// attributes of its JSON API data with --chain-body-format=jsonapi.
+func redactBodyConfig(body []byte, patterns []string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	if config, ok := fields["config"]; ok {
+		var generic interface{}
+		if err := json.Unmarshal(config, &generic); err != nil {
+			return nil, err
+		}
+		redacted, err := redactConfig(generic, patterns)
+		if err != nil {
+			return nil, err
+		}
+		if fields["config"], err = json.Marshal(redacted); err != nil {
+			return nil, err
+		}
+	} else if data, ok := fields["data"]; ok {
+		var resource map[string]json.RawMessage
+		if err := json.Unmarshal(data, &resource); err != nil {
+			return nil, err
+		}
+		if attributes, ok := resource["attributes"]; ok {
+			var err error
+			if resource["attributes"], err = redactBodyConfig(attributes, patterns); err != nil {
+				return nil, err
+			}
+			if fields["data"], err = json.Marshal(resource); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// write dumps body, the JSON body of a request about to be sent.
+func (d *requestDump) write(body []byte) error {
+	if d == nil {
+		return nil
+	}
+	if d.redact {
+		var err error
+		if body, err = redactBodyConfig(body, d.patterns); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(d.w, string(body))
+	return err
+}
+
+func (d *requestDump) close() error {
+	if d == nil || d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+// matchChainIDs returns the IDs matching the glob pattern, as understood by
+// path.Match, in their original order. It is an error for none to match.
+func matchChainIDs(pattern string, ids []string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, errors.Wrapf(err, "invalid --match pattern '%s'", pattern)
+	}
+	var matched []string
+	for _, id := range ids {
+		if ok, _ := path.Match(pattern, id); ok {
+			matched = append(matched, id)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, errors.Errorf("no chains match '%s'", pattern)
+	}
+	return matched, nil
+}
+
+// confirmConfigChanges prompts with prompter for each field changed from
+// config from to config to, and returns the generic config from with only the
+// approved changes applied. Answering "a" approves the remaining changes, and
+// "q" aborts with errAborted.
+func confirmConfigChanges(prompter Prompter, from, to interface{}) (approved interface{}, changed int, err error) {
+	diffs, err := diffConfigs(from, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	if approved, err = toGenericConfig(from); err != nil {
+		return nil, 0, err
+	}
+	all := false
+	for _, d := range diffs {
+		if !all {
+			answer := strings.ToLower(strings.TrimSpace(prompter.Prompt(fmt.Sprintf("%s: %s -> %s, apply? [y/N/a(ll)/q] ", d.Key, formatConfigValue(d.From), formatConfigValue(d.To)))))
+			switch answer {
+			case "y", "yes":
+			case "a", "all":
+				all = true
+			case "q", "quit":
+				return nil, 0, errAborted
+			default:
+				continue
+			}
+		}
+		if approved, err = setConfigPath(approved, strings.Split(d.Key, "."), d.To); err != nil {
+			return nil, 0, err
+		}
+		changed++
+	}
+	return approved, changed, nil
+}
+
+// setConfigPath sets the value at the path segments of the generic config v,
+// creating objects along the way, and returns the updated config.
+func setConfigPath(v interface{}, segs []string, value interface{}) (interface{}, error) {
+	if len(segs) == 0 {
+		return value, nil
+	}
+	switch typed := v.(type) {
+	case nil:
+		child, err := setConfigPath(nil, segs[1:], value)
+		return map[string]interface{}{segs[0]: child}, err
+	case map[string]interface{}:
+		child, err := setConfigPath(typed[segs[0]], segs[1:], value)
+		typed[segs[0]] = child
+		return typed, err
+	case []interface{}:
+		i, err := strconv.Atoi(segs[0])
+		if err != nil || i < 0 || i >= len(typed) {
+			return nil, errors.Errorf("invalid array index %q", segs[0])
+		}
+		typed[i], err = setConfigPath(typed[i], segs[1:], value)
+		return typed, err
+	default:
+		return nil, errors.Errorf("cannot set field %q of %s", segs[0], jsonTypeName(v))
+	}
+}
+
+// maxSimilarChainIDs is the number of suggestions of similarChainIDs.
+const maxSimilarChainIDs = 5
+
+// similarChainIDs returns up to maxSimilarChainIDs of ids sharing a prefix
+// with id of at least half its length, most similar first.
+func similarChainIDs(id string, ids []string) []string {
+	minPrefix := (len(id) + 1) / 2
+	type candidate struct {
+		id     string
+		prefix int
+	}
+	var candidates []candidate
+	for _, other := range ids {
+		n := 0
+		for n < len(id) && n < len(other) && id[n] == other[n] {
+			n++
+		}
+		if n >= minPrefix && n > 0 {
+			candidates = append(candidates, candidate{other, n})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].prefix > candidates[j].prefix })
+	var similar []string
+	for i := 0; i < len(candidates) && i < maxSimilarChainIDs; i++ {
+		similar = append(similar, candidates[i].id)
+	}
+	return similar
+}
+
+// assumeYes reports whether the prompts of the command run on c are to be
+// answered yes, by the global --assume-yes or one of the command's own flags.
+func (cli *Client) assumeYes(c *clipkg.Context, flags ...string) bool {
+	if cli.AssumeYes {
+		return true
+	}
+	for _, f := range flags {
+		if c.Bool(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// confirmChainMatches asks the user to confirm an action on the chains
+// selected by --match, --ids-stdin or --all, unless --force or --assume-yes
+// is set. A wildcard can select many more chains than intended, so without a
+// terminal to prompt on this fails rather than proceeding.
+func (cli *Client) confirmChainMatches(c *clipkg.Context, action string, chainIDs []string) error {
+	var selection string
+	switch {
+	case c.String("match") != "":
+		selection = fmt.Sprintf("%d chain(s) matching '%s'", len(chainIDs), c.String("match"))
+	case c.Bool("ids-stdin"):
+		selection = fmt.Sprintf("%d chain(s) read from stdin", len(chainIDs))
+	default:
+		selection = fmt.Sprintf("all %d chain(s)", len(chainIDs))
+	}
+	return cli.confirm(c, fmt.Sprintf("%s %s?", action, selection), false, strings.ToLower(action)+" "+selection, "force")
+}
+
+// confirmPrompter is a terminal prompter for confirmations, which reports a
+// terminal only if stdin is one. Bulk commands may read their input from a
+// piped stdin, which then cannot answer prompts.
+type confirmPrompter struct {
+	Prompter
+}
+
+func newConfirmPrompter() Prompter {
+	return confirmPrompter{NewTerminalPrompter()}
+}
+
+// IsTerminal reports whether stdin is a terminal.
+func (confirmPrompter) IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// confirmationPrompter returns the prompter of the chain commands'
+// confirmations, cli.ConfirmPrompter if set.
+func (cli *Client) confirmationPrompter() Prompter {
+	if cli.ConfirmPrompter != nil {
+		return cli.ConfirmPrompter
+	}
+	return newConfirmPrompter()
+}
+
+// errNoTerminal is returned by confirm when there is no terminal to prompt on.
+var errNoTerminal = errors.New("no terminal to prompt for confirmation on")
+
+// confirm asks prompt, a yes or no question, with prompter. It returns nil if
+// the user answers yes, and errAborted if they answer no. An empty answer is
+// def, and any other answer is asked again. Without a terminal it returns
+// errNoTerminal, as there is nobody to answer.
+func confirm(prompter Prompter, prompt string, def bool) error {
+	if !prompter.IsTerminal() {
+		return errNoTerminal
+	}
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	for {
+		switch answer := strings.ToLower(strings.TrimSpace(prompter.Prompt(fmt.Sprintf("%s %s ", prompt, hint)))); answer {
+		case "y", "yes":
+			return nil
+		case "n", "no":
+			return errAborted
+		case "":
+			if def {
+				return nil
+			}
+			return errAborted
+		default:
+			fmt.Printf("%s is not valid. Please type yes or no\n", answer)
+		}
+	}
+}
+
+// confirm asks prompt with the confirmation prompter, unless --assume-yes or
+// one of the command's flags is set. Without a terminal it refuses to do
+// what, asking for the first of flags instead, so that scripts must opt in
+// rather than proceeding unconfirmed.
+func (cli *Client) confirm(c *clipkg.Context, prompt string, def bool, what string, flags ...string) error {
+	if cli.assumeYes(c, flags...) {
+		return nil
+	}
+	err := confirm(cli.confirmationPrompter(), prompt, def)
+	if errors.Is(err, errNoTerminal) {
+		return errors.Errorf("refusing to %s without confirmation: pass --%s", what, flags[0])
+	}
+	return err
+}
+
+// maxListedChainIDs is the most chain IDs listed by confirmBulkConfigure,
+// beyond which only their count is shown.
+const maxListedChainIDs = 20
+
+// confirmBulkConfigure summarizes the config changes, the key=value arguments
+// or files, about to be applied to chainIDs to w, and asks the user to
+// confirm them, defaulting to no. Without a terminal to prompt on this fails
+// rather than proceeding.
+func confirmBulkConfigure(prompter Prompter, w io.Writer, changes, chainIDs []string) error {
+	if !prompter.IsTerminal() {
+		return errors.Errorf("refusing to configure %d chain(s) without confirmation: pass --yes", len(chainIDs))
+	}
+	fmt.Fprintf(w, "About to apply {%s} to %d chain(s)", strings.Join(changes, ", "), len(chainIDs))
+	if len(chainIDs) <= maxListedChainIDs {
+		fmt.Fprintf(w, ": %s", strings.Join(chainIDs, ", "))
+	}
+	fmt.Fprintln(w, ".")
+	return confirm(prompter, "Continue?", false)
+}
+
+// readChainIDsJSON reads the chain IDs of a bulk operation from r, for
+// --ids-stdin, as a JSON array of strings, e.g. ["devnet","testnet"].
+func readChainIDsJSON(r io.Reader) ([]string, error) {
+	var v interface{}
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "--ids-stdin: invalid JSON")
+	}
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("--ids-stdin: expected a JSON array of chain IDs, got JSON %s", jsonTypeName(v))
+	}
+	if len(arr) == 0 {
+		return nil, errors.New("--ids-stdin: no chain IDs given")
+	}
+	ids := make([]string, len(arr))
+	for i, el := range arr {
+		id, ok := el.(string)
+		if !ok {
+			return nil, errors.Errorf("--ids-stdin: element %d is JSON %s, not a chain ID string", i, jsonTypeName(el))
+		}
+		if id == "" {
+			return nil, errors.Errorf("--ids-stdin: element %d is an empty chain ID", i)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// chainIDsFlag returns the chain IDs passed with -id, which may be repeated or
+// comma separated.
+func chainIDsFlag(c *clipkg.Context) []string {
+	var ids []string
+	for _, id := range strings.Split(c.String("id"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// newProgress returns a progress reporter for an operation on total items.
+// Reporting is disabled for single items, when stderr is not a terminal, or
+// when --quiet is set, to keep the output of scripts clean.
+func newProgress(c *clipkg.Context, total int) *progress {
+	p := &progress{total: total}
+	if total > 1 && !c.Bool("quiet") && term.IsTerminal(int(os.Stderr.Fd())) {
+		p.w = os.Stderr
+	}
+	return p
+}
+
+// step reports that work on the next item has started.
+func (p *progress) step(format string, args ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.n++
+	if p.w == nil {
+		return
+	}
+	fmt.Fprintf(p.w, "[%d/%d] %s\n", p.n, p.total, fmt.Sprintf(format, args...))
+}
+
+// maxChainConcurrency caps --max-concurrency, so bulk operations cannot
+// overwhelm the node.
+const maxChainConcurrency = 16
+
+// concurrencyFlag returns the --max-concurrency of c, capped to
+// maxChainConcurrency. It defaults to 1, for sequential processing.
+func concurrencyFlag(c *clipkg.Context) (int, error) {
+	if !c.IsSet("max-concurrency") {
+		return 1, nil
+	}
+	n := c.Int("max-concurrency")
+	if n < 1 {
+		return 0, errors.Errorf("--max-concurrency must be at least 1, got %d", n)
+	}
+	if n > maxChainConcurrency {
+		fmt.Fprintf(os.Stderr, "WARNING: --max-concurrency %d exceeds the maximum, using %d\n", n, maxChainConcurrency)
+		n = maxChainConcurrency
+	}
+	return n, nil
+}
+
+// errSkippedAfterFailure marks the items of a bulk operation which were not
+// attempted, as an earlier item failed with --on-error=abort.
+var errSkippedAfterFailure = errors.New("skipped after an earlier failure with --on-error=abort")
+
+// onErrorFlag returns whether a bulk operation stops at its first failure, by
+// --on-error: with continue, the default, every item is attempted and their
+// errors are aggregated, and with abort the remaining items are skipped.
+func onErrorFlag(c *clipkg.Context) (abort bool, err error) {
+	switch policy := c.String("on-error"); policy {
+	case "", "continue":
+		return false, nil
+	case "abort":
+		return true, nil
+	default:
+		return false, usageError(c, errors.Errorf("unsupported --on-error '%s', options: [continue, abort]", policy))
+	}
+}
+
+// abortOnFailure wraps fn, as passed to forEachConcurrently, so that once an
+// item fails the items not started yet fail with errSkippedAfterFailure
+// instead. With several workers, the items already in progress on the others
+// still complete. Chains found to be absent are not failures.
+func abortOnFailure(fn func(i int) error) func(i int) error {
+	var failed int32
+	return func(i int) error {
+		if atomic.LoadInt32(&failed) == 1 {
+			return errSkippedAfterFailure
+		}
+		err := fn(i)
+		if err != nil && !errors.Is(err, errChainAbsent) {
+			atomic.StoreInt32(&failed, 1)
+		}
+		return err
+	}
+}
+
+// forEachConcurrently calls fn for the items 0 to count-1 from a pool of up
+// to concurrency workers, and returns the error for each item by index, so
+// results can be reported in order regardless of completion order. Once an
+// item fails with context.Canceled, no further items are started, and those
+// not started fail with context.Canceled too.
+func forEachConcurrently(concurrency, count int, fn func(i int) error) []error {
+	errs := make([]error, count)
+	items := make(chan int)
+	var canceled int32
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				if atomic.LoadInt32(&canceled) == 1 {
+					errs[i] = context.Canceled
+					continue
+				}
+				errs[i] = fn(i)
+				if errors.Is(errs[i], context.Canceled) {
+					atomic.StoreInt32(&canceled, 1)
+				}
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		items <- i
+	}
+	close(items)
+	wg.Wait()
+	return errs
+}
+
+// NoDeprecationWarningsEnv suppresses deprecation warnings when set to true,
+// for scripts which cannot migrate yet.
+const NoDeprecationWarningsEnv = "CL_NO_DEPRECATION_WARNINGS"
+
+// deprecationWarning is written at most once per process.
+type deprecationWarning struct {
+	once sync.Once
+	msg  string
+}
+
+var positionalConfigDeprecation = &deprecationWarning{
+	msg: "WARNING: passing the config as a positional argument is deprecated, use --config-json or --config-file instead. Set " + NoDeprecationWarningsEnv + "=true to silence this warning.",
+}
+
+// warn writes the warning to w, unless it was already written or
+// NoDeprecationWarningsEnv is set.
+func (d *deprecationWarning) warn(w io.Writer) {
+	if suppress, _ := strconv.ParseBool(os.Getenv(NoDeprecationWarningsEnv)); suppress {
+		return
+	}
+	d.once.Do(func() {
+		fmt.Fprintln(w, d.msg)
+	})
+}
+
+// usageError appends the help of the command of c to err, an invalid
+// arguments error, so users see how to fix the call without running --help.
+// Outside of a command, err is returned as is.
+func usageError(c *clipkg.Context, err error) error {
+	if c.Command.Name == "" {
+		return err
+	}
+	var help bytes.Buffer
+	clipkg.HelpPrinter(&help, clipkg.CommandHelpTemplate, c.Command)
+	return &errUsage{err: err, help: strings.TrimRight(help.String(), "\n")}
+}
+
+type errUsage struct {
+	err  error
+	help string
+}
+
+func (e *errUsage) Error() string {
+	return e.err.Error() + "\n\n" + e.help
+}
+
+func (e *errUsage) Unwrap() error {
+	return e.err
+}
+
+// flagConflict is a pair of flags which cannot be combined. A flag given as
+// name=value only conflicts when set to that value.
+type flagConflict [2]string
+
+// indexChainsConflicts are the conflicting flags of the chain list commands.
+var indexChainsConflicts = []flagConflict{
+	{"with-links", "raw"}, {"with-links", "select"}, {"with-links", "template-file"}, {"with-links", "wide"},
+	{"raw", "select"}, {"raw", "template-file"}, {"raw", "wide"},
+	{"select", "template-file"}, {"select", "wide"},
+	{"template-file", "wide"},
+	{"headers", "no-header"},
+	{"jq", "raw"}, {"jq", "select"}, {"jq", "template-file"}, {"jq", "wide"}, {"jq", "with-links"},
+}
+
+// checkFlagConflicts returns an error for the first of conflicts whose flags
+// are both set on c, rather than silently ignoring one of them.
+func checkFlagConflicts(c *clipkg.Context, conflicts ...flagConflict) error {
+	for _, fc := range conflicts {
+		if flagActive(c, fc[0]) && flagActive(c, fc[1]) {
+			return usageError(c, errors.Errorf("--%s cannot be combined with --%s", fc[0], fc[1]))
+		}
+	}
+	return nil
+}
+
+// flagActive reports whether the flag spec, a name or name=value, was set on
+// c. Boolean flags set to false are not active.
+func flagActive(c *clipkg.Context, spec string) bool {
+	parts := strings.SplitN(spec, "=", 2)
+	if !c.IsSet(parts[0]) {
+		return false
+	}
+	value := c.String(parts[0])
+	if len(parts) == 2 {
+		return value == parts[1]
+	}
+	return value != "" && value != "false"
+}
+
+// chainConfigFromFlags returns the chain config passed via --config-json,
+// --config-file, which may be repeated to deep-merge several files, or
+// fetched from --config-url. The
+// deprecated positional argument, which is guessed to be either a JSON blob or
+// a filepath, is still accepted with a warning.
+func chainConfigFromFlags(c *clipkg.Context) (json.RawMessage, error) {
+	configJSON, configFiles, configURL := c.String("config-json"), c.StringSlice("config-file"), c.String("config-url")
+	sources := 0
+	for _, set := range []bool{configJSON != "", len(configFiles) > 0, configURL != "", c.Args().Present()} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return nil, usageError(c, errors.New("must pass in the chain's parameters [-id string] [--config-json JSON blob | --config-file JSON filepath | --config-url URL]"))
+	case sources > 1:
+		return nil, usageError(c, errors.New("only one of --config-json, --config-file, --config-url or a positional config argument may be given"))
+	}
+
+	jsonc, strict := c.Bool("jsonc"), c.Bool("strict-json")
+	switch {
+	case configURL != "":
+		raw, err := fetchChainConfig(configURL, c.StringSlice("config-url-header"), jsonc)
+		if err == nil && strict {
+			err = errors.Wrapf(checkDuplicateKeys(raw), "config at %s", configURL)
+		}
+		return raw, err
+	case configJSON != "":
+		raw, err := inlineChainConfig(configJSON, jsonc)
+		if err == nil && strict {
+			err = errors.Wrap(checkDuplicateKeys(raw), "--config-json")
+		}
+		return raw, err
+	case len(configFiles) > 0:
+		if strict {
+			// Merging goes through maps, which silently keep the last of any
+			// duplicated keys, so each file has to be checked on its own.
+			for _, path := range configFiles {
+				raw, err := chainConfigFile(path, jsonc)
+				if err != nil {
+					return nil, err
+				}
+				if err = checkDuplicateKeys(raw); err != nil {
+					return nil, errors.Wrapf(err, "config file '%s'", path)
+				}
+			}
+		}
+		var verbose io.Writer
+		if c.Bool("verbose") {
+			verbose = os.Stderr
+		}
+		return mergeChainConfigFiles(verbose, configFiles, jsonc)
+	}
+	positionalConfigDeprecation.warn(os.Stderr)
+	arg := c.Args().First()
+	if trimmed := strings.TrimSpace(arg); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		raw, err := inlineChainConfig(arg, jsonc)
+		if err == nil && strict {
+			err = errors.Wrap(checkDuplicateKeys(raw), "config argument")
+		}
+		return raw, err
+	}
+	raw, err := chainConfigFile(arg, jsonc)
+	if err == nil && strict {
+		err = errors.Wrapf(checkDuplicateKeys(raw), "config file '%s'", arg)
+	}
+	return raw, err
+}
+
+// trimChainConfig removes the null fields of raw, and the objects left empty
+// by that, recursively, returning the minimal config and how many fields were
+// removed. Array elements are kept, so that indexes are unchanged, and so is
+// the top-level object even if empty.
+func trimChainConfig(raw json.RawMessage) (json.RawMessage, int, error) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var config interface{}
+	if err := d.Decode(&config); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to parse the config to trim")
+	}
+	trimmed := trimConfigValue(config)
+	b, err := json.Marshal(config)
+	if err != nil {
+		return nil, 0, err
+	}
+	return b, trimmed, nil
+}
+
+func trimConfigValue(v interface{}) (trimmed int) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, field := range typed {
+			trimmed += trimConfigValue(field)
+			if obj, ok := field.(map[string]interface{}); field == nil || ok && len(obj) == 0 {
+				delete(typed, k)
+				trimmed++
+			}
+		}
+	case []interface{}:
+		for _, elem := range typed {
+			trimmed += trimConfigValue(elem)
+		}
+	}
+	return trimmed
+}
+
+// checkDuplicateKeys walks raw token by token and returns an error naming the
+// first object key that appears more than once in the same object, which
+// json.Unmarshal would otherwise resolve by silently keeping the last value.
+func checkDuplicateKeys(raw []byte) error {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	return scanDuplicateKeys(d, "")
+}
+
+func scanDuplicateKeys(d *json.Decoder, path string) error {
+	tok, err := d.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		seen := map[string]bool{}
+		for d.More() {
+			tok, err = d.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			if seen[key] {
+				where := path
+				if where == "" {
+					where = "the top level"
+				}
+				return errors.Errorf("duplicate key '%s' at %s", key, where)
+			}
+			seen[key] = true
+			if err = scanDuplicateKeys(d, path+"."+key); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	case json.Delim('['):
+		for i := 0; d.More(); i++ {
+			if err = scanDuplicateKeys(d, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	}
+	return err
+}
+
+// inlineChainConfig validates a chain config given as a JSON blob. If jsonc
+// is true, comments and trailing commas are stripped first.
+func inlineChainConfig(s string, jsonc bool) (json.RawMessage, error) {
+	raw := []byte(s)
+	if jsonc {
+		var err error
+		if raw, err = stripJSONC(raw); err != nil {
+			return nil, errors.Wrap(err, "inline config is not valid JSONC")
+		}
+	}
+	if err := validateJSON(raw); err != nil {
+		return nil, errors.Wrap(err, "inline config is not valid JSON")
+	}
+	return raw, nil
+}
+
+// chainConfigFile reads and validates a chain config from the JSON file at
+// path. If jsonc is true, comments and trailing commas are stripped first.
+func chainConfigFile(path string, jsonc bool) (json.RawMessage, error) {
+	buf, err := fromFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file '%s'", path)
+	}
+	raw := buf.Bytes()
+	if err = checkUTF8(raw); err != nil {
+		return nil, errors.Wrapf(err, "config file '%s' is not text", path)
+	}
+	raw = normalizeText(raw)
+	if jsonc {
+		if raw, err = stripJSONC(raw); err != nil {
+			return nil, errors.Wrapf(err, "config file '%s' is not valid JSONC", path)
+		}
+	}
+	if err = validateJSON(raw); err != nil {
+		return nil, errors.Wrapf(err, "config file '%s' is not valid JSON", path)
+	}
+	return raw, nil
+}
+
+// maxConfigURLSize is the largest chain config fetched by --config-url.
+const maxConfigURLSize = 1 << 20
+
+// configURLClient fetches the chain configs of --config-url. It is separate
+// from the node's client, so that the session cookie is never sent to the
+// config server.
+var configURLClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchChainConfig fetches and validates a chain config from the http or
+// https URL rawURL, sending each "Name: value" of headers, e.g. for auth to
+// the config server. If jsonc is true, comments and trailing commas are
+// stripped first.
+func fetchChainConfig(rawURL string, headers []string, jsonc bool) (json.RawMessage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("invalid --config-url %q: must be an http or https URL", rawURL)
+	}
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for _, h := range headers {
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("invalid --config-url-header %q: must be 'Name: value'", h)
+		}
+		req.Header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	resp, err := configURLClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the config")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("failed to fetch the config from %s: %s", rawURL, resp.Status)
+	}
+	raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxConfigURLSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the config from %s", rawURL)
+	}
+	if len(raw) > maxConfigURLSize {
+		return nil, errors.Errorf("config at %s exceeds the maximum size of %d bytes", rawURL, maxConfigURLSize)
+	}
+	if err = checkUTF8(raw); err != nil {
+		return nil, errors.Wrapf(err, "config at %s is not text", rawURL)
+	}
+	raw = normalizeText(raw)
+	if jsonc {
+		if raw, err = stripJSONC(raw); err != nil {
+			return nil, errors.Wrapf(err, "config at %s is not valid JSONC", rawURL)
+		}
+	}
+	if err = validateJSON(raw); err != nil {
+		return nil, errors.Wrapf(err, "config at %s is not valid JSON", rawURL)
+	}
+	return raw, nil
+}
+
+// mergeChainConfigFiles reads the chain configs in paths and deep-merges each
+// over the ones before it. A single file is used verbatim. If verbose is not
+// nil, the number of keys each file contributed to the final config is
+// written to it.
+func mergeChainConfigFiles(verbose io.Writer, paths []string, jsonc bool) (json.RawMessage, error) {
+	if len(paths) == 1 {
+		return chainConfigFile(paths[0], jsonc)
+	}
+	merged := map[string]interface{}{}
+	owners := map[string]int{}
+	for i, path := range paths {
+		raw, err := chainConfigFile(path, jsonc)
+		if err != nil {
+			return nil, err
+		}
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+		var config map[string]interface{}
+		if err = d.Decode(&config); err != nil || config == nil {
+			return nil, errors.Errorf("config file '%s' must contain a JSON object to be merged", path)
+		}
+		deepMergeConfig(merged, config, "", func(key string) {
+			for k := range owners {
+				if strings.HasPrefix(k, key+".") {
+					delete(owners, k)
+				}
+			}
+			owners[key] = i
+		})
+	}
+	if verbose != nil {
+		counts := make([]int, len(paths))
+		for _, i := range owners {
+			counts[i]++
+		}
+		for i, path := range paths {
+			fmt.Fprintf(verbose, "config file '%s': %d key(s) in the final config\n", path, counts[i])
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// deepMergeConfig merges src into dst. Objects present in both are merged
+// recursively, any other value in src replaces the one in dst. set is called
+// with the dotted key of each value taken from src.
+func deepMergeConfig(dst, src map[string]interface{}, prefix string, set func(key string)) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		srcObj, srcIsObj := v.(map[string]interface{})
+		dstObj, dstIsObj := dst[k].(map[string]interface{})
+		if srcIsObj && dstIsObj {
+			deepMergeConfig(dstObj, srcObj, key, set)
+			continue
+		}
+		dst[k] = v
+		set(key)
+	}
+}
+
+// stripJSONC converts JSON with comments to JSON, by blanking out // line and
+// /* block */ comments and trailing commas before a closing } or ]. Strings,
+// such as URLs containing //, are left untouched. Removed characters are
+// replaced by spaces, so that the offsets of syntax errors still match the
+// original input.
+func stripJSONC(b []byte) ([]byte, error) {
+	out := make([]byte, len(b))
+	copy(out, b)
+	blank := func(i int) {
+		if out[i] != '\n' {
+			out[i] = ' '
+		}
+	}
+	lastComma := -1 // offset of a comma which may turn out to be trailing
+	for i := 0; i < len(out); i++ {
+		switch ch := out[i]; {
+		case ch == '"':
+			lastComma = -1
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				blank(i)
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			for i += 2; i+1 < len(out) && !(out[i] == '*' && out[i+1] == '/'); i++ {
+			}
+			if i+1 >= len(out) {
+				return nil, errors.Errorf("unterminated block comment at offset %d", start)
+			}
+			for j := start; j <= i+1; j++ {
+				blank(j)
+			}
+			i++
+		case ch == ',':
+			lastComma = i
+		case ch == '}' || ch == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+	return out, nil
+}
+
+// validateJSON returns an error including the offset of the first syntax
+// error if b is not valid JSON.
+func validateJSON(b []byte) error {
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	var serr *json.SyntaxError
+	if errors.As(err, &serr) {
+		return errors.Errorf("at offset %d: %v", serr.Offset, serr)
+	}
+	return err
+}
+
+// compareTimes returns -1, 0 or 1 as a is before, equal to or after b.
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// warnChainConfigCompatibility writes a warning to w if the node's build
+// differs from the CLI's. The node does not report a version for its chain
+// config schema, so its build version stands in for it: config fields known
+// to this CLI may be unknown to a different node build, and vice versa.
+func (cli *Client) warnChainConfigCompatibility(w io.Writer, cliVersion, cliSha string) {
+	remoteVersion, remoteSha := "unknown", "unknown"
+	if info, err := cli.getNodeBuild(); err == nil {
+		remoteVersion, remoteSha = info.Version, info.CommitSHA
+	}
+	if remoteVersion == cliVersion && remoteSha == cliSha {
+		return
+	}
+	fmt.Fprintf(w, "WARNING: node build (%s@%s) differs from CLI build (%s@%s), so their chain config fields may not match. Upgrade the CLI to the node's version if the node rejects the config.\n", remoteVersion, remoteSha, cliVersion, cliSha)
+}
+
+// nodeBuild is the build of the node, as reported by /v2/build_info.
+type nodeBuild struct {
+	Version   string `json:"version"`
+	CommitSHA string `json:"commitSHA"`
+}
+
+// nodeBuildResult caches the outcome of the /v2/build_info request.
+type nodeBuildResult struct {
+	build nodeBuild
+	err   error
+}
+
+// getNodeBuild returns the build of the node. It is requested at most once
+// per invocation, however many checks need it.
+func (cli *Client) getNodeBuild() (nodeBuild, error) {
+	if cli.nodeBuild == nil {
+		var r nodeBuildResult
+		r.build, r.err = cli.fetchNodeBuild()
+		cli.nodeBuild = &r
+	}
+	return cli.nodeBuild.build, cli.nodeBuild.err
+}
+
+func (cli *Client) fetchNodeBuild() (info nodeBuild, err error) {
+	resp, err := cli.HTTP.Get("/v2/build_info")
+	if err != nil {
+		return info, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	b, err := parseResponse(resp)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(b, &info)
+	return info, errors.Wrap(err, "invalid build info")
+}
+
+// minNodeVersions maps chain commands, by their name without the leading
+// application name (e.g. "chains solana history"), to the oldest node version
+// serving the endpoints they use. chainAction refuses to run them against
+// older nodes, rather than letting them fail with an obscure 404. Add an entry
+// when a command starts depending on a new server endpoint or field which it
+// has no fallback for.
+var minNodeVersions = map[string]string{
+	// /v2/chains/solana/:ID/history is not served by 1.2.1 and earlier
+	"chains solana history": "1.3.0",
+}
+
+// requireNodeVersion returns an error if the node is older than min. Nodes
+// reporting a version which is not semver, such as development builds, are
+// assumed to be recent enough.
+func (cli *Client) requireNodeVersion(min string) error {
+	info, err := cli.getNodeBuild()
+	if err != nil {
+		return errors.Wrap(err, "failed to get node version")
+	}
+	v, err := semver.NewVersion(info.Version)
+	if err != nil {
+		return nil
+	}
+	if v.LessThan(semver.MustParse(min)) {
+		return errors.Errorf("this operation requires node version >= %s (node is %s)", min, info.Version)
+	}
+	return nil
+}
+
+// commandMinNodeVersion returns the minNodeVersions entry of the running
+// command, if any.
+func commandMinNodeVersion(c *clipkg.Context) (string, bool) {
+	min, ok := minNodeVersions[chainCommandName(c)]
+	return min, ok
+}
+
+// chainCommandName returns the name of the running command without the
+// leading application name, e.g. "solana chains history", or "" outside of
+// the application.
+func chainCommandName(c *clipkg.Context) string {
+	name := c.Command.HelpName
+	if i := strings.IndexByte(name, ' '); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// cachedGETCommands are the chain commands, by their name as in
+// minNodeVersions, which GET the same resources repeatedly, e.g. to cross
+// reference chains with their nodes and health. chainAction caches their GET
+// responses for the duration of the command. Commands which poll, such as
+// wait, must not be added.
+var cachedGETCommands = map[string]bool{
+	"chains solana stats":        true,
+	"chains solana doctor":       true,
+	"chains solana validate-all": true,
+	"chains solana show":         true,
+	"chains solana list":         true,
+}
+
+// errAborted is returned by chain commands interrupted by the user.
+var errAborted = errors.New("aborted by user")
+
+// chainAction wraps a chain command action so that, while it runs, cli.HTTP
+// sends its requests through the --proxy URL, if set, and is bound to a
+// context cancelled on SIGINT or SIGTERM. Ctrl-C then aborts in-flight
+// requests instead of waiting for them to complete.
+func (cli *Client) chainAction(action func(*clipkg.Context) error) func(*clipkg.Context) error {
+	return func(c *clipkg.Context) error {
+		orig := cli.HTTP
+		defer func() { cli.HTTP = orig }()
+
+		if proxy := c.String("proxy"); proxy != "" {
+			proxyURL, err := parseProxyURL(proxy)
+			if err != nil {
+				return cli.errorOut(err)
+			}
+			if h, ok := cli.HTTP.(proxyHTTPClient); ok {
+				cli.HTTP = h.WithProxy(proxyURL)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if h, ok := cli.HTTP.(contextHTTPClient); ok {
+			cli.HTTP = h.WithContext(ctx)
+		}
+		if c.IsSet("format-config") {
+			if err := checkChainConfigFormat(c.String("format-config")); err != nil {
+				return cli.errorOut(usageError(c, err))
+			}
+		}
+		if cachedGETCommands[chainCommandName(c)] {
+			cli.HTTP = newCachingHTTPClient(cli.HTTP, maxCachedGETBytes)
+		}
+		if cli.CheckMode {
+			// Applied last, so that nothing can get past it
+			cli.HTTP = readOnlyHTTPClient{cli.HTTP}
+		}
+		if min, ok := commandMinNodeVersion(c); ok {
+			if err := cli.requireNodeVersion(min); err != nil {
+				return cli.errorOut(err)
+			}
+		}
+		var err error
+		if path := c.String("output-file"); path != "" {
+			err = cli.runToOutputFile(c, path, action)
+		} else {
+			err = action(c)
+		}
+		if ctx.Err() != nil {
+			return cli.errorOut(errAborted)
+		}
+		return err
+	}
+}
+
+// runToOutputFile runs action with its output captured, then writes it to
+// path atomically, so that the file is left untouched if the action fails.
+// Warnings and logs still go to stderr. An existing file is only overwritten
+// with --force.
+func (cli *Client) runToOutputFile(c *clipkg.Context, path string, action func(*clipkg.Context) error) error {
+	if !c.Bool("force") {
+		if _, err := os.Stat(path); err == nil {
+			return cli.errorOut(usageError(c, errors.Errorf("output file '%s' already exists: pass --force to overwrite it", path)))
+		}
+	}
+	orig := cli.Renderer
+	defer func() { cli.Renderer = orig }()
+	var b bytes.Buffer
+	cli.Renderer = rendererWithWriter(orig, &b)
+	if err := action(c); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, b.Bytes()); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to write output file '%s'", path))
+	}
+	return nil
+}
+
+// rendererWithWriter returns a copy of r writing to w. Renderers without a
+// writer are replaced by a table renderer.
+func rendererWithWriter(r Renderer, w io.Writer) Renderer {
+	switch r := r.(type) {
+	case RendererTable:
+		r.Writer = w
+		return r
+	case RendererJSON:
+		r.Writer = w
+		return r
+	case sortedKeysRenderer:
+		r.Writer = w
+		return r
+	default:
+		return RendererTable{Writer: w}
+	}
+}
+
+// parseProxyURL parses and validates the --proxy URL.
+func parseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid --proxy URL %q", s)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("invalid --proxy URL %q: scheme must be one of http, https or socks5", s)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid --proxy URL %q: missing host", s)
+	}
+	return u, nil
+}
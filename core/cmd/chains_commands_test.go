@@ -0,0 +1,1387 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smartcontractkit/chainlink-solana/pkg/solana/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/smartcontractkit/chainlink/core/cmd"
+	"github.com/smartcontractkit/chainlink/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web/presenters"
+)
+
+func TestRedactConfig(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]interface{}{
+		"URL":         "http://node",
+		"APIKey":      "abc",
+		"AuthToken":   nil,
+		"Nested":      map[string]interface{}{"Password": "hunter2", "Port": 8080},
+		"Credentials": []interface{}{map[string]interface{}{"ClientSecret": "s"}},
+	}
+
+	t.Run("default patterns", func(t *testing.T) {
+		redacted, err := cmd.RedactConfig(config, []string{"secret", "key", "password", "token"})
+		require.NoError(t, err)
+		m := redacted.(map[string]interface{})
+		assert.Equal(t, "http://node", m["URL"])
+		assert.Equal(t, "***", m["APIKey"])
+		assert.Nil(t, m["AuthToken"])
+		nested := m["Nested"].(map[string]interface{})
+		assert.Equal(t, "***", nested["Password"])
+		assert.Equal(t, "8080", nested["Port"].(interface{ String() string }).String())
+		creds := m["Credentials"].([]interface{})
+		assert.Equal(t, "***", creds[0].(map[string]interface{})["ClientSecret"])
+	})
+
+	t.Run("custom patterns", func(t *testing.T) {
+		redacted, err := cmd.RedactConfig(config, []string{"url"})
+		require.NoError(t, err)
+		m := redacted.(map[string]interface{})
+		assert.Equal(t, "***", m["URL"])
+		assert.Equal(t, "abc", m["APIKey"])
+	})
+}
+
+func TestRedactConfig_KeyWords(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]interface{}{
+		"KeySpecific": map[string]interface{}{
+			"0xabc": map[string]interface{}{"EvmMaxGasPriceWei": "100", "SigningKey": "k", "private_key": "p"},
+		},
+		"MonkeyPatch":     "10",
+		"Keystone":        "ks",
+		"PrivateKey":      "s",
+		"ETH_API_KEY":     "e",
+		"RPCAuthToken":    "t",
+		"ClientSecrets":   "c",
+		"SecretKeyPath":   "/key",
+		"OCR2CachePeriod": "1s",
+	}
+	redacted, err := cmd.RedactConfig(config, []string{"secret", "key", "password", "token"})
+	require.NoError(t, err)
+	m := redacted.(map[string]interface{})
+	// KeySpecific is kept, with the secrets nested under it redacted
+	keySpecific := m["KeySpecific"].(map[string]interface{})["0xabc"].(map[string]interface{})
+	assert.Equal(t, "100", keySpecific["EvmMaxGasPriceWei"])
+	assert.Equal(t, "***", keySpecific["SigningKey"])
+	assert.Equal(t, "***", keySpecific["private_key"])
+	// Patterns match whole words only
+	assert.Equal(t, "10", m["MonkeyPatch"])
+	assert.Equal(t, "ks", m["Keystone"])
+	assert.Equal(t, "1s", m["OCR2CachePeriod"])
+	for _, key := range []string{"PrivateKey", "ETH_API_KEY", "RPCAuthToken", "ClientSecrets", "SecretKeyPath"} {
+		assert.Equal(t, "***", m[key], key)
+	}
+
+	// Patterns of several words match them in a row
+	redacted, err = cmd.RedactConfig(config, []string{"auth_token"})
+	require.NoError(t, err)
+	m = redacted.(map[string]interface{})
+	assert.Equal(t, "***", m["RPCAuthToken"])
+	assert.Equal(t, "s", m["PrivateKey"])
+}
+
+func TestDiffChains(t *testing.T) {
+	t.Parallel()
+
+	from := map[string]interface{}{
+		"a": map[string]interface{}{"TxTimeout": "1m", "Nodes": []interface{}{"x"}},
+		"b": map[string]interface{}{},
+	}
+	to := map[string]interface{}{
+		"a": map[string]interface{}{"TxTimeout": "1h", "Nodes": []interface{}{"x", "y"}},
+		"c": map[string]interface{}{},
+	}
+
+	diff, err := cmd.DiffChains(from, to)
+	require.NoError(t, err)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []string{"b"}, diff.OnlyInFrom)
+	assert.Equal(t, []string{"c"}, diff.OnlyInTo)
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, "a", diff.Changed[0].ID)
+	assert.Equal(t, []cmd.ConfigFieldDiff{
+		{Key: "Nodes.1", From: nil, To: "y"},
+		{Key: "TxTimeout", From: "1m", To: "1h"},
+	}, diff.Changed[0].Fields)
+
+	diff.From, diff.To = "staging", "prod"
+	var b bytes.Buffer
+	require.NoError(t, diff.RenderTable(cmd.RendererTable{Writer: &b}))
+	assert.Contains(t, b.String(), "Only on staging: b")
+	assert.Contains(t, b.String(), "Only on prod: c")
+	assert.Contains(t, b.String(), `TxTimeout: "1m" -> "1h"`)
+
+	same, err := cmd.DiffChains(from, from)
+	require.NoError(t, err)
+	assert.True(t, same.Empty())
+}
+
+func TestParseConfigParams(t *testing.T) {
+	t.Parallel()
+
+	params, err := cmd.ParseConfigParams([]string{"A=1", "B=foo", "C=true", "D=a=b", `E={"x":null}`})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"A": float64(1),
+		"B": "foo",
+		"C": true,
+		"D": "a=b",
+		"E": map[string]interface{}{"x": nil},
+	}, params)
+
+	_, err = cmd.ParseConfigParams([]string{"invalid"})
+	assert.EqualError(t, err, "invalid parameter: invalid")
+}
+
+func TestParseConfigParams_TypeHints(t *testing.T) {
+	t.Parallel()
+
+	params, err := cmd.ParseConfigParams([]string{"A=8080:string", "B=8080:int", "C=0.5:float", "D=true:bool", "E=1m:duration", `F=[1]:json`, "G=http://host:8080", "H=a:b:string"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"A": "8080",
+		"B": int64(8080),
+		"C": 0.5,
+		"D": true,
+		"E": "1m",
+		"F": []interface{}{float64(1)},
+		"G": "http://host:8080",
+		"H": "a:b",
+	}, params)
+
+	_, err = cmd.ParseConfigParams([]string{"A=1:number"})
+	assert.EqualError(t, err, `invalid parameter A=1:number: unknown type "number" (options: bool, duration, float, int, json, string)`)
+	_, err = cmd.ParseConfigParams([]string{"A=x:int"})
+	assert.EqualError(t, err, `invalid parameter A=x:int: not a valid int: strconv.ParseInt: parsing "x": invalid syntax`)
+	_, err = cmd.ParseConfigParams([]string{"A=soon:duration"})
+	assert.EqualError(t, err, `invalid parameter A=soon:duration: not a valid duration: time: invalid duration "soon"`)
+}
+
+func TestParseTypedConfigParams(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Timeout *models.Duration
+		Enabled null.Bool
+		Retries null.Int
+		Name    null.String
+		Peers   []string
+	}
+	params, err := cmd.ParseTypedConfigParams([]string{"Timeout=5s", "enabled=true", "Retries=3", "Name=123", `Peers=["a"]`}, config{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Timeout": "5s",
+		"Enabled": true,
+		"Retries": int64(3),
+		"Name":    "123",
+		"Peers":   []interface{}{"a"},
+	}, params)
+
+	// Type hints, null and references are not coerced
+	params, err = cmd.ParseTypedConfigParams([]string{"Name=null", "Retries=7:string", "Timeout={{.Name}}"}, config{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": nil, "Retries": "7", "Timeout": "{{.Name}}"}, params)
+
+	_, err = cmd.ParseTypedConfigParams([]string{"Timeout=soon"}, config{})
+	assert.EqualError(t, err, `invalid parameter Timeout=soon: not a valid duration: time: invalid duration "soon"`)
+	_, err = cmd.ParseTypedConfigParams([]string{"Enabled=maybe"}, config{})
+	assert.EqualError(t, err, `invalid parameter Enabled=maybe: not a valid bool: strconv.ParseBool: parsing "maybe": invalid syntax`)
+	_, err = cmd.ParseTypedConfigParams([]string{"Retries=1.5"}, config{})
+	assert.EqualError(t, err, `invalid parameter Retries=1.5: not a valid int: strconv.ParseInt: parsing "1.5": invalid syntax`)
+	_, err = cmd.ParseTypedConfigParams([]string{"Retrys=3"}, config{})
+	assert.EqualError(t, err, "invalid parameter Retrys=3: unknown field 'Retrys'; did you mean Retries?")
+}
+
+func TestParseTypedConfigParams_Unset(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Timeout *models.Duration
+		Name    null.String
+	}
+	params, err := cmd.ParseTypedConfigParams([]string{"Timeout=", "name=__unset__"}, config{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Timeout": nil, "Name": nil}, params)
+
+	// A type hint still sets the empty string
+	params, err = cmd.ParseTypedConfigParams([]string{"Name=:string"}, config{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Name": ""}, params)
+
+	params, err = cmd.ParseConfigParams([]string{"A=", "B=__unset__"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"A": nil, "B": nil}, params)
+
+	_, err = cmd.ParseTypedConfigParams([]string{"Timout="}, config{})
+	assert.EqualError(t, err, "invalid parameter Timout=: unknown field 'Timout'; did you mean Timeout?")
+}
+
+func TestReadKeyValueLines(t *testing.T) {
+	t.Parallel()
+
+	lines, err := cmd.ReadKeyValueLines(strings.NewReader("A=1\n\n# comment\n  B=2  \r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A=1", "B=2"}, lines)
+
+	lines, err = cmd.ReadKeyValueLines(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestValidateChainID(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		id   string
+		exp  string
+		err  string
+	}{
+		{"valid", "mainnet", "mainnet", ""},
+		{"trimmed", "  devnet\t", "devnet", ""},
+		{"empty", "", "", "missing chain ID [-id string]"},
+		{"whitespace only", "   ", "", "missing chain ID [-id string]"},
+		{"dot", ".", "", `invalid chain ID ".": reserved path segment`},
+		{"dot dot", " .. ", "", `invalid chain ID "..": reserved path segment`},
+		{"slash", "a/b", "", `invalid chain ID "a/b": must not contain any of '/', '?' or '#'`},
+		{"query", "a?b", "", `invalid chain ID "a?b": must not contain any of '/', '?' or '#'`},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := cmd.ValidateChainID(tt.id)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, id)
+		})
+	}
+}
+
+func TestChainConfigDiff_RenderTable(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	p := cmd.ChainConfigDiff{ID: "a", Fields: []cmd.ConfigFieldDiff{
+		{Key: "TxTimeout", From: "1m", To: "1h"},
+		{Key: "APIKey", From: nil, To: "secret"},
+	}}
+	require.NoError(t, p.RenderTable(cmd.RendererTable{Writer: &b}))
+	assert.Contains(t, b.String(), `"1h"`)
+	assert.NotContains(t, b.String(), "secret")
+
+	b.Reset()
+	require.NoError(t, cmd.ChainConfigDiff{ID: "a"}.RenderTable(cmd.RendererTable{Writer: &b}))
+	assert.Equal(t, "No config changes for chain a\n", b.String())
+}
+
+func TestLookupConfigPath(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]interface{}{
+		"RPC":   map[string]interface{}{"URL": "http://node", "Timeout": "5s"},
+		"Nodes": []interface{}{map[string]interface{}{"URL": "ws://a"}},
+	}
+
+	v, err := cmd.LookupConfigPath(config, "RPC.URL")
+	require.NoError(t, err)
+	assert.Equal(t, "http://node", v)
+
+	v, err = cmd.LookupConfigPath(config, "Nodes.0.URL")
+	require.NoError(t, err)
+	assert.Equal(t, "ws://a", v)
+
+	_, err = cmd.LookupConfigPath(config, "RPC.Missing")
+	assert.EqualError(t, err, "config field RPC.Missing not found")
+
+	_, err = cmd.LookupConfigPath(config, "Nodes.1.URL")
+	assert.EqualError(t, err, "config field Nodes.1 not found: index out of range [0, 1)")
+
+	_, err = cmd.LookupConfigPath(config, "RPC.URL.Host")
+	assert.EqualError(t, err, "config field RPC.URL.Host not found: RPC.URL is not an object or array")
+}
+
+func TestWriteSelected(t *testing.T) {
+	t.Parallel()
+
+	chains := cmd.SolanaChainPresenters{
+		{SolanaChainResource: presenters.SolanaChainResource{
+			JAID:    presenters.NewJAID("devnet"),
+			Enabled: true,
+			Config:  db.ChainCfg{Commitment: null.StringFrom("confirmed\tfast")},
+		}},
+		{SolanaChainResource: presenters.SolanaChainResource{
+			JAID:   presenters.NewJAID("testnet"),
+			Config: db.ChainCfg{SkipPreflight: null.BoolFrom(true)},
+		}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteSelected(&b, chains, []string{"ID", "enabled", "Config.Commitment", "Config.SkipPreflight", "Config.Missing.Nested"}, true))
+	assert.Equal(t, "ID\tenabled\tConfig.Commitment\tConfig.SkipPreflight\tConfig.Missing.Nested\n"+
+		"devnet\ttrue\tconfirmed\\tfast\t\t\n"+
+		"testnet\tfalse\t\ttrue\t\n", b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.WriteSelected(&b, chains[:1], []string{"ID"}, false))
+	assert.Equal(t, "devnet\n", b.String())
+}
+
+func TestExpectChainConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+		return path
+	}
+	config := db.ChainCfg{Commitment: null.StringFrom("confirmed"), SkipPreflight: null.BoolFrom(true)}
+
+	t.Run("match", func(t *testing.T) {
+		file := writeFile("match.json", `{"SkipPreflight": true, "Commitment": "confirmed"}`)
+		var b bytes.Buffer
+		require.NoError(t, cmd.ExpectChainConfig(&b, "devnet", config, file, false))
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		file := writeFile("mismatch.json", `{"Commitment": "finalized"}`)
+		var b bytes.Buffer
+		err := cmd.ExpectChainConfig(&b, "devnet", config, file, false)
+		assert.EqualError(t, err, "config of chain devnet does not match "+file)
+		assert.Equal(t, "Config of chain devnet does not match "+file+":\n"+
+			"  Commitment: expected \"finalized\", got \"confirmed\"\n"+
+			"  SkipPreflight: expected null, got true\n", b.String())
+	})
+
+	t.Run("subset", func(t *testing.T) {
+		file := writeFile("subset.json", `{"Commitment": "confirmed"}`)
+		require.NoError(t, cmd.ExpectChainConfig(ioutil.Discard, "devnet", config, file, true))
+
+		file = writeFile("subset-mismatch.json", `{"Commitment": "finalized"}`)
+		var b bytes.Buffer
+		require.Error(t, cmd.ExpectChainConfig(&b, "devnet", config, file, true))
+		assert.NotContains(t, b.String(), "SkipPreflight")
+	})
+}
+
+func TestClient_WarnChainConfigCompatibility(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"version":"1.2.0","commitSHA":"abc"}`),
+	}}}
+
+	var b bytes.Buffer
+	client.WarnChainConfigCompatibility(&b, "1.2.0", "abc")
+	assert.Empty(t, b.String())
+
+	client.WarnChainConfigCompatibility(&b, "1.1.0", "def")
+	assert.Equal(t, "WARNING: node build (1.2.0@abc) differs from CLI build (1.1.0@def), so their chain config fields may not match. Upgrade the CLI to the node's version if the node rejects the config.\n", b.String())
+
+	b.Reset()
+	client = &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusNotFound, "")}}}
+	client.WarnChainConfigCompatibility(&b, "1.1.0", "def")
+	assert.Contains(t, b.String(), "node build (unknown@unknown)")
+}
+
+func TestClient_RequireNodeVersion(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"version":"1.2.0","commitSHA":"abc"}`),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	require.NoError(t, client.RequireNodeVersion("1.2.0"))
+	require.NoError(t, client.RequireNodeVersion("1.1.3"))
+	assert.EqualError(t, client.RequireNodeVersion("1.3.0"), "this operation requires node version >= 1.3.0 (node is 1.2.0)")
+	var b bytes.Buffer
+	client.WarnChainConfigCompatibility(&b, "1.2.0", "abc")
+	assert.Empty(t, b.String())
+	assert.Len(t, stub.requests, 1, "build info should be requested once")
+
+	client = &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"version":"unset","commitSHA":"unset"}`),
+	}}}
+	assert.NoError(t, client.RequireNodeVersion("1.3.0"))
+
+	client = &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusNotFound, "")}}}
+	err := client.RequireNodeVersion("1.3.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get node version")
+}
+
+func TestClient_ChainAction_MinNodeVersion(t *testing.T) {
+	t.Parallel()
+
+	history := `{"data":[{"type":"solana_chain_config_versions","id":"1","attributes":{"config":{}}}]}`
+	run := func(version string) (*stubHTTPClient, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"version":"`+version+`","commitSHA":"abc"}`),
+			stubResponse(http.StatusOK, history),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		c := cli.NewContext(nil, set, nil)
+		c.Command.HelpName = "chainlink chains solana history"
+		return stub, client.ChainAction(client.HistorySolanaChain)(c)
+	}
+
+	stub, err := run("1.2.1")
+	assert.EqualError(t, err, "this operation requires node version >= 1.3.0 (node is 1.2.1)")
+	require.Len(t, stub.requests, 1, "the command is refused before any request of its own")
+	assert.Equal(t, "/v2/build_info", stub.requests[0].path)
+
+	stub, err = run("1.3.0")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, "/v2/chains/solana/devnet/history", stub.requests[1].path)
+}
+
+func TestClient_CountChains(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[],"meta":{"count":2}}`),
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusOK, `{"data":[],"meta":{"count":1}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("test", 0)
+	set.Bool("all-families", true, "")
+	require.NoError(t, client.CountChains(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 3)
+	assert.Equal(t, "/v2/chains/evm?size=1", stub.requests[0].path)
+	assert.Equal(t, "/v2/chains/solana?size=1", stub.requests[1].path)
+	assert.Equal(t, "/v2/chains/terra?size=1", stub.requests[2].path)
+
+	require.Len(t, r.Renders, 1)
+	counts := r.Renders[0].(*cmd.ChainCountsPresenter)
+	assert.Equal(t, 3, counts.Total)
+	require.Len(t, counts.Families, 3)
+	assert.Equal(t, 2, *counts.Families[0].Count)
+	assert.Nil(t, counts.Families[1].Count)
+	assert.Equal(t, 1, *counts.Families[2].Count)
+
+	set = flag.NewFlagSet("test", 0)
+	assert.EqualError(t, client.CountChains(cli.NewContext(nil, set, nil)), "must pass --family or --all-families")
+
+	// Unavailable families are n/a with a warning
+	for _, status := range []int{http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		var b bytes.Buffer
+		stub = &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[],"meta":{"count":2}}`),
+			stubResponse(status, `{"errors":[{"detail":"boom"}]}`),
+			stubResponse(http.StatusOK, `{"data":[],"meta":{"count":1}}`),
+		}}
+		client = &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set = flag.NewFlagSet("test", 0)
+		set.Bool("all-families", true, "")
+		set.String("output", "json", "")
+		require.NoError(t, client.CountChains(cli.NewContext(nil, set, nil)))
+		var out struct {
+			Total    int
+			Families []struct{ Count *int }
+			Warnings []string
+		}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &out), b.String())
+		assert.Equal(t, 3, out.Total)
+		require.Len(t, out.Families, 3)
+		assert.Nil(t, out.Families[1].Count)
+		require.Len(t, out.Warnings, 1)
+		assert.Equal(t, "failed to count solana chains: Error; boom", out.Warnings[0])
+	}
+
+	// Rejected credentials fail the count
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		stub = &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[],"meta":{"count":2}}`),
+			stubResponse(status, `{"errors":[{"detail":"boom"}]}`),
+		}}
+		r = &cltest.RendererMock{}
+		client = &cmd.Client{HTTP: stub, Renderer: r}
+		set = flag.NewFlagSet("test", 0)
+		set.Bool("all-families", true, "")
+		err := client.CountChains(cli.NewContext(nil, set, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to count solana chains")
+		assert.Empty(t, r.Renders)
+	}
+}
+
+func TestClient_ListChains_GroupByFamily(t *testing.T) {
+	t.Parallel()
+
+	list := func(t *testing.T, output string) string {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[]}`),
+			stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}]}`),
+			stubResponse(http.StatusOK, `{"data":[{"type":"terra_chain","id":"columbus-5","attributes":{"enabled":false,"config":{}}}]}`),
+		}}
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("test", 0)
+		set.Bool("group-by-family", true, "")
+		set.String("output", output, "")
+		require.NoError(t, set.Parse(nil))
+		require.NoError(t, client.ListChains(cli.NewContext(nil, set, nil)))
+		require.Len(t, stub.requests, 3)
+		return b.String()
+	}
+
+	t.Run("table", func(t *testing.T) {
+		out := list(t, "")
+		assert.NotContains(t, out, "EVM")
+		require.Contains(t, out, "Solana (1)\n")
+		require.Contains(t, out, "Terra (1)\n")
+		assert.Less(t, strings.Index(out, "Solana (1)"), strings.Index(out, "Terra (1)"))
+		assert.Contains(t, out, "devnet")
+		assert.Contains(t, out, "columbus-5")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var grouped map[string][]map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(list(t, "json")), &grouped))
+		require.Len(t, grouped, 2)
+		require.Len(t, grouped["solana"], 1)
+		assert.Equal(t, "devnet", grouped["solana"][0]["id"])
+		require.Len(t, grouped["terra"], 1)
+	})
+}
+
+// failingChainPresenter is a chain presenter whose row cannot be rendered.
+type failingChainPresenter struct{ id string }
+
+func (p failingChainPresenter) GetID() string { return p.id }
+
+func (p failingChainPresenter) Row(cmd.RendererTable) ([]string, error) {
+	return nil, errors.New("bad config")
+}
+
+func TestFamilyChainsPresenter_PartialOutput(t *testing.T) {
+	t.Parallel()
+
+	chain := &cmd.SolanaChainPresenter{SolanaChainResource: presenters.SolanaChainResource{JAID: presenters.NewJAID("devnet")}}
+	chains := cmd.FamilyChainsPresenter{
+		{ID: "devnet", Family: "solana", Chain: chain},
+		{ID: "broken", Family: "terra", Chain: failingChainPresenter{id: "broken"}},
+	}
+
+	var b bytes.Buffer
+	err := chains.RenderTable(cmd.RendererTable{Writer: &b})
+	require.EqualError(t, err, "1 row(s) failed to render")
+	out := b.String()
+	assert.Contains(t, out, "devnet")
+	assert.Contains(t, out, "1 row(s) failed to render: broken: bad config\n")
+}
+
+func TestClient_CreateChain_DetectFamily(t *testing.T) {
+	t.Parallel()
+
+	create := func(t *testing.T, stub *stubHTTPClient, config string) error {
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: ioutil.Discard}}
+		set := flag.NewFlagSet("test", 0)
+		set.String("type", "", "")
+		set.String("id", "devnet", "")
+		require.NoError(t, set.Parse([]string{config}))
+		return client.CreateChain(cli.NewContext(nil, set, nil))
+	}
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`),
+	}}
+	require.NoError(t, create(t, stub, `{"Commitment":"confirmed","ConfirmPollPeriod":"1s"}`))
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/v2/chains/solana", stub.requests[0].path)
+
+	stub = &stubHTTPClient{}
+	assert.EqualError(t, create(t, stub, `{"ConfirmPollPeriod":"1s"}`), "the config could be a Solana or Terra config: pass --type to choose")
+	assert.EqualError(t, create(t, stub, `{"Foo":1}`), "cannot detect the chain family of the config: pass --type (options: evm, solana, terra)")
+	assert.Empty(t, stub.requests)
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	var b bytes.Buffer
+	cmd.WarnDeprecated(&b, "WARNING: deprecated", 3)
+	assert.Equal(t, "WARNING: deprecated\n", b.String())
+
+	t.Setenv(cmd.NoDeprecationWarningsEnv, "true")
+	b.Reset()
+	cmd.WarnDeprecated(&b, "WARNING: deprecated", 1)
+	assert.Empty(t, b.String())
+}
+
+func TestStripJSONC(t *testing.T) {
+	t.Parallel()
+
+	in := `{
+	// the RPC node
+	"URL": "http://node//path", /* inline */ "Name": "a /* not a comment */",
+	"Escaped": "quote \" // still a string",
+	"List": [1, 2, /* last */],
+}`
+	out, err := cmd.StripJSONC([]byte(in))
+	require.NoError(t, err)
+	assert.Len(t, out, len(in))
+	assert.JSONEq(t, `{"URL": "http://node//path", "Name": "a /* not a comment */", "Escaped": "quote \" // still a string", "List": [1, 2]}`, string(out))
+
+	_, err = cmd.StripJSONC([]byte(`{"a": 1 /* open`))
+	assert.EqualError(t, err, "unterminated block comment at offset 8")
+}
+
+func TestConfigHash(t *testing.T) {
+	t.Parallel()
+
+	a, err := cmd.ConfigHash(map[string]interface{}{"A": 1, "B": map[string]interface{}{"C": "x", "D": true}})
+	require.NoError(t, err)
+	b, err := cmd.ConfigHash(json.RawMessage(`{"B": {"D": true, "C": "x"}, "A": 1}`))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 12)
+
+	c, err := cmd.ConfigHash(map[string]interface{}{"A": 2})
+	require.NoError(t, err)
+	assert.NotEqual(t, a, c)
+}
+
+func TestLintChainConfig(t *testing.T) {
+	t.Parallel()
+
+	issues := cmd.LintChainConfig([]byte(`{
+		"Commitment": "confirmed",
+		"txTimeout": "1m",
+		"BalancePollPeriod": "soon",
+		"SkipPreflight": "yes",
+		"RPC": {}
+	}`), db.ChainCfg{}, map[string]string{"Commitment": "Foo"})
+	assert.Equal(t, []string{
+		`error: BalancePollPeriod: invalid value "soon": time: invalid duration "soon"`,
+		"warning: Commitment: deprecated, use 'Foo'",
+		"error: RPC: unknown field",
+		`error: SkipPreflight: invalid value "yes": null: couldn't unmarshal JSON: json: cannot unmarshal string into Go value of type bool`,
+		"warning: txTimeout: only matches field TxTimeout case-insensitively",
+	}, issues)
+
+	assert.Empty(t, cmd.LintChainConfig([]byte(`{"TxTimeout": "1m", "SkipPreflight": null}`), db.ChainCfg{}, nil))
+	assert.Equal(t, []string{"error: config must be a JSON object"},
+		cmd.LintChainConfig([]byte(`[]`), db.ChainCfg{}, nil))
+}
+
+func TestGuessChainFamily(t *testing.T) {
+	t.Parallel()
+
+	solana := []byte(`{"Commitment": "confirmed", "SkipPreflight": true}`)
+	assert.Equal(t, "solana", cmd.GuessChainFamily("evm", solana))
+	assert.Equal(t, "solana", cmd.GuessChainFamily("terra", solana))
+	assert.Empty(t, cmd.GuessChainFamily("solana", solana))
+
+	evm := []byte(`{"evmFinalityDepth": 50, "GasEstimatorMode": "FixedPrice"}`)
+	assert.Equal(t, "evm", cmd.GuessChainFamily("solana", evm))
+
+	// A single key of the command's family is enough, even among others
+	assert.Empty(t, cmd.GuessChainFamily("terra", []byte(`{"Commitment": "confirmed", "FCDURL": "https://fcd"}`)))
+	// Unknown keys are left to the node to reject
+	assert.Empty(t, cmd.GuessChainFamily("evm", []byte(`{"Foo": 1}`)))
+	assert.Empty(t, cmd.GuessChainFamily("evm", []byte(`[]`)))
+}
+
+func TestWriteConfigEnv(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteConfigEnv(&b, map[string]interface{}{
+		"RPC":        map[string]interface{}{"URL": "http://it's.local"},
+		"Nodes":      []interface{}{map[string]interface{}{"Name": "a b"}},
+		"TxTimeout":  "1m0s",
+		"Retries":    3,
+		"Commitment": nil,
+		"APIKey":     "hunter2",
+	}, false))
+	assert.Equal(t, `CONFIG_APIKEY='***'
+CONFIG_COMMITMENT=''
+CONFIG_NODES_0_NAME='a b'
+CONFIG_RETRIES='3'
+CONFIG_RPC_URL='http://it'\''s.local'
+CONFIG_TXTIMEOUT='1m0s'
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.WriteConfigEnv(&b, map[string]interface{}{"APIKey": "hunter2"}, true))
+	assert.Equal(t, "CONFIG_APIKEY='hunter2'\n", b.String())
+
+	err := cmd.WriteConfigEnv(&b, map[string]interface{}{"a_b": 1, "a": map[string]interface{}{"b": 2}}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "are both named CONFIG_A_B")
+}
+
+func TestCommandWarnings(t *testing.T) {
+	t.Parallel()
+
+	var out, stderr bytes.Buffer
+	warn, r, flush := cmd.CollectWarnings(cmd.RendererJSON{Writer: &out}, &stderr)
+	fmt.Fprintln(warn, "WARNING: config key 'Foo' is deprecated")
+	fmt.Fprintln(warn, "WARNING: node build differs")
+	require.NoError(t, r.Render(map[string]interface{}{"id": "devnet"}))
+	require.NoError(t, r.Render(map[string]interface{}{"id": "testnet"}))
+	d := json.NewDecoder(&out)
+	var first, second map[string]interface{}
+	require.NoError(t, d.Decode(&first))
+	require.NoError(t, d.Decode(&second))
+	assert.Equal(t, map[string]interface{}{
+		"id":       "devnet",
+		"warnings": []interface{}{"config key 'Foo' is deprecated", "node build differs"},
+	}, first)
+	// Warnings are attached to a single output
+	assert.Equal(t, map[string]interface{}{"id": "testnet"}, second)
+
+	out.Reset()
+	fmt.Fprintln(warn, "WARNING: lists are nested")
+	require.NoError(t, r.Render([]string{"devnet"}))
+	assert.JSONEq(t, `{"data":["devnet"],"warnings":["lists are nested"]}`, out.String())
+
+	fmt.Fprintln(warn, "WARNING: not rendered")
+	flush()
+	assert.Equal(t, "WARNING: not rendered\n", stderr.String())
+}
+
+func TestClient_PingNode(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"version":"1.2.0","commitSHA":"abc"}`),
+		stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"busy"}]}`),
+		stubResponse(http.StatusOK, `{"version":"1.2.0","commitSHA":"abc"}`),
+	}}
+	var b bytes.Buffer
+	require.NoError(t, (&cmd.Client{HTTP: stub}).Ping(&b, 3))
+	assert.Len(t, stub.requests, 3)
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^Reply 1 from node 1\.2\.0@abc: time=\S+$`, lines[0])
+	assert.Regexp(t, `^Request 2 failed: `, lines[1])
+	assert.Regexp(t, `^3 sent, 2 received, round-trip min/avg/max = \S+/\S+/\S+$`, lines[3])
+
+	b.Reset()
+	unreachable := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusBadGateway, `bad gateway`)}}
+	err := (&cmd.Client{HTTP: unreachable}).Ping(&b, 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "node is unreachable")
+	assert.Contains(t, b.String(), "2 sent, 0 received\n")
+}
+
+func TestReadChainIDsJSON(t *testing.T) {
+	t.Parallel()
+
+	ids, err := cmd.ReadChainIDsJSON(strings.NewReader(`["devnet", "testnet"]` + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"devnet", "testnet"}, ids)
+
+	for input, msg := range map[string]string{
+		`{"ids":["devnet"]}`: "--ids-stdin: expected a JSON array of chain IDs, got JSON object",
+		`"devnet"`:           "--ids-stdin: expected a JSON array of chain IDs, got JSON string",
+		`[]`:                 "--ids-stdin: no chain IDs given",
+		`["devnet", 5]`:      "--ids-stdin: element 1 is JSON number, not a chain ID string",
+		`[""]`:               "--ids-stdin: element 0 is an empty chain ID",
+	} {
+		_, err = cmd.ReadChainIDsJSON(strings.NewReader(input))
+		assert.EqualError(t, err, msg, input)
+	}
+	_, err = cmd.ReadChainIDsJSON(strings.NewReader(`devnet testnet`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--ids-stdin: invalid JSON")
+}
+
+func TestCheckDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{`{}`, `[]`, `"x"`, `{"a":{"b":1},"b":{"b":2},"c":[{"d":1},{"d":2}]}`} {
+		assert.NoError(t, cmd.CheckDuplicateKeys([]byte(raw)), raw)
+	}
+	for raw, msg := range map[string]string{
+		`{"TxTimeout":"1s","TxTimeout":"2s"}`:           "duplicate key 'TxTimeout' at the top level",
+		`{"Nodes":{"RPC":"a","RPC":"b"}}`:               "duplicate key 'RPC' at .Nodes",
+		`{"Nodes":[{"URL":"a"},{"URL":"b","URL":"c"}]}`: "duplicate key 'URL' at .Nodes[1]",
+	} {
+		assert.EqualError(t, cmd.CheckDuplicateKeys([]byte(raw)), msg, raw)
+	}
+}
+
+func TestClient_CreateSolanaChain_StrictJSON(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"TxTimeout":"1s","TxTimeout":"2s"}`), 0600))
+
+	stub := &stubHTTPClient{}
+	client := &cmd.Client{Renderer: &cltest.RendererMock{}, HTTP: stub}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.Var(&cli.StringSlice{path}, "config-file", "")
+	set.Bool("strict-json", true, "")
+	require.NoError(t, set.Parse(nil))
+
+	err := client.CreateSolanaChain(cli.NewContext(nil, set, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("config file '%s': duplicate key 'TxTimeout' at the top level", path))
+	assert.Empty(t, stub.requests)
+}
+
+func TestWriteConfigTree(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteConfigTree(&b, map[string]interface{}{
+		"TxTimeout": "1m0s",
+		"Nodes": []interface{}{
+			map[string]interface{}{"Name": "primary", "URL": "http://a", "Weight": 2},
+			map[string]interface{}{},
+		},
+		"Tags":          []interface{}{},
+		"SkipPreflight": true,
+		"Commitment":    nil,
+	}))
+	assert.Equal(t, `Nodes
+  [0]
+    Name: primary
+    URL: http://a
+    Weight: 2
+  [1]
+    (empty)
+SkipPreflight: true
+Tags
+  (empty)
+TxTimeout: 1m0s
+`, b.String())
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	t.Parallel()
+
+	t.Run("results by index", func(t *testing.T) {
+		var running, peak int32
+		errs := cmd.ForEachConcurrently(3, 10, func(i int) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			if i%2 == 1 {
+				return fmt.Errorf("item %d", i)
+			}
+			return nil
+		})
+		require.Len(t, errs, 10)
+		for i, err := range errs {
+			if i%2 == 1 {
+				assert.EqualError(t, err, fmt.Sprintf("item %d", i))
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+		assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(3))
+	})
+
+	t.Run("stops on cancel", func(t *testing.T) {
+		var calls int32
+		errs := cmd.ForEachConcurrently(1, 5, func(i int) error {
+			atomic.AddInt32(&calls, 1)
+			if i == 1 {
+				return context.Canceled
+			}
+			return nil
+		})
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+		assert.NoError(t, errs[0])
+		for _, err := range errs[1:] {
+			assert.ErrorIs(t, err, context.Canceled)
+		}
+	})
+}
+
+func TestMergeChainConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	base, env, override := filepath.Join(dir, "base.json"), filepath.Join(dir, "env.json"), filepath.Join(dir, "override.json")
+	require.NoError(t, ioutil.WriteFile(base, []byte(`{"a": {"b": 1, "c": 2}, "d": [1, 2]}`), 0600))
+	require.NoError(t, ioutil.WriteFile(env, []byte(`{"a": {"c": 3}, "d": [3]}`), 0600))
+	require.NoError(t, ioutil.WriteFile(override, []byte(`{"a": 4}`), 0600))
+
+	var verbose bytes.Buffer
+	config, err := cmd.MergeChainConfigFiles(&verbose, []string{base, env}, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": {"b": 1, "c": 3}, "d": [3]}`, string(config))
+	assert.Equal(t, "config file '"+base+"': 1 key(s) in the final config\n"+
+		"config file '"+env+"': 2 key(s) in the final config\n", verbose.String())
+
+	verbose.Reset()
+	config, err = cmd.MergeChainConfigFiles(&verbose, []string{base, env, override}, false)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": 4, "d": [3]}`, string(config))
+	assert.Equal(t, "config file '"+base+"': 0 key(s) in the final config\n"+
+		"config file '"+env+"': 1 key(s) in the final config\n"+
+		"config file '"+override+"': 1 key(s) in the final config\n", verbose.String())
+}
+
+func TestClient_UsageErrors(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{HTTP: &stubHTTPClient{}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "", "")
+	c := cli.NewContext(nil, set, nil)
+	c.Command = cli.Command{
+		Name:      "delete",
+		HelpName:  "chainlink solana chains delete",
+		Usage:     "Delete one or more Solana chains",
+		ArgsUsage: "ID...",
+		Flags:     []cli.Flag{cli.BoolFlag{Name: "quiet, q", Usage: "suppress progress output"}},
+	}
+
+	err := client.RemoveSolanaChain(c)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "must pass the id of the chain to be removed\n\nNAME:\n"), err.Error())
+	assert.Contains(t, err.Error(), "chainlink solana chains delete [command options] ID...")
+	assert.Contains(t, err.Error(), "--quiet, -q  suppress progress output")
+
+	// Errors outside of a command are unchanged.
+	err = client.RemoveSolanaChain(cli.NewContext(nil, set, nil))
+	assert.EqualError(t, err, "must pass the id of the chain to be removed")
+}
+
+func TestExecuteTemplateFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, tmpl string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(tmpl), 0600))
+		return path
+	}
+	chains := []cmd.SolanaChainPresenter{
+		{SolanaChainResource: presenters.SolanaChainResource{JAID: presenters.NewJAID("devnet"), Enabled: true, Config: db.ChainCfg{Commitment: null.StringFrom("confirmed")}}},
+		{SolanaChainResource: presenters.SolanaChainResource{JAID: presenters.NewJAID("testnet")}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.ExecuteTemplateFile(&b, write("each.tmpl", "{{.ID}} enabled={{.Enabled}}\n"), chains, false))
+	assert.Equal(t, "devnet enabled=true\ntestnet enabled=false\n", b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.ExecuteTemplateFile(&b, write("once.tmpl", "{{len .}} chains:{{range .}} {{.ID}}{{end}}\n"), chains, true))
+	assert.Equal(t, "2 chains: devnet testnet\n", b.String())
+
+	invalid := write("invalid.tmpl", "line 1\n{{.ID}\n")
+	err := cmd.ExecuteTemplateFile(&b, invalid, chains, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse template: template: "+invalid+":2:")
+
+	err = cmd.ExecuteTemplateFile(&b, write("missing.tmpl", "{{.Missing}}"), chains, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to execute template for item 0")
+}
+
+func TestClient_WriteRaw(t *testing.T) {
+	t.Parallel()
+
+	body := `{"data":[],"meta":{"count":0},"unknown":true}`
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, body),
+		stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	var b bytes.Buffer
+	require.NoError(t, client.WriteRaw(&b, "/v2/chains/solana", 2))
+	assert.Equal(t, body, b.String())
+	assert.Equal(t, "/v2/chains/solana?page=2", stub.requests[0].path)
+
+	b.Reset()
+	assert.EqualError(t, client.WriteRaw(&b, "/v2/chains/solana/devnet", 0), "request failed with status 500")
+	assert.Equal(t, `{"errors":[{"detail":"boom"}]}`, b.String())
+	assert.Equal(t, "/v2/chains/solana/devnet", stub.requests[1].path)
+}
+
+func TestClient_FlagConflicts(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{HTTP: &stubHTTPClient{}}
+	for _, tt := range []struct {
+		name  string
+		run   func(*cli.Context) error
+		flags map[string]string
+		err   string
+	}{
+		{name: "raw and select", run: client.IndexSolanaChains, flags: map[string]string{"raw": "true", "select": "ID"}, err: "--raw cannot be combined with --select"},
+		{name: "raw and sort", run: client.IndexSolanaChains, flags: map[string]string{"raw": "true", "sort": "id"}, err: "--raw cannot be combined with --sort"},
+		{name: "select and wide", run: client.IndexEVMChains, flags: map[string]string{"select": "ID", "wide": "true"}, err: "--select cannot be combined with --wide"},
+		{name: "field and template", run: client.ShowSolanaChain, flags: map[string]string{"id": "x", "field": "TxTimeout", "template-file": "t.tmpl"}, err: "--field cannot be combined with --template-file"},
+		{name: "no-render and json", run: client.ConfigureSolanaChain, flags: map[string]string{"no-render": "true", "output": "json"}, err: "--no-render cannot be combined with --output=json"},
+		{name: "false bool", run: client.ConfigureSolanaChain, flags: map[string]string{"no-render": "false", "output": "json"}, err: "missing chain ID"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			set := flag.NewFlagSet("cli", 0)
+			for _, name := range []string{"select", "sort", "template-file", "id", "field", "expect-config", "output"} {
+				set.String(name, "", "")
+			}
+			for _, name := range []string{"raw", "wide", "only-unhealthy", "no-render", "changed-only"} {
+				set.Bool(name, false, "")
+			}
+			for k, v := range tt.flags {
+				require.NoError(t, set.Set(k, v))
+			}
+			err := tt.run(cli.NewContext(nil, set, nil))
+			require.Error(t, err)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.err), err.Error())
+		})
+	}
+}
+
+func TestClient_IndexChains_WithLinks(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}],"links":{"next":"/v2/chains/solana?page=3&size=1"},"meta":{"count":5}}`),
+	}}
+	var b bytes.Buffer
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererJSON{Writer: &b}}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.Bool("with-links", false, "")
+	set.Int("page", 2, "")
+	require.NoError(t, set.Set("with-links", "true"))
+	require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, "/v2/chains/solana?page=2&size=100", stub.requests[0].path)
+
+	var page struct {
+		Data  []map[string]interface{} `json:"data"`
+		Links map[string]string        `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &page))
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, map[string]string{"next": "/v2/chains/solana?page=3&size=1"}, page.Links)
+
+	client.Renderer = &cltest.RendererMock{}
+	assert.EqualError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)), "--with-links requires JSON output (--json)")
+}
+
+func TestWriteConfigHCL(t *testing.T) {
+	t.Parallel()
+
+	config := json.RawMessage(`{"Commitment": "confirmed", "TxTimeout": "1m0s", "Skip": true, "Empty": null, "Tags": ["a", "${b}"],
+		"RPC": {"URL": "http://x", "Retries": 3}, "Nodes": [{"Name": "n1"}, {"Name": "n2"}]}`)
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteConfigHCL(&b, "solana_chain", "devnet", true, config))
+	assert.Equal(t, `solana_chain "devnet" {
+  enabled = true
+  config {
+    Commitment = "confirmed"
+    Skip       = true
+    Tags       = ["a", "$${b}"]
+    TxTimeout  = "1m0s"
+    Nodes {
+      Name = "n1"
+    }
+    Nodes {
+      Name = "n2"
+    }
+    RPC {
+      Retries = 3
+      URL     = "http://x"
+    }
+  }
+}
+`, b.String())
+
+	err := cmd.WriteConfigHCL(&b, "solana_chain", "devnet", true, json.RawMessage(`{"has space": 1}`))
+	assert.EqualError(t, err, `config key "has space" is not a valid HCL identifier`)
+}
+
+// errWriter fails every write.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestWriteConfigYAML(t *testing.T) {
+	t.Parallel()
+
+	config := json.RawMessage(`{"Commitment": "confirmed", "TxTimeout": "1m0s", "Skip": true, "Empty": null, "Tags": ["a", "b"],
+		"RPC": {"URL": "http://x", "Retries": 3, "Ratio": 0.5}, "Nested": {}, "None": [], "has space": 1}`)
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteConfigYAML(&b, config))
+	assert.Equal(t, `Commitment: confirmed
+Empty: null
+Nested: {}
+None: []
+RPC:
+  Ratio: 0.5
+  Retries: 3
+  URL: http://x
+Skip: true
+Tags:
+  - a
+  - b
+TxTimeout: 1m0s
+has space: 1
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.WriteConfigYAML(&b, json.RawMessage(`{"true": "null", "null": "yes", "yes": "on", "on": "1", "Key": "a: b"}`)))
+	assert.Equal(t, `Key: 'a: b'
+"null": "yes"
+"on": "1"
+"true": "null"
+"yes": "on"
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.WriteConfigYAML(&b, json.RawMessage(`{}`)))
+	assert.Equal(t, "{}\n", b.String())
+
+	assert.Error(t, cmd.WriteConfigYAML(errWriter{}, config))
+}
+
+func TestUnsetConfigPaths(t *testing.T) {
+	t.Parallel()
+
+	config := json.RawMessage(`{"RPC": {"URL": "x", "Timeout": "1s"}, "Labels": ["a"], "Name": "devnet", "Empty": null}`)
+	unset, err := cmd.UnsetConfigPaths(config, []string{"RPC.Timeout", "Labels"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"RPC": map[string]interface{}{"URL": "x"}, "Name": "devnet", "Empty": nil}, unset)
+
+	_, err = cmd.UnsetConfigPaths(config, []string{"RPC.Missing"}, false)
+	assert.EqualError(t, err, "config field RPC.Missing is not set")
+	_, err = cmd.UnsetConfigPaths(config, []string{"Empty"}, false)
+	assert.EqualError(t, err, "config field Empty is not set")
+	_, err = cmd.UnsetConfigPaths(config, []string{"Name.First"}, false)
+	assert.EqualError(t, err, "config field Name.First cannot be unset: Name is not an object")
+
+	unset, err = cmd.UnsetConfigPaths(config, []string{"Missing.Nested", "Name"}, true)
+	require.NoError(t, err)
+	assert.NotContains(t, unset, "Name")
+}
+
+func TestDeprecatedKeyWarnings(t *testing.T) {
+	t.Parallel()
+
+	deprecated := map[string]string{"Foo": "Bar", "RPC": "", "Nested.Old": "Nested.New", "Absent": "Present"}
+	warnings, err := cmd.DeprecatedKeyWarnings(deprecated, json.RawMessage(`{"Foo": 1, "RPC": {"URL": "x"}, "Nested": {"Old": true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"config key 'Foo' is deprecated; use 'Bar'",
+		"config key 'Nested.Old' is deprecated; use 'Nested.New'",
+		"config key 'RPC' is deprecated",
+	}, warnings)
+
+	warnings, err = cmd.DeprecatedKeyWarnings(nil, json.RawMessage(`{"Foo": 1}`))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestWarnSecretConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	cmd.WarnSecretConfigKeys(&b, json.RawMessage(`{"Token": "abc", "Auth": {"Password": "p", "User": "u"}, "APIKey": null, "Commitment": "confirmed"}`))
+	assert.Equal(t, "WARNING: field 'Auth.Password' looks like a secret and is sent in plaintext; consider using a secret reference (silence with --no-secret-warnings)\n"+
+		"WARNING: field 'Token' looks like a secret and is sent in plaintext; consider using a secret reference (silence with --no-secret-warnings)\n", b.String())
+
+	b.Reset()
+	cmd.WarnSecretConfigKeys(&b, json.RawMessage(`{"Commitment": "confirmed"}`))
+	assert.Empty(t, b.String())
+}
+
+func TestWriteFieldsJSON(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.WriteFieldsJSON(&b, json.RawMessage(`{"RPC": {"URL": "http://x", "Timeout": "5s"}, "Peers": ["a", "b"], "Limit": 10, "Empty": {}}`)))
+	assert.Equal(t, `{"Empty":{},"Limit":10,"Peers.0":"a","Peers.1":"b","RPC.Timeout":"5s","RPC.URL":"http://x"}`+"\n", b.String())
+}
+
+func TestMatchChainIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{"testnet-a", "devnet", "testnet-b", "testnet"}
+	matched, err := cmd.MatchChainIDs("testnet-*", ids)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testnet-a", "testnet-b"}, matched)
+
+	matched, err = cmd.MatchChainIDs("*net", ids)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"devnet", "testnet"}, matched)
+
+	_, err = cmd.MatchChainIDs("mainnet-*", ids)
+	assert.EqualError(t, err, "no chains match 'mainnet-*'")
+
+	_, err = cmd.MatchChainIDs("testnet-[", ids)
+	assert.EqualError(t, err, "invalid --match pattern 'testnet-[': syntax error in pattern")
+}
+
+func TestWriteProjected(t *testing.T) {
+	t.Parallel()
+
+	chain := cmd.SolanaChainPresenter{}
+	chain.ID = "devnet"
+	chain.Enabled = true
+	chain.Config.Commitment = null.StringFrom("confirmed")
+	chains := []cmd.SolanaChainPresenter{chain}
+
+	for _, tt := range []struct {
+		name, expr, exp string
+	}{
+		{"path", ".config.Commitment", `"confirmed"` + "\n"},
+		{"object", "{id: .id, commitment: .config.Commitment, enabled: .enabled}", `{"id":"devnet","commitment":"confirmed","enabled":true}` + "\n"},
+		{"missing", "{rpc: .config.RPC.URL}", `{"rpc":null}` + "\n"},
+		{"empty object", "{}", "{}\n"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			require.NoError(t, cmd.WriteProjected(&b, chains, tt.expr))
+			assert.Equal(t, tt.exp, b.String())
+		})
+	}
+
+	for _, tt := range []struct {
+		name, expr, err string
+	}{
+		{"not an object", "{c: .config.Commitment.Level}", "--jq failed for chain devnet: cannot index string with 'Level' at .config.Commitment.Level"},
+		{"no dot", "{id: id}", "invalid --jq path 'id': must start with '.'"},
+		{"bad key", "{.id}", "invalid --jq expression '{.id}': expected key: .path, got '.id'"},
+		{"unclosed", "{id: .id", "invalid --jq expression '{id: .id': missing closing '}'"},
+		{"pipe", ".config | .Commitment", "invalid --jq path '.config | .Commitment': only .field segments are supported"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, cmd.WriteProjected(ioutil.Discard, chains, tt.expr), tt.err)
+		})
+	}
+}
+
+func TestClient_IndexChains_PageSize(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[]}`),
+		stubResponse(http.StatusOK, `{"data":[]}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+
+	require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, flag.NewFlagSet("test", 0), nil)))
+
+	set := flag.NewFlagSet("test", 0)
+	set.Int("page-size", 0, "")
+	require.NoError(t, set.Parse([]string{"--page-size", "10"}))
+	require.NoError(t, client.IndexEVMChains(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, "/v2/chains/solana?size=100", stub.requests[0].path)
+	assert.Equal(t, "/v2/chains/evm?size=10", stub.requests[1].path)
+
+	set = flag.NewFlagSet("test", 0)
+	set.Int("page-size", 0, "")
+	require.NoError(t, set.Parse([]string{"--page-size", "0"}))
+	assert.EqualError(t, client.IndexTerraChains(cli.NewContext(nil, set, nil)), "invalid --page-size 0: must be at least 1")
+}
+
+func TestConfirmConfigChanges(t *testing.T) {
+	t.Parallel()
+
+	from := map[string]interface{}{"A": "1s", "B": "2s", "C": "3s", "D": "4s"}
+	to := map[string]interface{}{"A": "10s", "B": "20s", "C": "30s", "D": "40s"}
+
+	for _, tt := range []struct {
+		name    string
+		answers []string
+		exp     string
+		changed int
+	}{
+		{"field by field", []string{"y", "n", "", "yes"}, `{"A":"10s","B":"2s","C":"3s","D":"40s"}`, 2},
+		{"all", []string{"n", "a"}, `{"A":"1s","B":"20s","C":"30s","D":"40s"}`, 3},
+		{"none", []string{"n", "N", "no", "n"}, `{"A":"1s","B":"2s","C":"3s","D":"4s"}`, 0},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: tt.answers}
+			approved, changed, err := cmd.ConfirmConfigChanges(prompter, from, to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+			b, err := json.Marshal(approved)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.exp, string(b))
+			assert.Equal(t, len(tt.answers), prompter.Count)
+		})
+	}
+
+	t.Run("quit", func(t *testing.T) {
+		prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: []string{"y", "q"}}
+		_, _, err := cmd.ConfirmConfigChanges(prompter, from, to)
+		assert.EqualError(t, err, "aborted by user")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: []string{"y"}}
+		approved, _, err := cmd.ConfirmConfigChanges(prompter,
+			map[string]interface{}{"RPC": map[string]interface{}{"Timeout": "5s", "URL": "http://a"}},
+			map[string]interface{}{"RPC": map[string]interface{}{"Timeout": "10s", "URL": "http://a"}})
+		require.NoError(t, err)
+		b, err := json.Marshal(approved)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"RPC":{"Timeout":"10s","URL":"http://a"}}`, string(b))
+	})
+}
+
+func TestRoundTripIssues(t *testing.T) {
+	t.Parallel()
+
+	issues, err := cmd.RoundTripIssues(json.RawMessage(`{"SkipPreflight":true,"Commitment":"confirmed"}`), db.ChainCfg{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	issues, err = cmd.RoundTripIssues(json.RawMessage(`{"Commitment":"confirmed","TxTimeout":"1m","Unknown":1}`), db.ChainCfg{})
+	require.NoError(t, err)
+	byKey := map[string]string{}
+	for _, issue := range issues {
+		byKey[issue.Key] = issue.Issue
+	}
+	assert.Equal(t, map[string]string{
+		"TxTimeout": "value re-encoded",
+		"Unknown":   "dropped: unknown to the CLI",
+		"(order)":   "top-level keys reordered",
+	}, byKey)
+
+	_, err = cmd.RoundTripIssues(json.RawMessage(`{"TxTimeout":"soon"}`), db.ChainCfg{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/pkg/errors"
+	clipkg "github.com/urfave/cli"
+	"go.uber.org/multierr"
+
+	"github.com/smartcontractkit/chainlink-solana/pkg/solana/db"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/sessions"
+	"github.com/smartcontractkit/chainlink/core/web/presenters"
+)
+
+// mockChainsServer is an in-process node implementing the Solana chain
+// endpoints, which self-test runs the chain commands against.
+type mockChainsServer struct {
+	mu     sync.Mutex
+	chains map[string]presenters.SolanaChainResource
+}
+
+func newMockChainsServer() *mockChainsServer {
+	return &mockChainsServer{chains: map[string]presenters.SolanaChainResource{}}
+}
+
+// chain returns the chain with id, and whether it exists.
+func (s *mockChainsServer) chain(id string) (presenters.SolanaChainResource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chain, ok := s.chains[id]
+	return chain, ok
+}
+
+func (s *mockChainsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const base = "/v2/chains/solana"
+	if r.URL.Path == base {
+		switch r.Method {
+		case http.MethodGet:
+			ids := make([]string, 0, len(s.chains))
+			for id := range s.chains {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			chains := make([]presenters.SolanaChainResource, len(ids))
+			for i, id := range ids {
+				chains[i] = s.chains[id]
+			}
+			writeMockResource(w, http.StatusOK, chains)
+		case http.MethodPost:
+			var body struct {
+				ChainID string      `json:"chainID"`
+				Config  db.ChainCfg `json:"config"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeMockError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+			if _, ok := s.chains[body.ChainID]; ok {
+				writeMockError(w, http.StatusConflict, fmt.Sprintf("chain %s already exists", body.ChainID))
+				return
+			}
+			now := time.Now().UTC()
+			chain := presenters.SolanaChainResource{JAID: presenters.NewJAID(body.ChainID), Enabled: true, Config: body.Config, CreatedAt: now, UpdatedAt: now}
+			s.chains[body.ChainID] = chain
+			writeMockResource(w, http.StatusCreated, chain)
+		default:
+			writeMockError(w, http.StatusMethodNotAllowed, r.Method+" is not allowed")
+		}
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, base+"/")
+	chain, ok := s.chains[id]
+	if id == r.URL.Path || strings.Contains(id, "/") || !ok {
+		writeMockError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeMockResource(w, http.StatusOK, chain)
+	case http.MethodPatch:
+		var body struct {
+			Enabled bool        `json:"enabled"`
+			Config  db.ChainCfg `json:"config"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeMockError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		chain.Enabled, chain.Config, chain.UpdatedAt = body.Enabled, body.Config, time.Now().UTC()
+		s.chains[id] = chain
+		writeMockResource(w, http.StatusOK, chain)
+	case http.MethodDelete:
+		delete(s.chains, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMockError(w, http.StatusMethodNotAllowed, r.Method+" is not allowed")
+	}
+}
+
+func writeMockResource(w http.ResponseWriter, status int, resource interface{}) {
+	b, err := jsonapi.Marshal(resource)
+	if err != nil {
+		writeMockError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func writeMockError(w http.ResponseWriter, status int, detail string) {
+	b, _ := json.Marshal(map[string]interface{}{"errors": []map[string]string{{"detail": detail}}})
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+// selfTestConfig points the HTTP client of self-test at the mock node.
+type selfTestConfig struct {
+	url string
+}
+
+func (s selfTestConfig) ClientNodeURL() string    { return s.url }
+func (s selfTestConfig) InsecureSkipVerify() bool { return false }
+
+// selfTestStep is a chain command run by self-test, with the check of its
+// output and error.
+type selfTestStep struct {
+	name  string
+	args  []string
+	check func(out []byte, err error) error
+}
+
+// ChainsSelfTest runs the create, show, configure and delete commands of the
+// Solana chains against an in-process mock node, with the real HTTP client,
+// presenters and config merging, checking that each round-trips. It verifies
+// that a CLI build works end to end without a node, and fails on the first
+// check which does not hold.
+func (cli *Client) ChainsSelfTest(c *clipkg.Context) error {
+	node := newMockChainsServer()
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	config := selfTestConfig{url: server.URL}
+	var out bytes.Buffer
+	client := &Client{
+		Config:         cli.Config,
+		Logger:         logger.NullLogger,
+		Renderer:       RendererJSON{Writer: &out},
+		HTTP:           NewAuthenticatedHTTPClient(config, NewSessionCookieAuthenticator(config, &MemoryCookieStore{}, logger.NullLogger), sessions.SessionRequest{}),
+		ServerWarnings: ioutil.Discard,
+	}
+	app := NewApp(client)
+	app.Writer, app.ErrWriter = ioutil.Discard, ioutil.Discard
+	// Report failures instead of exiting
+	app.ExitErrHandler = func(*clipkg.Context, error) {}
+
+	const id = "self-test"
+	stored := func(want string) error {
+		chain, ok := node.chain(id)
+		if !ok {
+			return errors.Errorf("chain %s is missing from the node", id)
+		}
+		return checkSelfTestConfig("node", chain.Config, want)
+	}
+	rendered := func(out []byte, want string) error {
+		var chain SolanaChainPresenter
+		if err := json.Unmarshal(out, &chain); err != nil {
+			return errors.Wrap(err, "invalid output")
+		}
+		return checkSelfTestConfig("output", chain.Config, want)
+	}
+	steps := []selfTestStep{
+		{"create", []string{"create", "--id", id, `{"Commitment": "confirmed", "TxTimeout": "1m0s"}`}, func(out []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			want := `{"Commitment": "confirmed", "TxTimeout": "1m0s"}`
+			return multierr.Combine(stored(want), rendered(out, want))
+		}},
+		{"show", []string{"show", "--id", id}, func(out []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			return rendered(out, `{"Commitment": "confirmed", "TxTimeout": "1m0s"}`)
+		}},
+		{"configure", []string{"configure", "--id", id, "Commitment=finalized", "SkipPreflight=true"}, func(out []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			// Fields not passed are kept
+			want := `{"Commitment": "finalized", "SkipPreflight": true, "TxTimeout": "1m0s"}`
+			return multierr.Combine(stored(want), rendered(out, want))
+		}},
+		{"delete", []string{"delete", id}, func(_ []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			if _, ok := node.chain(id); ok {
+				return errors.Errorf("chain %s is still on the node", id)
+			}
+			return nil
+		}},
+		{"show deleted", []string{"show", "--id", id}, func(_ []byte, err error) error {
+			if err == nil {
+				return errors.Errorf("showing the deleted chain %s succeeded", id)
+			}
+			if code := exitCode(err); code != ExitCodeNotFound {
+				return errors.Errorf("showing the deleted chain %s exited with %d instead of %d: %v", id, code, ExitCodeNotFound, err)
+			}
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		out.Reset()
+		err := app.Run(append([]string{"chainlink", "--assume-yes", "chains", "solana"}, step.args...))
+		if err = step.check(out.Bytes(), err); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "self-test %s failed", step.name))
+		}
+		fmt.Fprintf(cli.stdout(), "ok  %s\n", step.name)
+	}
+	fmt.Fprintln(cli.stdout(), "Self-test passed")
+	return nil
+}
+
+// checkSelfTestConfig returns an error listing the fields in which the config
+// got of what differs from the JSON config want.
+func checkSelfTestConfig(what string, got db.ChainCfg, want string) error {
+	var expected db.ChainCfg
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		return err
+	}
+	fields, err := diffConfigs(expected, got)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	diffs := make([]string, len(fields))
+	for i, f := range fields {
+		diffs[i] = fmt.Sprintf("%s is %s instead of %s", f.Key, formatConfigValue(f.To), formatConfigValue(f.From))
+	}
+	return errors.Errorf("config of the %s: %s", what, strings.Join(diffs, ", "))
+}
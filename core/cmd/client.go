@@ -9,11 +9,18 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"os"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/Depado/ginprom"
@@ -25,6 +32,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/smartcontractkit/chainlink/core/auth"
 	"github.com/smartcontractkit/chainlink/core/chains/evm"
 	"github.com/smartcontractkit/chainlink/core/chains/solana"
 	"github.com/smartcontractkit/chainlink/core/chains/terra"
@@ -42,6 +50,7 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/migrate"
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/smartcontractkit/chainlink/core/web"
+	webauth "github.com/smartcontractkit/chainlink/core/web/auth"
 	"github.com/smartcontractkit/sqlx"
 )
 
@@ -75,13 +84,136 @@ type Client struct {
 	PromptingSessionRequestBuilder SessionRequestBuilder
 	ChangePasswordPrompter         ChangePasswordPrompter
 	PasswordPrompter               PasswordPrompter
+	// CheckMode is set by the global --check flag. The chain commands then
+	// refuse to send any request which mutates the node, and the create,
+	// configure and delete commands report what they would do instead.
+	CheckMode bool
+	// ConfirmPrompter answers the confirmation prompts of the chain commands.
+	// Defaults to a terminal prompter on stdin when nil.
+	ConfirmPrompter Prompter
+	// AssumeYes is set by the global --assume-yes flag, which confirms every
+	// prompt of the chain commands, as their own --force and --yes flags do.
+	AssumeYes bool
+	// ChainBodyFormat is set by the global --chain-body-format flag, and is
+	// the shape of the bodies of chain create and update requests, one of
+	// the keys of chainBodyEncoders. Defaults to plain when empty.
+	ChainBodyFormat string
+	// CompactJSON is set by the global --compact-json flag, and renders JSON
+	// output on a single line instead of indented.
+	CompactJSON bool
+	// ServerWarnings receives the warnings the node returns with responses,
+	// a line each prefixed with WARNING:. Defaults to os.Stderr when nil.
+	ServerWarnings io.Writer
+	// Stderr receives the status messages and warnings of the chain
+	// commands, which are kept out of their rendered output. Defaults to
+	// os.Stderr when nil.
+	Stderr io.Writer
+	// PrettyErrors is set by the global --pretty-errors flag, and appends
+	// hints on how to fix common mistakes to the errors of commands.
+	PrettyErrors bool
+
+	// nodeBuild caches the node's build info for the invocation.
+	nodeBuild *nodeBuildResult
+}
+
+// Exit codes of the commands, so that scripts can tell the kinds of failure
+// apart without parsing the error messages.
+const (
+	// ExitCodeError is the exit code of failures not covered by another code.
+	ExitCodeError = 1
+	// ExitCodeNotFound is the exit code of missing resources, e.g. chains.
+	ExitCodeNotFound = 2
+	// ExitCodeAuth is the exit code of requests the node rejected as
+	// unauthenticated or forbidden.
+	ExitCodeAuth = 3
+	// ExitCodeValidation is the exit code of requests the node rejected as
+	// invalid.
+	ExitCodeValidation = 4
+	// ExitCodeNetwork is the exit code of requests which failed to reach the
+	// node, or timed out.
+	ExitCodeNetwork = 5
+)
+
+// exitCodeError attaches the exit code of the command to an error.
+type exitCodeError struct {
+	error
+	code int
+}
+
+func (e exitCodeError) ExitCode() int { return e.code }
+func (e exitCodeError) Unwrap() error { return e.error }
+
+// withExitCode returns err with the exit code attached, or nil if err is nil.
+func withExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return exitCodeError{err, code}
+}
+
+// exitCode returns the exit code for err: the code attached anywhere in its
+// chain, ExitCodeNetwork for network errors and timeouts, or ExitCodeError.
+func exitCode(err error) int {
+	var coder clipkg.ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return ExitCodeNetwork
+	}
+	return ExitCodeError
 }
 
 func (cli *Client) errorOut(err error) error {
-	if err != nil {
-		return clipkg.NewExitError(err.Error(), 1)
+	if err == nil {
+		return nil
 	}
-	return nil
+	msg := err.Error()
+	var translated *clipkg.ExitError
+	if cli.PrettyErrors && !errors.As(err, &translated) {
+		for _, hint := range cli.errorHints(err) {
+			msg += "\nHint: " + hint
+		}
+	}
+	return clipkg.NewExitError(msg, exitCode(err))
+}
+
+// configFieldEnums lists the values of the config fields which take one of a
+// fixed set, for the hints of --pretty-errors.
+var configFieldEnums = map[string][]string{
+	"Commitment": {"processed", "confirmed", "finalized"},
+}
+
+// errorHints returns the remediation hints for err which --pretty-errors
+// appends to it. They are heuristics on the exit code and message of err, for
+// common mistakes such as a wrong node URL or an expired session.
+func (cli *Client) errorHints(err error) []string {
+	msg := err.Error()
+	var hints []string
+	switch exitCode(err) {
+	case ExitCodeAuth:
+		hints = append(hints, "your session may have expired: log in again with 'chainlink admin login', or pass --auth-token")
+	case ExitCodeValidation:
+		fields := make([]string, 0, len(configFieldEnums))
+		for field := range configFieldEnums {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if strings.Contains(msg, field) {
+				hints = append(hints, fmt.Sprintf("%s must be one of: %s", field, strings.Join(configFieldEnums[field], ", ")))
+			}
+		}
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(msg, "connection refused") {
+		hint := "check that the node is running, and that the host and port of its URL are right"
+		if cli.Config != nil {
+			hint += fmt.Sprintf(" (CLIENT_NODE_URL is %s)", cli.Config.ClientNodeURL())
+		}
+		hints = append(hints, hint)
+	}
+	return hints
 }
 
 // AppFactory implements the NewApplication method.
@@ -353,17 +485,87 @@ type HTTPClientConfig interface {
 	SessionCookieAuthenticatorConfig
 }
 
+// contextHTTPClient is implemented by HTTPClients which can bind their
+// requests to a context, so that they are aborted when it is cancelled.
+type contextHTTPClient interface {
+	WithContext(ctx context.Context) HTTPClient
+}
+
+// proxyHTTPClient is implemented by HTTPClients which can send their requests
+// through a proxy.
+type proxyHTTPClient interface {
+	WithProxy(proxyURL *url.URL) HTTPClient
+}
+
+// redirectHTTPClient is implemented by HTTPClients which can refuse to
+// follow redirects.
+type redirectHTTPClient interface {
+	WithoutRedirects() HTTPClient
+}
+
+// tokenHTTPClient is implemented by HTTPClients which can authenticate with
+// an API token as well as a session cookie, for headless automation.
+type tokenHTTPClient interface {
+	WithAuthToken(token auth.Token) HTTPClient
+}
+
+// statsHTTPClient is implemented by HTTPClients which can count the
+// connections used by their requests.
+type statsHTTPClient interface {
+	WithStats(stats *HTTPStats) HTTPClient
+}
+
+// HTTPStats counts the requests of an HTTPClient and the connections they
+// used, for --http-stats.
+type HTTPStats struct {
+	Requests    int64
+	HTTP2       int64
+	NewConns    int64
+	ReusedConns int64
+}
+
+// trace returns a trace counting the connections got for a request.
+func (s *HTTPStats) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				atomic.AddInt64(&s.ReusedConns, 1)
+			} else {
+				atomic.AddInt64(&s.NewConns, 1)
+			}
+		},
+	}
+}
+
+// record counts the response to a request.
+func (s *HTTPStats) record(resp *http.Response) {
+	atomic.AddInt64(&s.Requests, 1)
+	if resp.ProtoMajor == 2 {
+		atomic.AddInt64(&s.HTTP2, 1)
+	}
+}
+
+// Write writes a summary of the stats to w.
+func (s *HTTPStats) Write(w io.Writer) {
+	fmt.Fprintf(w, "HTTP stats: %d request(s), %d over HTTP/2; %d new connection(s), %d reused\n",
+		atomic.LoadInt64(&s.Requests), atomic.LoadInt64(&s.HTTP2), atomic.LoadInt64(&s.NewConns), atomic.LoadInt64(&s.ReusedConns))
+}
+
 type authenticatedHTTPClient struct {
+	ctx            context.Context
 	config         HTTPClientConfig
 	client         *http.Client
 	cookieAuth     CookieAuthenticator
 	sessionRequest sessions.SessionRequest
+	stats          *HTTPStats
+	authToken      *auth.Token
 }
 
 // NewAuthenticatedHTTPClient uses the CookieAuthenticator to generate a sessionID
 // which is then used for all subsequent HTTP API requests.
 func NewAuthenticatedHTTPClient(config HTTPClientConfig, cookieAuth CookieAuthenticator, sessionRequest sessions.SessionRequest) HTTPClient {
 	return &authenticatedHTTPClient{
+		ctx:            context.Background(),
 		config:         config,
 		client:         newHttpClient(config),
 		cookieAuth:     cookieAuth,
@@ -371,16 +573,80 @@ func NewAuthenticatedHTTPClient(config HTTPClientConfig, cookieAuth CookieAuthen
 	}
 }
 
-func newHttpClient(config SessionCookieAuthenticatorConfig) *http.Client {
-	tr := &http.Transport{
-		// User enables this at their own risk!
-		// #nosec G402
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify()},
+// nodeURLConfig overrides the node URL of an HTTPClientConfig, for talking to
+// a node other than the configured one.
+type nodeURLConfig struct {
+	HTTPClientConfig
+	url string
+}
+
+// ClientNodeURL returns the overridden node URL.
+func (n nodeURLConfig) ClientNodeURL() string {
+	return n.url
+}
+
+// newRemoteHTTPClient returns an HTTPClient for the node at nodeURL, with its
+// own in-memory session authenticated using the credentials in file. If file
+// is empty, the session is authenticated with credentials from the prompt.
+func (cli *Client) newRemoteHTTPClient(nodeURL, file string) (HTTPClient, error) {
+	config := nodeURLConfig{HTTPClientConfig: cli.Config, url: strings.TrimSuffix(nodeURL, "/")}
+	sessionRequest, err := cli.buildSessionRequest(file)
+	if err != nil {
+		return nil, err
+	}
+	cookieAuth := NewSessionCookieAuthenticator(config, &MemoryCookieStore{}, cli.Logger)
+	if _, err = cookieAuth.Authenticate(sessionRequest); err != nil {
+		return nil, errors.Wrapf(err, "failed to authenticate with %s", nodeURL)
 	}
+	return NewAuthenticatedHTTPClient(config, cookieAuth, sessionRequest), nil
+}
+
+func newHttpClient(config SessionCookieAuthenticatorConfig) *http.Client {
+	// Start from the default transport for its dial and idle timeouts, and
+	// HTTP/2 support
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	// Honor HTTP_PROXY, HTTPS_PROXY and NO_PROXY, unless overridden by --proxy
+	tr.Proxy = http.ProxyFromEnvironment
+	// User enables this at their own risk!
+	// #nosec G402
+	tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify()}
+	// Keep a connection alive for each concurrent request of the bulk chain
+	// commands, instead of the default 2, so that they are reused rather than
+	// closed and redialed after every request
+	tr.MaxIdleConnsPerHost = maxChainConcurrency
+	// Setting TLSClientConfig disables HTTP/2 unless it is forced. It is still
+	// negotiated with ALPN, so older nodes fall back to HTTP/1.1
+	tr.ForceAttemptHTTP2 = true
 	if config.InsecureSkipVerify() {
 		fmt.Println("WARNING: INSECURE_SKIP_VERIFY is set to true, skipping SSL certificate verification.")
 	}
-	return &http.Client{Transport: tr}
+	return &http.Client{Transport: tr, CheckRedirect: followSameHostRedirects}
+}
+
+// maxRedirects is how many redirects are followed for a single request.
+const maxRedirects = 10
+
+// followSameHostRedirects is the redirect policy of the HTTP client. A
+// redirect can only be due to a misconfigured node URL, e.g. http instead of
+// https, so it is followed with a warning as long as it stays on the node's
+// host. Redirects to another host are refused, so that the session cookie is
+// not sent there.
+func followSameHostRedirects(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	from := via[len(via)-1].URL
+	if req.URL.Hostname() != via[0].URL.Hostname() {
+		return errors.Errorf("refusing to follow redirect from %s to %s on another host, which could leak the session cookie; check the node URL (CLIENT_NODE_URL)", from, req.URL)
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: %s redirected to %s; check the node URL (CLIENT_NODE_URL)\n", from, req.URL)
+	return nil
+}
+
+// refuseRedirects is the redirect policy of the HTTP client with
+// --no-follow-redirects.
+func refuseRedirects(req *http.Request, via []*http.Request) error {
+	return errors.Errorf("refusing to follow redirect from %s to %s with --no-follow-redirects; check the node URL (CLIENT_NODE_URL)", via[len(via)-1].URL, req.URL)
 }
 
 // Get performs an HTTP Get using the authenticated HTTP client's cookie.
@@ -408,6 +674,56 @@ func (h *authenticatedHTTPClient) Delete(path string) (*http.Response, error) {
 	return h.doRequest("DELETE", path, nil)
 }
 
+// WithContext returns a copy of the client whose requests use ctx.
+func (h *authenticatedHTTPClient) WithContext(ctx context.Context) HTTPClient {
+	hc := *h
+	hc.ctx = ctx
+	return &hc
+}
+
+// WithProxy returns a copy of the client which sends all requests through
+// proxyURL, ignoring the proxy environment variables.
+func (h *authenticatedHTTPClient) WithProxy(proxyURL *url.URL) HTTPClient {
+	hc := *h
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if orig, ok := h.client.Transport.(*http.Transport); ok {
+		tr = orig.Clone()
+	}
+	tr.Proxy = http.ProxyURL(proxyURL)
+	client := *h.client
+	client.Transport = tr
+	hc.client = &client
+	return &hc
+}
+
+// WithoutRedirects returns a copy of the client which fails requests
+// redirected by the node, rather than following them.
+func (h *authenticatedHTTPClient) WithoutRedirects() HTTPClient {
+	hc := *h
+	client := *h.client
+	client.CheckRedirect = refuseRedirects
+	hc.client = &client
+	return &hc
+}
+
+// WithAuthToken returns a copy of the client which authenticates its requests
+// with the API token, in the X-API-KEY and X-API-SECRET headers of the node.
+// The session cookie is still sent, for the node to fall back to if it
+// rejects the token.
+func (h *authenticatedHTTPClient) WithAuthToken(token auth.Token) HTTPClient {
+	hc := *h
+	hc.authToken = &token
+	return &hc
+}
+
+// WithStats returns a copy of the client which counts its requests and the
+// connections they used in stats.
+func (h *authenticatedHTTPClient) WithStats(stats *HTTPStats) HTTPClient {
+	hc := *h
+	hc.stats = stats
+	return &hc
+}
+
 func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, headerArgs ...map[string]string) (*http.Response, error) {
 	var headers map[string]string
 	if len(headerArgs) > 0 {
@@ -416,7 +732,11 @@ func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, h
 		headers = map[string]string{}
 	}
 
-	request, err := http.NewRequest(verb, h.config.ClientNodeURL()+path, body)
+	ctx := h.ctx
+	if h.stats != nil {
+		ctx = httptrace.WithClientTrace(ctx, h.stats.trace())
+	}
+	request, err := http.NewRequestWithContext(ctx, verb, h.config.ClientNodeURL()+path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -425,6 +745,12 @@ func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, h
 	for key, value := range headers {
 		request.Header.Add(key, value)
 	}
+	if h.authToken != nil {
+		// The node checks the token first, and only falls back to the session
+		// cookie if the token is rejected
+		request.Header.Set(webauth.APIKey, h.authToken.AccessKey)
+		request.Header.Set(webauth.APISecret, h.authToken.Secret)
+	}
 	cookie, err := h.cookieAuth.Cookie()
 	if err != nil {
 		return nil, err
@@ -436,9 +762,11 @@ func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, h
 	if err != nil {
 		return response, err
 	}
+	if h.stats != nil {
+		h.stats.record(response)
+	}
 	if response.StatusCode == http.StatusUnauthorized && (h.sessionRequest.Email != "" || h.sessionRequest.Password != "") {
-		var cookieerr error
-		cookie, cookieerr = h.cookieAuth.Authenticate(h.sessionRequest)
+		cookie, cookieerr := h.cookieAuth.Authenticate(h.sessionRequest)
 		if cookieerr != nil {
 			return response, err
 		}
@@ -448,10 +776,373 @@ func (h *authenticatedHTTPClient) doRequest(verb, path string, body io.Reader, h
 		if err != nil {
 			return response, err
 		}
+		if h.stats != nil {
+			h.stats.record(response)
+		}
 	}
 	return response, nil
 }
 
+// readOnlyHTTPClient is the HTTPClient of the chain commands in --check mode,
+// which only lets GET requests through, so that no command can mutate the
+// node even if it does not handle --check itself.
+type readOnlyHTTPClient struct {
+	HTTPClient
+}
+
+func errReadOnly(method, path string) error {
+	return errors.Errorf("refusing to %s %s in --check mode", method, path)
+}
+
+// Post refuses to perform an HTTP Post.
+func (h readOnlyHTTPClient) Post(path string, _ io.Reader) (*http.Response, error) {
+	return nil, errReadOnly(http.MethodPost, path)
+}
+
+// Put refuses to perform an HTTP Put.
+func (h readOnlyHTTPClient) Put(path string, _ io.Reader) (*http.Response, error) {
+	return nil, errReadOnly(http.MethodPut, path)
+}
+
+// Patch refuses to perform an HTTP Patch.
+func (h readOnlyHTTPClient) Patch(path string, _ io.Reader, _ ...map[string]string) (*http.Response, error) {
+	return nil, errReadOnly(http.MethodPatch, path)
+}
+
+// Delete refuses to perform an HTTP Delete.
+func (h readOnlyHTTPClient) Delete(path string) (*http.Response, error) {
+	return nil, errReadOnly(http.MethodDelete, path)
+}
+
+// maxCachedGETBytes bounds the size of the bodies kept by cachingHTTPClient.
+const maxCachedGETBytes = 8 << 20
+
+// cachingHTTPClient wraps an HTTPClient for the lifetime of a single command,
+// so that identical GETs are only sent once. Successful responses are kept by
+// path, evicting the oldest once the bodies exceed maxBytes. Requests with
+// extra headers are not cached, and mutating requests clear the cache, so that
+// later GETs see their effect.
+type cachingHTTPClient struct {
+	HTTPClient
+	maxBytes int
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	order   []string
+	size    int
+}
+
+// cachedResponse is a response kept by cachingHTTPClient.
+type cachedResponse struct {
+	resp http.Response
+	body []byte
+}
+
+func newCachingHTTPClient(h HTTPClient, maxBytes int) *cachingHTTPClient {
+	return &cachingHTTPClient{HTTPClient: h, maxBytes: maxBytes, entries: map[string]*cachedResponse{}}
+}
+
+// Get performs an HTTP Get, answering from the cache if the path was already
+// fetched.
+func (h *cachingHTTPClient) Get(path string, headers ...map[string]string) (*http.Response, error) {
+	if len(headers) > 0 {
+		return h.HTTPClient.Get(path, headers...)
+	}
+	h.mu.Lock()
+	cached, ok := h.entries[path]
+	h.mu.Unlock()
+	if ok {
+		return cached.response(), nil
+	}
+	resp, err := h.HTTPClient.Get(path)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if cerr := resp.Body.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	cached = &cachedResponse{resp: *resp, body: body}
+	h.store(path, cached)
+	return cached.response(), nil
+}
+
+// store adds resp to the cache, evicting the oldest entries to stay within
+// maxBytes. Responses larger than maxBytes are not kept.
+func (h *cachingHTTPClient) store(path string, resp *cachedResponse) {
+	if len(resp.body) > h.maxBytes {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.entries[path]; ok {
+		return
+	}
+	for h.size+len(resp.body) > h.maxBytes {
+		oldest := h.order[0]
+		h.order = h.order[1:]
+		h.size -= len(h.entries[oldest].body)
+		delete(h.entries, oldest)
+	}
+	h.entries[path] = resp
+	h.order = append(h.order, path)
+	h.size += len(resp.body)
+}
+
+// clear empties the cache.
+func (h *cachingHTTPClient) clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries, h.order, h.size = map[string]*cachedResponse{}, nil, 0
+}
+
+// response returns a copy of the cached response with its own body reader.
+func (r *cachedResponse) response() *http.Response {
+	resp := r.resp
+	resp.Body = ioutil.NopCloser(bytes.NewReader(r.body))
+	return &resp
+}
+
+// Post performs an HTTP Post, clearing the cache.
+func (h *cachingHTTPClient) Post(path string, body io.Reader) (*http.Response, error) {
+	h.clear()
+	return h.HTTPClient.Post(path, body)
+}
+
+// Put performs an HTTP Put, clearing the cache.
+func (h *cachingHTTPClient) Put(path string, body io.Reader) (*http.Response, error) {
+	h.clear()
+	return h.HTTPClient.Put(path, body)
+}
+
+// Patch performs an HTTP Patch, clearing the cache.
+func (h *cachingHTTPClient) Patch(path string, body io.Reader, headers ...map[string]string) (*http.Response, error) {
+	h.clear()
+	return h.HTTPClient.Patch(path, body, headers...)
+}
+
+// Delete performs an HTTP Delete, clearing the cache.
+func (h *cachingHTTPClient) Delete(path string) (*http.Response, error) {
+	h.clear()
+	return h.HTTPClient.Delete(path)
+}
+
+// retryConditions is a set of the conditions under which retryingHTTPClient
+// retries a request.
+type retryConditions uint8
+
+const (
+	// retryOn5xx retries responses with a 5xx server error status.
+	retryOn5xx retryConditions = 1 << iota
+	// retryOn429 retries responses with 429 Too Many Requests.
+	retryOn429
+	// retryOnTimeout retries requests which timed out.
+	retryOnTimeout
+	// retryOnConnection retries requests which failed to connect to the
+	// node, or whose connection was closed before a response.
+	retryOnConnection
+)
+
+// retryConditionNames maps the tokens of --retry-on to their conditions.
+var retryConditionNames = map[string]retryConditions{
+	"5xx":        retryOn5xx,
+	"429":        retryOn429,
+	"timeout":    retryOnTimeout,
+	"connection": retryOnConnection,
+}
+
+// defaultRetryOn is the default of --retry-on, which retries requests that
+// never reached the node or got no answer from it, and those rate limited by
+// the node, after its Retry-After delay.
+const defaultRetryOn = "timeout,connection,429"
+
+// parseRetryConditions parses the comma-separated list of conditions of
+// --retry-on.
+func parseRetryConditions(s string) (retryConditions, error) {
+	var on retryConditions
+	for _, token := range strings.Split(s, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		condition, ok := retryConditionNames[token]
+		if !ok {
+			return 0, errors.Errorf("unsupported --retry-on condition '%s', options: [5xx, 429, timeout, connection]", token)
+		}
+		on |= condition
+	}
+	return on, nil
+}
+
+// errorCondition returns the condition of the transport error err, or zero
+// if it must not be retried, as when the request was cancelled.
+func errorCondition(err error) retryConditions {
+	var netErr net.Error
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return 0
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return retryOnTimeout
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return retryOnConnection
+	}
+	return 0
+}
+
+// requestMayHaveReached returns whether the request which failed with the
+// transport error err may have reached the node, and so have been applied.
+// Only requests which failed to dial are known not to have left the client.
+func requestMayHaveReached(err error) bool {
+	var opErr *net.OpError
+	return !errors.As(err, &opErr) || opErr.Op != "dial"
+}
+
+// statusCondition returns the condition of a response with status, or zero
+// if it is never retried.
+func statusCondition(status int) retryConditions {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return retryOn429
+	case status >= http.StatusInternalServerError:
+		return retryOn5xx
+	}
+	return 0
+}
+
+// retryingHTTPClient wraps an HTTPClient, retrying requests which failed
+// under one of its conditions. Retries wait for the delay requested by the
+// node's Retry-After header, or else back off linearly. Requests other than
+// GET may change the node, so transport errors only retry them if they failed
+// to dial, as the node may have applied a request which then timed out or
+// lost its connection.
+type retryingHTTPClient struct {
+	HTTPClient
+	retries int
+	on      retryConditions
+	sleep   func(time.Duration)
+}
+
+// NewRetryingHTTPClient returns an HTTPClient which retries requests made
+// with h up to retries times, when they fail under one of the conditions on.
+func NewRetryingHTTPClient(h HTTPClient, retries int, on retryConditions) HTTPClient {
+	return &retryingHTTPClient{HTTPClient: h, retries: retries, on: on, sleep: time.Sleep}
+}
+
+// Get performs an HTTP Get, retrying on the conditions of the client.
+func (h *retryingHTTPClient) Get(path string, headers ...map[string]string) (*http.Response, error) {
+	return h.do(true, nil, func(io.Reader) (*http.Response, error) { return h.HTTPClient.Get(path, headers...) })
+}
+
+// Post performs an HTTP Post, retrying on the conditions of the client.
+func (h *retryingHTTPClient) Post(path string, body io.Reader) (*http.Response, error) {
+	return h.do(false, body, func(b io.Reader) (*http.Response, error) { return h.HTTPClient.Post(path, b) })
+}
+
+// Put performs an HTTP Put, retrying on the conditions of the client.
+func (h *retryingHTTPClient) Put(path string, body io.Reader) (*http.Response, error) {
+	return h.do(false, body, func(b io.Reader) (*http.Response, error) { return h.HTTPClient.Put(path, b) })
+}
+
+// Patch performs an HTTP Patch, retrying on the conditions of the client.
+func (h *retryingHTTPClient) Patch(path string, body io.Reader, headers ...map[string]string) (*http.Response, error) {
+	return h.do(false, body, func(b io.Reader) (*http.Response, error) { return h.HTTPClient.Patch(path, b, headers...) })
+}
+
+// Delete performs an HTTP Delete, retrying on the conditions of the client.
+func (h *retryingHTTPClient) Delete(path string) (*http.Response, error) {
+	return h.do(false, nil, func(io.Reader) (*http.Response, error) { return h.HTTPClient.Delete(path) })
+}
+
+// WithContext returns a copy of the client whose requests use ctx, and which
+// stops waiting to retry when ctx is cancelled.
+func (h *retryingHTTPClient) WithContext(ctx context.Context) HTTPClient {
+	inner := h.HTTPClient
+	if ch, ok := inner.(contextHTTPClient); ok {
+		inner = ch.WithContext(ctx)
+	}
+	return &retryingHTTPClient{HTTPClient: inner, retries: h.retries, on: h.on, sleep: func(d time.Duration) {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+	}}
+}
+
+// WithProxy returns a copy of the client which sends all requests through
+// proxyURL.
+func (h *retryingHTTPClient) WithProxy(proxyURL *url.URL) HTTPClient {
+	inner := h.HTTPClient
+	if ph, ok := inner.(proxyHTTPClient); ok {
+		inner = ph.WithProxy(proxyURL)
+	}
+	return &retryingHTTPClient{HTTPClient: inner, retries: h.retries, on: h.on, sleep: h.sleep}
+}
+
+// retriedBody is the body of a response which still failed after retries,
+// for parseResponse to report how much of the retry budget was spent.
+type retriedBody struct {
+	io.ReadCloser
+	retries int
+	elapsed time.Duration
+}
+
+// maxRetryAfter is the longest Retry-After which the client waits for before
+// retrying. A response asking for a longer wait is returned, and fails as
+// rate limited.
+const maxRetryAfter = time.Minute
+
+// do buffers body so that it can be resent, and calls send until the
+// request does not fail under a condition of the client or the retries are
+// exhausted, or the node asks to wait longer than maxRetryAfter. Unless safe,
+// the request is not resent after a transport error which may have happened
+// once it reached the node.
+func (h *retryingHTTPClient) do(safe bool, body io.Reader, send func(io.Reader) (*http.Response, error)) (*http.Response, error) {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = ioutil.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		resp, err := send(bytes.NewReader(b))
+		if err != nil {
+			if h.on&errorCondition(err) == 0 || (!safe && requestMayHaveReached(err)) {
+				return nil, err
+			}
+			if attempt >= h.retries {
+				if attempt > 0 {
+					err = errRetriesExhausted{retries: attempt, elapsed: time.Since(start), err: err}
+				}
+				return nil, err
+			}
+			h.sleep(time.Duration(attempt+1) * time.Second)
+			continue
+		}
+		if h.on&statusCondition(resp.StatusCode) == 0 {
+			return resp, nil
+		}
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if attempt >= h.retries || wait > maxRetryAfter {
+			if attempt > 0 {
+				resp.Body = retriedBody{ReadCloser: resp.Body, retries: attempt, elapsed: time.Since(start)}
+			}
+			return resp, nil
+		}
+		if wait <= 0 {
+			wait = time.Duration(attempt+1) * time.Second
+		}
+		if err = resp.Body.Close(); err != nil {
+			return nil, err
+		}
+		h.sleep(wait)
+	}
+}
+
 // CookieAuthenticator is the interface to generating a cookie to authenticate
 // future HTTP requests.
 type CookieAuthenticator interface {
@@ -552,6 +1243,55 @@ func (m *MemoryCookieStore) Retrieve() (*http.Cookie, error) {
 	return m.Cookie, nil
 }
 
+// SessionCookieEnv is the environment variable from which EnvCookieStore
+// reads the session cookie, in the Cookie header form clsession=<value>, as
+// saved in the cookie file after a login.
+const SessionCookieEnv = "CL_SESSION_COOKIE"
+
+// AuthTokenEnv is the environment variable of --auth-token, an API token of
+// the form <access key>:<secret>. The node authenticates requests by the token
+// when it is valid, and by the session cookie otherwise.
+const AuthTokenEnv = "CL_AUTH_TOKEN"
+
+// parseAuthToken parses an API token of the form <access key>:<secret>, as
+// given with --auth-token.
+func parseAuthToken(v string) (auth.Token, error) {
+	key, secret, ok := strings.Cut(v, ":")
+	if !ok || key == "" || secret == "" {
+		return auth.Token{}, errors.Errorf("invalid --auth-token or %s: expected an API token of the form <access key>:<secret>", AuthTokenEnv)
+	}
+	return auth.Token{AccessKey: key, Secret: secret}, nil
+}
+
+// EnvCookieStore prefers the session cookie set in SessionCookieEnv, so that
+// CI pipelines can authenticate without a login step or a persisted cookie
+// file. Without it set, it falls back to the wrapped CookieStore.
+type EnvCookieStore struct {
+	CookieStore
+}
+
+// Retrieve returns the cookie from SessionCookieEnv if set, or else any
+// cookie saved in the wrapped store.
+func (e EnvCookieStore) Retrieve() (*http.Cookie, error) {
+	v, ok := os.LookupEnv(SessionCookieEnv)
+	if !ok {
+		return e.CookieStore.Retrieve()
+	}
+	return parseSessionCookie(v)
+}
+
+func parseSessionCookie(v string) (*http.Cookie, error) {
+	header := http.Header{}
+	header.Add("Cookie", strings.TrimSpace(v))
+	request := http.Request{Header: header}
+	for _, cookie := range request.Cookies() {
+		if cookie.Name == webauth.SessionName && cookie.Value != "" {
+			return cookie, nil
+		}
+	}
+	return nil, errors.Errorf("invalid %s: expected a cookie of the form %s=<value>", SessionCookieEnv, webauth.SessionName)
+}
+
 type DiskCookieConfig interface {
 	RootDir() string
 }
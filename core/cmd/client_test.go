@@ -1,22 +1,115 @@
 package cmd_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	ginsessions "github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"github.com/smartcontractkit/chainlink/core/auth"
+	"github.com/smartcontractkit/chainlink/core/bridges"
 	"github.com/smartcontractkit/chainlink/core/cmd"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/testutils"
 	"github.com/smartcontractkit/chainlink/core/internal/testutils/pgtest"
 	"github.com/smartcontractkit/chainlink/core/logger"
 	"github.com/smartcontractkit/chainlink/core/sessions"
+	webauth "github.com/smartcontractkit/chainlink/core/web/auth"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
 )
 
 type cfg struct{}
 
+// stubRequest is a request recorded by stubHTTPClient.
+type stubRequest struct {
+	method, path string
+	body         []byte
+}
+
+// stubHTTPClient is a cmd.HTTPClient which records requests and replies with
+// the queued responses in order, repeating the last one once exhausted.
+type stubHTTPClient struct {
+	responses []*http.Response
+	requests  []stubRequest
+}
+
+func (h *stubHTTPClient) Get(path string, _ ...map[string]string) (*http.Response, error) {
+	return h.do("GET", path, nil)
+}
+
+func (h *stubHTTPClient) Post(path string, body io.Reader) (*http.Response, error) {
+	return h.do("POST", path, body)
+}
+
+func (h *stubHTTPClient) Put(path string, body io.Reader) (*http.Response, error) {
+	return h.do("PUT", path, body)
+}
+
+func (h *stubHTTPClient) Patch(path string, body io.Reader, _ ...map[string]string) (*http.Response, error) {
+	return h.do("PATCH", path, body)
+}
+
+func (h *stubHTTPClient) Delete(path string) (*http.Response, error) {
+	return h.do("DELETE", path, nil)
+}
+
+func (h *stubHTTPClient) do(method, path string, body io.Reader) (*http.Response, error) {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = ioutil.ReadAll(body); err != nil {
+			return nil, err
+		}
+	}
+	h.requests = append(h.requests, stubRequest{method: method, path: path, body: b})
+	i := len(h.requests) - 1
+	if i >= len(h.responses) {
+		i = len(h.responses) - 1
+	}
+	resp := *h.responses[i]
+	resp.Body = ioutil.NopCloser(bytes.NewReader(resp.Body.(stubBody)))
+	return &resp, nil
+}
+
+// stubBody holds the body of a queued response, so that it can be replayed.
+type stubBody []byte
+
+func (stubBody) Read([]byte) (int, error) { return 0, io.EOF }
+func (stubBody) Close() error             { return nil }
+
+// stubResponse returns a response for stubHTTPClient with the given status,
+// body and header key value pairs.
+func stubResponse(status int, body string, header ...string) *http.Response {
+	h := http.Header{}
+	for i := 0; i+1 < len(header); i += 2 {
+		h.Set(header[i], header[i+1])
+	}
+	return &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Header:     h,
+		Body:       stubBody(body),
+	}
+}
+
 func (c cfg) ClientNodeURL() string    { return "" }
 func (c cfg) InsecureSkipVerify() bool { return false }
 
@@ -291,3 +384,629 @@ func TestFileSessionRequestBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestCachingHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	get := func(h cmd.HTTPClient, path string) string {
+		resp, err := h.Get(path)
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return string(b)
+	}
+
+	stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, `{"data":"a"}`)}}
+	h := cmd.NewCachingHTTPClient(stub, 1024)
+	// A repeated GET is answered from the cache
+	assert.Equal(t, `{"data":"a"}`, get(h, "/v2/chains/solana/a"))
+	assert.Equal(t, `{"data":"a"}`, get(h, "/v2/chains/solana/a"))
+	require.Len(t, stub.requests, 1)
+	get(h, "/v2/chains/solana/b")
+	require.Len(t, stub.requests, 2)
+
+	// Mutating requests bypass and clear the cache
+	_, err := h.Patch("/v2/chains/solana/a", strings.NewReader("{}"))
+	require.NoError(t, err)
+	get(h, "/v2/chains/solana/a")
+	require.Len(t, stub.requests, 4)
+	assert.Equal(t, http.MethodGet, stub.requests[3].method)
+
+	// Failed responses are not cached
+	stub = &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusInternalServerError, `{}`), stubResponse(http.StatusOK, `{}`)}}
+	h = cmd.NewCachingHTTPClient(stub, 1024)
+	get(h, "/v2/chains/solana/a")
+	get(h, "/v2/chains/solana/a")
+	get(h, "/v2/chains/solana/a")
+	require.Len(t, stub.requests, 2)
+
+	// The oldest responses are evicted to stay within the size bound
+	stub = &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, `0123456789`)}}
+	h = cmd.NewCachingHTTPClient(stub, 25)
+	get(h, "/a")
+	get(h, "/b")
+	get(h, "/c")
+	get(h, "/b")
+	require.Len(t, stub.requests, 3)
+	get(h, "/a")
+	require.Len(t, stub.requests, 4)
+
+	assert.True(t, cmd.CachedGETCommand("chains solana stats"))
+	assert.False(t, cmd.CachedGETCommand("chains solana wait"))
+}
+
+func TestRetryingHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusTooManyRequests, "", "Retry-After", "3"),
+		stubResponse(http.StatusTooManyRequests, ""),
+		stubResponse(http.StatusOK, "{}"),
+	}}
+	var waits []time.Duration
+	h := cmd.NewRetryingHTTPClientWithSleep(stub, 2, func(d time.Duration) { waits = append(waits, d) })
+
+	resp, err := h.Post("/v2/chains/solana", strings.NewReader("body"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{3 * time.Second, 2 * time.Second}, waits)
+	require.Len(t, stub.requests, 3)
+	for _, r := range stub.requests {
+		assert.Equal(t, "body", string(r.body))
+	}
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusTooManyRequests, "", "Retry-After", "1")}}
+		h := cmd.NewRetryingHTTPClientWithSleep(stub, 1, func(time.Duration) {})
+
+		resp, err := h.Get("/v2/chains/solana")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Len(t, stub.requests, 2)
+
+		// The final error reports the retries
+		_, err = (&cmd.Client{}).ParseResponse(resp)
+		require.Error(t, err)
+		assert.Regexp(t, `^failed after 1 retry over [0-9.]+m?s; last error: HTTP 429: rate limited; retry after 1 seconds`, err.Error())
+	})
+
+	t.Run("does not wait past the longest Retry-After", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusTooManyRequests, "", "Retry-After", "3600")}}
+		var waits []time.Duration
+		h := cmd.NewRetryingHTTPClientWithSleep(stub, 3, func(d time.Duration) { waits = append(waits, d) })
+
+		resp, err := h.Get("/v2/chains/solana")
+		require.NoError(t, err)
+		assert.Empty(t, waits)
+		assert.Len(t, stub.requests, 1)
+
+		_, err = (&cmd.Client{}).ParseResponse(resp)
+		assert.EqualError(t, err, "rate limited; retry after 3600 seconds")
+
+		stub = &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusTooManyRequests, "", "Retry-After", "60"),
+			stubResponse(http.StatusOK, "{}"),
+		}}
+		waits = nil
+		h = cmd.NewRetryingHTTPClientWithSleep(stub, 3, func(d time.Duration) { waits = append(waits, d) })
+		resp, err = h.Get("/v2/chains/solana")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []time.Duration{time.Minute}, waits)
+	})
+}
+
+// flakyHTTPClient fails its first requests with errs, then answers them as
+// its stubHTTPClient.
+type flakyHTTPClient struct {
+	stubHTTPClient
+	errs  []error
+	calls int
+}
+
+func (h *flakyHTTPClient) Get(path string, headers ...map[string]string) (*http.Response, error) {
+	h.calls++
+	if len(h.errs) > 0 {
+		err := h.errs[0]
+		h.errs = h.errs[1:]
+		return nil, err
+	}
+	return h.stubHTTPClient.Get(path, headers...)
+}
+
+func (h *flakyHTTPClient) Post(path string, body io.Reader) (*http.Response, error) {
+	h.calls++
+	if len(h.errs) > 0 {
+		err := h.errs[0]
+		h.errs = h.errs[1:]
+		return nil, err
+	}
+	return h.stubHTTPClient.Post(path, body)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryingHTTPClient_RetryOn(t *testing.T) {
+	t.Parallel()
+
+	timeout := &url.Error{Op: "Get", URL: "http://localhost:6688/v2/chains/solana", Err: timeoutError{}}
+	refused := &url.Error{Op: "Get", URL: "http://localhost:6688/v2/chains/solana", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	get := func(t *testing.T, on string, h *flakyHTTPClient) (*http.Response, error) {
+		client, err := cmd.NewRetryingHTTPClientOn(h, 2, on, func(time.Duration) {})
+		require.NoError(t, err)
+		return client.Get("/v2/chains/solana")
+	}
+
+	t.Run("default retries timeouts and connection errors", func(t *testing.T) {
+		h := &flakyHTTPClient{errs: []error{timeout, refused}, stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, "{}")}}}
+		resp, err := get(t, cmd.DefaultRetryOn, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, h.calls)
+	})
+
+	t.Run("default retries rate limited requests but not server errors", func(t *testing.T) {
+		h := &flakyHTTPClient{stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusTooManyRequests, ""), stubResponse(http.StatusOK, "{}")}}}
+		resp, err := get(t, cmd.DefaultRetryOn, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, h.calls)
+
+		h = &flakyHTTPClient{stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusInternalServerError, ""), stubResponse(http.StatusOK, "{}")}}}
+		resp, err = get(t, cmd.DefaultRetryOn, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, 1, h.calls)
+	})
+
+	t.Run("requests changing the node are only resent if they failed to dial", func(t *testing.T) {
+		post := func(h *flakyHTTPClient) (*http.Response, error) {
+			client, err := cmd.NewRetryingHTTPClientOn(h, 2, cmd.DefaultRetryOn, func(time.Duration) {})
+			require.NoError(t, err)
+			return client.Post("/v2/chains/solana", strings.NewReader(`{"chainID":"devnet"}`))
+		}
+		reset := &url.Error{Op: "Post", URL: "http://localhost:6688/v2/chains/solana", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+		for _, err := range []error{timeout, reset, io.ErrUnexpectedEOF} {
+			h := &flakyHTTPClient{errs: []error{err}, stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusCreated, "{}")}}}
+			_, perr := post(h)
+			assert.Equal(t, err, perr)
+			assert.Equal(t, 1, h.calls, "%v", err)
+			assert.Empty(t, h.requests)
+		}
+
+		h := &flakyHTTPClient{errs: []error{refused}, stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusCreated, "{}")}}}
+		resp, err := post(h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, 2, h.calls)
+		require.Len(t, h.requests, 1)
+		assert.Equal(t, `{"chainID":"devnet"}`, string(h.requests[0].body))
+	})
+
+	t.Run("only the listed conditions", func(t *testing.T) {
+		h := &flakyHTTPClient{errs: []error{refused}, stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, "{}")}}}
+		_, err := get(t, "timeout", h)
+		assert.Equal(t, refused, err)
+		assert.Equal(t, 1, h.calls)
+
+		h = &flakyHTTPClient{stubHTTPClient: stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusBadGateway, ""),
+			stubResponse(http.StatusTooManyRequests, ""),
+			stubResponse(http.StatusOK, "{}"),
+		}}}
+		resp, err := get(t, "5xx, 429", h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, h.calls)
+
+		// Client errors are never retried
+		h = &flakyHTTPClient{stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusNotFound, ""), stubResponse(http.StatusOK, "{}")}}}
+		resp, err = get(t, "5xx,429,timeout,connection", h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		h := &flakyHTTPClient{errs: []error{timeout, timeout, timeout}}
+		_, err := get(t, "timeout", h)
+		require.Error(t, err)
+		assert.Regexp(t, `^failed after 2 retries over [0-9.]+m?s; last error: Get "http://localhost:6688/v2/chains/solana": i/o timeout$`, err.Error())
+		// The timeout is still reported as a network failure
+		assert.Equal(t, cmd.ExitCodeNetwork, (&cmd.Client{}).ErrorOut(err).(cli.ExitCoder).ExitCode())
+		assert.Equal(t, 3, h.calls)
+
+		h = &flakyHTTPClient{stubHTTPClient: stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusServiceUnavailable, `{"errors":[{"detail":"down"}]}`)}}}
+		resp, err := get(t, "5xx", h)
+		require.NoError(t, err)
+		_, err = (&cmd.Client{}).ParseResponse(resp)
+		assert.Regexp(t, `^failed after 2 retries over [0-9.]+m?s; last error: HTTP 503: Error; down$`, err.Error())
+	})
+
+	_, err := cmd.NewRetryingHTTPClientOn(&stubHTTPClient{}, 1, "timeout,500", func(time.Duration) {})
+	assert.EqualError(t, err, "unsupported --retry-on condition '500', options: [5xx, 429, timeout, connection]")
+
+	app := cmd.NewApp(&cmd.Client{Config: cltest.NewTestGeneralConfig(t), Renderer: cmd.RendererTable{Writer: ioutil.Discard}})
+	err = app.Run([]string{"chainlink", "--retries", "2", "--retry-on", "timeout,sometimes", "chains", "solana", "ls"})
+	assert.EqualError(t, err, "unsupported --retry-on condition 'sometimes', options: [5xx, 429, timeout, connection]")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 120*time.Second, cmd.ParseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, cmd.ParseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, cmd.ParseRetryAfter("", now))
+	assert.Zero(t, cmd.ParseRetryAfter("-1", now))
+	assert.Zero(t, cmd.ParseRetryAfter("soon", now))
+}
+
+func TestClient_ParseResponse_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{}
+	_, err := client.ParseResponse(stubResponse(http.StatusTooManyRequests, "", "Retry-After", "5"))
+	assert.EqualError(t, err, "rate limited; retry after 5 seconds")
+}
+
+func TestClient_ResponseErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors":[{"detail":"invalid config"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"detail":"chain not found"}]}`))
+	}))
+	defer srv.Close()
+	client := &cmd.Client{HTTP: cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, "session")}
+
+	resp, err := client.HTTP.Patch("/v2/chains/solana/foo", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_, err = client.ParseResponse(resp)
+	require.NoError(t, resp.Body.Close())
+	assert.EqualError(t, err, "PATCH /v2/chains/solana/foo -> 422: Error; invalid config")
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "foo", "")
+	err = client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+	assert.EqualError(t, err, "GET /v2/chains/solana/foo -> 404: parseResponse error: Error; chain not found")
+}
+
+type httpClientConfig struct {
+	url      string
+	insecure bool
+}
+
+func (c httpClientConfig) ClientNodeURL() string    { return c.url }
+func (c httpClientConfig) InsecureSkipVerify() bool { return c.insecure }
+
+func TestAuthenticatedHTTPClient_WithContext(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, "session")
+	ctx, cancel := context.WithCancel(context.Background())
+	h = h.(interface {
+		WithContext(context.Context) cmd.HTTPClient
+	}).WithContext(ctx)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := h.Get("/v2/chains/solana")
+	assert.True(t, errors.Is(err, context.Canceled), err)
+}
+
+func TestAuthenticatedHTTPClient_WithProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: "http://chainlink.invalid:6688"}, "session")
+	h = h.(interface {
+		WithProxy(*url.URL) cmd.HTTPClient
+	}).WithProxy(proxyURL)
+
+	resp, err := h.Get("/v2/chains/solana")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, []string{"http://chainlink.invalid:6688/v2/chains/solana"}, proxied)
+}
+
+func TestAuthenticatedHTTPClient_Redirects(t *testing.T) {
+	t.Parallel()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer other.Close()
+	otherURL, err := url.Parse(other.URL)
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/moved":
+			http.Redirect(w, r, "/v2/chains/solana", http.StatusMovedPermanently)
+		case "/v2/elsewhere":
+			// localhost rather than 127.0.0.1, so another host
+			http.Redirect(w, r, "http://localhost:"+otherURL.Port()+"/v2/chains/solana", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, "session")
+	resp, err := h.Get("/v2/moved")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "/v2/chains/solana", resp.Request.URL.Path)
+
+	_, err = h.Get("/v2/elsewhere")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "on another host, which could leak the session cookie")
+
+	h = h.(interface {
+		WithoutRedirects() cmd.HTTPClient
+	}).WithoutRedirects()
+	_, err = h.Get("/v2/moved")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "with --no-follow-redirects")
+}
+
+// tokenAuthenticator authenticates the API token of user, and the sessions
+// with ID session.
+type tokenAuthenticator struct {
+	user    sessions.User
+	session string
+}
+
+func (a tokenAuthenticator) AuthorizedUserWithSession(sessionID string) (sessions.User, error) {
+	if sessionID != a.session {
+		return sessions.User{}, auth.ErrorAuthFailed
+	}
+	return a.user, nil
+}
+
+func (a tokenAuthenticator) FindExternalInitiator(*auth.Token) (*bridges.ExternalInitiator, error) {
+	return nil, auth.ErrorAuthFailed
+}
+
+func (a tokenAuthenticator) FindUser() (sessions.User, error) {
+	return a.user, nil
+}
+
+func TestAuthenticatedHTTPClient_WithAuthToken(t *testing.T) {
+	t.Parallel()
+
+	// The node's own auth middleware, as on its /v2 routes
+	user := cltest.MustRandomUser(t)
+	require.NoError(t, user.SetAuthToken(&auth.Token{AccessKey: cltest.APIKey, Secret: cltest.APISecret}))
+	router := gin.New()
+	router.Use(ginsessions.Sessions(webauth.SessionName, ginsessions.NewCookieStore([]byte(cltest.SessionSecret))))
+	router.Use(webauth.Authenticate(tokenAuthenticator{user: user, session: "session"}, webauth.AuthenticateByToken, webauth.AuthenticateBySession))
+	router.GET("/v2/chains/solana", func(c *gin.Context) { c.String(http.StatusOK, "{}") })
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	get := func(session string, token *auth.Token) (*http.Response, error) {
+		h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, session)
+		if token != nil {
+			h = h.(interface {
+				WithAuthToken(auth.Token) cmd.HTTPClient
+			}).WithAuthToken(*token)
+		}
+		return h.Get("/v2/chains/solana")
+	}
+	good := &auth.Token{AccessKey: cltest.APIKey, Secret: cltest.APISecret}
+	bad := &auth.Token{AccessKey: cltest.APIKey, Secret: "bad"}
+
+	resp, err := get("expired", good)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "a valid token wins over the session cookie")
+
+	resp, err = get("session", bad)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the node falls back to the session cookie")
+
+	resp, err = get("expired", bad)
+	require.NoError(t, err)
+	_, err = (&cmd.Client{}).ParseResponse(resp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the node rejected the token of --auth-token or "+cmd.AuthTokenEnv)
+
+	resp, err = get("expired", nil)
+	require.NoError(t, err)
+	_, err = (&cmd.Client{}).ParseResponse(resp)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "or pass --auth-token")
+
+	app := cmd.NewApp(&cmd.Client{Config: cltest.NewTestGeneralConfig(t), Renderer: cmd.RendererTable{Writer: ioutil.Discard}})
+	err = app.Run([]string{"chainlink", "--auth-token", "s3cret", "chains", "solana", "ls"})
+	assert.EqualError(t, err, "invalid --auth-token or "+cmd.AuthTokenEnv+": expected an API token of the form <access key>:<secret>")
+}
+
+// connCountingServer returns a test server which counts the connections
+// opened to it, serving HTTP/2 over TLS if http2 is set.
+func connCountingServer(tb testing.TB, http2 bool) (*httptest.Server, *int64) {
+	var conns int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	if http2 {
+		srv.EnableHTTP2 = true
+		srv.StartTLS()
+	} else {
+		srv.Start()
+	}
+	tb.Cleanup(srv.Close)
+	return srv, &conns
+}
+
+// getConcurrently makes count GET requests with get, 16 at a time, as the
+// bulk chain commands do at their maximum concurrency.
+func getConcurrently(tb testing.TB, count int, get func() (*http.Response, error)) {
+	errs := cmd.ForEachConcurrently(16, count, func(int) error {
+		resp, err := get()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		if cerr := resp.Body.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	})
+	for _, err := range errs {
+		require.NoError(tb, err)
+	}
+}
+
+func TestAuthenticatedHTTPClient_ReusesConnections(t *testing.T) {
+	t.Parallel()
+
+	srv, conns := connCountingServer(t, false)
+	h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, "session")
+	get := func() (*http.Response, error) { return h.Get("/v2/chains/solana") }
+	// getBatch makes a batch of requests at the maximum concurrency, none of
+	// which reads its response before all have one, so that each holds its own
+	// connection
+	getBatch := func() {
+		var received sync.WaitGroup
+		received.Add(cmd.MaxChainConcurrency)
+		getConcurrently(t, cmd.MaxChainConcurrency, func() (*http.Response, error) {
+			resp, err := get()
+			received.Done()
+			received.Wait()
+			return resp, err
+		})
+	}
+
+	// The warm-up opens a connection per request, which are all kept idle
+	// for the following requests
+	getBatch()
+	require.Equal(t, int64(cmd.MaxChainConcurrency), atomic.LoadInt64(conns))
+	for i := 0; i < 3; i++ {
+		getBatch()
+	}
+	for i := 0; i < 10; i++ {
+		getConcurrently(t, 1, get)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt64(conns), int64(cmd.MaxChainConcurrency), "connections should be pooled across requests")
+}
+
+func TestAuthenticatedHTTPClient_HTTPStats(t *testing.T) {
+	t.Parallel()
+
+	for _, http2 := range []bool{false, true} {
+		srv, _ := connCountingServer(t, http2)
+		h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL, insecure: http2}, "session")
+		stats := &cmd.HTTPStats{}
+		h = h.(interface {
+			WithStats(*cmd.HTTPStats) cmd.HTTPClient
+		}).WithStats(stats)
+		for i := 0; i < 3; i++ {
+			getConcurrently(t, 1, func() (*http.Response, error) { return h.Get("/v2/chains/solana") })
+		}
+
+		var http2Requests int64
+		if http2 {
+			http2Requests = 3
+		}
+		assert.Equal(t, cmd.HTTPStats{Requests: 3, HTTP2: http2Requests, NewConns: 1, ReusedConns: 2}, *stats)
+		var b bytes.Buffer
+		stats.Write(&b)
+		assert.Equal(t, fmt.Sprintf("HTTP stats: 3 request(s), %d over HTTP/2; 1 new connection(s), 2 reused\n", http2Requests), b.String())
+	}
+}
+
+// BenchmarkAuthenticatedHTTPClient_Batch measures batches of 100 concurrent
+// requests, reporting the connections opened per batch, against a transport
+// which keeps only the default 2 idle connections per host.
+func BenchmarkAuthenticatedHTTPClient_Batch(b *testing.B) {
+	b.Run("pooled", func(b *testing.B) {
+		srv, conns := connCountingServer(b, false)
+		h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL}, "session")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			getConcurrently(b, 100, func() (*http.Response, error) { return h.Get("/v2/chains/solana") })
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(conns))/float64(b.N), "conns/op")
+	})
+	b.Run("http2", func(b *testing.B) {
+		srv, conns := connCountingServer(b, true)
+		h := cltest.NewMockAuthenticatedHTTPClient(httpClientConfig{url: srv.URL, insecure: true}, "session")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			getConcurrently(b, 100, func() (*http.Response, error) { return h.Get("/v2/chains/solana") })
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(conns))/float64(b.N), "conns/op")
+	})
+	b.Run("default transport", func(b *testing.B) {
+		srv, conns := connCountingServer(b, false)
+		client := &http.Client{Transport: &http.Transport{}}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			getConcurrently(b, 100, func() (*http.Response, error) { return client.Get(srv.URL + "/v2/chains/solana") })
+		}
+		b.ReportMetric(float64(atomic.LoadInt64(conns))/float64(b.N), "conns/op")
+	})
+}
+
+func TestParseProxyURL(t *testing.T) {
+	t.Parallel()
+
+	u, err := cmd.ParseProxyURL("http://corp-proxy:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "corp-proxy:8080", u.Host)
+
+	_, err = cmd.ParseProxyURL("corp-proxy:8080")
+	assert.EqualError(t, err, `invalid --proxy URL "corp-proxy:8080": scheme must be one of http, https or socks5`)
+	_, err = cmd.ParseProxyURL("http://")
+	assert.EqualError(t, err, `invalid --proxy URL "http://": missing host`)
+}
+
+func TestEnvCookieStore(t *testing.T) {
+	fallback := &cmd.MemoryCookieStore{Cookie: &http.Cookie{Name: "clsession", Value: "disk"}}
+	store := cmd.EnvCookieStore{CookieStore: fallback}
+
+	cookie, err := store.Retrieve()
+	require.NoError(t, err)
+	assert.Equal(t, "disk", cookie.Value)
+
+	t.Setenv(cmd.SessionCookieEnv, "clsession=ci")
+	cookie, err = store.Retrieve()
+	require.NoError(t, err)
+	assert.Equal(t, "clsession", cookie.Name)
+	assert.Equal(t, "ci", cookie.Value)
+
+	for _, v := range []string{"", "ci", "other=ci", "clsession="} {
+		t.Setenv(cmd.SessionCookieEnv, v)
+		_, err = store.Retrieve()
+		assert.EqualError(t, err, "invalid CL_SESSION_COOKIE: expected a cookie of the form clsession=<value>", v)
+	}
+}
@@ -29,7 +29,7 @@ func (p *CSAKeyPresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 CSA Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return nil
 }
@@ -56,7 +56,7 @@ func (ps CSAKeyPresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 CSA Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 	return utils.JustError(rt.Write([]byte("\n")))
 }
 
@@ -39,7 +39,7 @@ var ethKeysTableHeaders = []string{"Address", "EVM Chain ID", "ETH", "LINK", "Is
 func (p *EthKeyPresenter) RenderTable(rt RendererTable) error {
 	rows := [][]string{p.ToRow()}
 
-	renderList(ethKeysTableHeaders, rows, rt.Writer)
+	rt.renderList(ethKeysTableHeaders, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -54,7 +54,7 @@ func (ps EthKeyPresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(ethKeysTableHeaders, rows, rt.Writer)
+	rt.renderList(ethKeysTableHeaders, rows)
 
 	return nil
 }
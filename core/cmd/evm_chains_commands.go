@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -21,32 +22,43 @@ type EVMChainPresenter struct {
 	presenters.EVMChainResource
 }
 
-// ToRow presents the EVMChainResource as a slice of strings.
-func (p *EVMChainPresenter) ToRow() []string {
-	// NOTE: it's impossible to omitempty null fields when serializing to JSON: https://github.com/golang/go/issues/11939
-	config, err := json.MarshalIndent(p.Config, "", "    ")
+// ToRow presents the EVMChainResource as a slice of strings. It panics if
+// the config cannot be formatted, see Row.
+func (p *EVMChainPresenter) ToRow(rt RendererTable) []string {
+	row, err := p.Row(rt)
 	if err != nil {
 		panic(err)
 	}
+	return row
+}
+
+// Row presents the EVMChainResource as a slice of strings, or returns an
+// error if its config cannot be formatted.
+func (p *EVMChainPresenter) Row(rt RendererTable) ([]string, error) {
+	config, err := rt.formatChainConfig(p.Config)
+	if err != nil {
+		return nil, err
+	}
 
 	row := []string{
 		p.GetID(),
 		strconv.FormatBool(p.Enabled),
-		string(config),
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		config,
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 	}
-	return row
+	return row, nil
 }
 
 // RenderTable implements TableRenderer
 // Just renders a single row
 func (p EVMChainPresenter) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
-	rows := [][]string{}
-	rows = append(rows, p.ToRow())
+	row, err := p.Row(rt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render chain %s", p.GetID())
+	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, [][]string{row})
 
 	return nil
 }
@@ -56,31 +68,77 @@ type EVMChainPresenters []EVMChainPresenter
 
 // RenderTable implements TableRenderer
 func (ps EVMChainPresenters) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
 	rows := [][]string{}
+	var failed rowErrors
 
 	for _, p := range ps {
-		rows = append(rows, p.ToRow())
+		row, err := p.Row(rt)
+		if err != nil {
+			failed.add(p.GetID(), err)
+			continue
+		}
+		rows = append(rows, row)
 	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, rows)
 
-	return nil
+	return failed.report(rt)
 }
 
 // IndexEVMChains returns all EVM chains.
 func (cli *Client) IndexEVMChains(c *cli.Context) (err error) {
-	return cli.getPage("/v2/chains/evm", c.Int("page"), &EVMChainPresenters{})
+	if err = checkFlagConflicts(c, indexChainsConflicts...); err != nil {
+		return cli.errorOut(err)
+	}
+	cli.setChainRenderOpts(c)
+	uri, err := chainsPageURI(c, "evm")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("raw") {
+		return cli.errorOut(cli.writeRaw(cli.stdout(), uri, c.Int("page")))
+	}
+	if c.Bool("with-links") {
+		return cli.errorOut(cli.renderPageWithLinks(c, uri, &EVMChainPresenters{}))
+	}
+	proj, err := jqFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fields, templateFile := selectFields(c), c.String("template-file")
+	if len(fields) == 0 && !c.Bool("wide") && templateFile == "" && proj == nil {
+		return cli.getPage(uri, c.Int("page"), &EVMChainPresenters{})
+	}
+
+	var chains EVMChainPresenters
+	if err = cli.getAllPages(cli.HTTP, uri, &chains); err != nil {
+		return cli.errorOut(err)
+	}
+	if len(fields) > 0 {
+		return cli.errorOut(writeSelected(cli.stdout(), chains, fields, c.Bool("headers")))
+	}
+	if templateFile != "" {
+		return cli.errorOut(executeTemplateFile(cli.stdout(), templateFile, chains, c.Bool("template-once")))
+	}
+	if proj != nil {
+		return cli.errorOut(writeProjected(cli.stdout(), chains, proj))
+	}
+	wide, err := cli.wideChains("evm", chains)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(&wide))
 }
 
 // CreateEVMChain adds a new EVM chain.
 func (cli *Client) CreateEVMChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in the chain's parameters [-id integer] [JSON blob | JSON filepath]"))
+		return cli.errorOut(usageError(c, errors.New("must pass in the chain's parameters [-id integer] [JSON blob | JSON filepath]")))
 	}
 	chainID := c.Int64("id")
 	if chainID == 0 {
-		return cli.errorOut(errors.New("missing chain ID [-id integer]"))
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id integer]")))
 	}
 
 	buf, err := getBufferFromJSON(c.Args().First())
@@ -88,6 +146,9 @@ func (cli *Client) CreateEVMChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
+	warnChainFamilyMismatch(c, os.Stderr, "evm", buf.Bytes())
+	warnDeprecatedConfigKeys(os.Stderr, "evm", json.RawMessage(buf.Bytes()))
+
 	params := map[string]interface{}{
 		"chainID": chainID,
 		"config":  json.RawMessage(buf.Bytes()),
@@ -115,7 +176,7 @@ func (cli *Client) CreateEVMChain(c *cli.Context) (err error) {
 // RemoveEVMChain removes a specific EVM Chain by id.
 func (cli *Client) RemoveEVMChain(c *cli.Context) (err error) {
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass the id of the chain to be removed"))
+		return cli.errorOut(usageError(c, errors.New("must pass the id of the chain to be removed")))
 	}
 	chainID := c.Args().First()
 	resp, err := cli.HTTP.Delete("/v2/chains/evm/" + chainID)
@@ -127,19 +188,20 @@ func (cli *Client) RemoveEVMChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
-	fmt.Printf("Chain %v deleted\n", c.Args().First())
+	fmt.Fprintf(cli.stdout(), "Chain %v deleted\n", c.Args().First())
 	return nil
 }
 
 // ConfigureEVMChain configures an existing EVM chain.
 func (cli *Client) ConfigureEVMChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
 	chainID := c.Int64("id")
 	if chainID == 0 {
-		return cli.errorOut(errors.New("missing chain ID (usage: chainlink evm chains configure [-id integer] [key1=value1 key2=value2 ...])"))
+		return cli.errorOut(usageError(c, errors.New("missing chain ID (usage: chainlink evm chains configure [-id integer] [key1=value1 key2=value2 ...])")))
 	}
 
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in at least one chain configuration parameters (usage: chainlink evm chains configure [-id integer] [key1=value1 key2=value2 ...])"))
+		return cli.errorOut(usageError(c, errors.New("must pass in at least one chain configuration parameters (usage: chainlink evm chains configure [-id integer] [key1=value1 key2=value2 ...])")))
 	}
 
 	// Fetch existing config
@@ -163,7 +225,7 @@ func (cli *Client) ConfigureEVMChain(c *cli.Context) (err error) {
 	for _, arg := range c.Args() {
 		parts := strings.SplitN(arg, "=", 2)
 		if len(parts) != 2 {
-			return cli.errorOut(errors.Errorf("invalid parameter: %v", arg))
+			return cli.errorOut(usageError(c, errors.Errorf("invalid parameter: %v", arg)))
 		}
 
 		var value interface{}
@@ -186,6 +248,8 @@ func (cli *Client) ConfigureEVMChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
+	warnDeprecatedConfigKeys(os.Stderr, "evm", config)
+
 	// Send the new config
 	params = map[string]interface{}{
 		"enabled": chain.Enabled,
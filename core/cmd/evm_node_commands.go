@@ -27,8 +27,8 @@ func (p *EVMNodePresenter) ToRow() []string {
 		p.EVMChainID.ToInt().String(),
 		p.WSURL.ValueOrZero(),
 		p.HTTPURL.ValueOrZero(),
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 		p.State,
 	}
 	return row
@@ -40,7 +40,7 @@ var evmNodeHeaders = []string{"ID", "Name", "Chain ID", "Websocket URL", "HTTP U
 func (p EVMNodePresenter) RenderTable(rt RendererTable) error {
 	var rows [][]string
 	rows = append(rows, p.ToRow())
-	renderList(evmNodeHeaders, rows, rt.Writer)
+	rt.renderList(evmNodeHeaders, rows)
 
 	return nil
 }
@@ -56,7 +56,7 @@ func (ps EVMNodePresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(evmNodeHeaders, rows, rt.Writer)
+	rt.renderList(evmNodeHeaders, rows)
 
 	return nil
 }
@@ -27,7 +27,7 @@ func mustInsertEVMChain(t *testing.T, orm types.ORM) types.Chain {
 func assertTableRenders(t *testing.T, r *cltest.RendererMock) {
 	// Should be no error rendering any of the responses as tables
 	b := bytes.NewBuffer([]byte{})
-	tb := cmd.RendererTable{b}
+	tb := cmd.RendererTable{Writer: b}
 	for _, rn := range r.Renders {
 		require.NoError(t, tb.Render(rn))
 	}
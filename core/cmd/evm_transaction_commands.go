@@ -32,7 +32,7 @@ func (p *EthTxPresenter) RenderTable(rt RendererTable) error {
 		fmt.Sprint(p.State),
 	})
 
-	render(fmt.Sprintf("Ethereum Transaction %v", p.Hash.Hex()), table)
+	rt.render(fmt.Sprintf("Ethereum Transaction %v", p.Hash.Hex()), table)
 	return nil
 }
 
@@ -52,7 +52,7 @@ func (ps EthTxPresenters) RenderTable(rt RendererTable) error {
 		})
 	}
 
-	render("Ethereum Transactions", table)
+	rt.render("Ethereum Transactions", table)
 	return nil
 }
 
@@ -13,7 +13,7 @@ type ExternalInitiatorPresenter struct {
 func (eip *ExternalInitiatorPresenter) RenderTable(rt RendererTable) error {
 	table := rt.newTable([]string{"ID", "Name", "URL", "AccessKey", "OutgoingToken", "CreatedAt", "UpdatedAt"})
 	table.Append(eip.ToRow())
-	render("External Initiator:", table)
+	rt.render("External Initiator:", table)
 	return nil
 }
 
@@ -40,7 +40,7 @@ func (eips *ExternalInitiatorPresenters) RenderTable(rt RendererTable) error {
 	for _, eip := range *eips {
 		table.Append(eip.ToRow())
 	}
-	render("External Initiators:", table)
+	rt.render("External Initiators:", table)
 	return nil
 }
 
@@ -40,7 +40,7 @@ func (p *EVMForwarderPresenter) ToRow() []string {
 func (p *EVMForwarderPresenter) RenderTable(rt RendererTable) error {
 	var rows [][]string
 	rows = append(rows, p.ToRow())
-	renderList(evmFwdsHeaders, rows, rt.Writer)
+	rt.renderList(evmFwdsHeaders, rows)
 
 	return nil
 }
@@ -56,7 +56,7 @@ func (ps EVMForwarderPresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(evmFwdsHeaders, rows, rt.Writer)
+	rt.renderList(evmFwdsHeaders, rows)
 
 	return nil
 }
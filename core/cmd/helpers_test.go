@@ -1,8 +1,399 @@
 package cmd
 
-import "github.com/smartcontractkit/chainlink/core/logger"
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/smartcontractkit/chainlink-solana/pkg/solana/db"
+	clipkg "github.com/urfave/cli"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+)
 
 // CheckRemoteBuildCompatibility exposes checkRemoteBuildCompatibility for testing.
 func (cli *Client) CheckRemoteBuildCompatibility(lggr logger.Logger, onlyWarn bool, cliVersion, cliSha string) error {
 	return cli.checkRemoteBuildCompatibility(lggr, onlyWarn, cliVersion, cliSha)
 }
+
+// RedactConfig exposes redactConfig for testing.
+func RedactConfig(config interface{}, patterns []string) (interface{}, error) {
+	return redactConfig(config, patterns)
+}
+
+// DiffChains exposes diffChains for testing.
+func DiffChains(from, to map[string]interface{}) (ChainsDiffPresenter, error) {
+	return diffChains(from, to)
+}
+
+// ParseConfigParams exposes parseConfigParams for testing.
+func ParseConfigParams(args []string) (map[string]interface{}, error) {
+	return parseConfigParams(args)
+}
+
+// ParseTypedConfigParams exposes parseTypedConfigParams for testing.
+func ParseTypedConfigParams(args []string, cfg interface{}) (map[string]interface{}, error) {
+	return parseTypedConfigParams(args, cfg)
+}
+
+// ReadKeyValueLines exposes readKeyValueLines for testing.
+func ReadKeyValueLines(r io.Reader) ([]string, error) {
+	return readKeyValueLines(r)
+}
+
+// ValidateChainID exposes validateChainID for testing.
+func ValidateChainID(id string) (string, error) {
+	return validateChainID(id)
+}
+
+// LookupConfigPath exposes lookupConfigPath for testing.
+func LookupConfigPath(config interface{}, path string) (interface{}, error) {
+	return lookupConfigPath(config, path)
+}
+
+// NewRetryingHTTPClientWithSleep returns a retryingHTTPClient retrying rate limited requests, using sleep to wait between retries.
+func NewRetryingHTTPClientWithSleep(h HTTPClient, retries int, sleep func(time.Duration)) HTTPClient {
+	return &retryingHTTPClient{HTTPClient: h, retries: retries, on: retryOn429, sleep: sleep}
+}
+
+// DefaultRetryOn exposes defaultRetryOn for testing.
+const DefaultRetryOn = defaultRetryOn
+
+// NewRetryingHTTPClientOn returns a retryingHTTPClient retrying on the conditions of the --retry-on list on, using sleep to wait between retries.
+func NewRetryingHTTPClientOn(h HTTPClient, retries int, on string, sleep func(time.Duration)) (HTTPClient, error) {
+	conditions, err := parseRetryConditions(on)
+	if err != nil {
+		return nil, err
+	}
+	return &retryingHTTPClient{HTTPClient: h, retries: retries, on: conditions, sleep: sleep}, nil
+}
+
+// ParseRetryAfter exposes parseRetryAfter for testing.
+func ParseRetryAfter(v string, now time.Time) time.Duration {
+	return parseRetryAfter(v, now)
+}
+
+// ParseResponse exposes parseResponse for testing.
+func (cli *Client) ParseResponse(resp *http.Response) ([]byte, error) {
+	return cli.parseResponse(resp)
+}
+
+// SortSolanaChains exposes sortSolanaChains for testing.
+func SortSolanaChains(chains SolanaChainPresenters, key string) error {
+	return sortSolanaChains(chains, key)
+}
+
+// WriteSelected exposes writeSelected for testing.
+func WriteSelected(w io.Writer, resources interface{}, fields []string, headers bool) error {
+	return writeSelected(w, resources, fields, headers)
+}
+
+// ExpectChainConfig exposes expectChainConfig for testing.
+func ExpectChainConfig(w io.Writer, chainID string, config interface{}, file string, subset bool) error {
+	return expectChainConfig(w, chainID, config, file, subset)
+}
+
+// ParseProxyURL exposes parseProxyURL for testing.
+func ParseProxyURL(s string) (*url.URL, error) {
+	return parseProxyURL(s)
+}
+
+// WarnChainConfigCompatibility exposes warnChainConfigCompatibility for testing.
+func (cli *Client) WarnChainConfigCompatibility(w io.Writer, cliVersion, cliSha string) {
+	cli.warnChainConfigCompatibility(w, cliVersion, cliSha)
+}
+
+// RequireNodeVersion exposes requireNodeVersion for testing.
+func (cli *Client) RequireNodeVersion(min string) error {
+	return cli.requireNodeVersion(min)
+}
+
+// WarnDeprecated writes a new deprecation warning with msg to w count times.
+func WarnDeprecated(w io.Writer, msg string, count int) {
+	d := &deprecationWarning{msg: msg}
+	for i := 0; i < count; i++ {
+		d.warn(w)
+	}
+}
+
+// RunSolanaDoctor exposes runSolanaDoctor for testing.
+func (cli *Client) RunSolanaDoctor(cliVersion, cliSha string) DoctorPresenter {
+	var p DoctorPresenter
+	cli.runSolanaDoctor(&p, cliVersion, cliSha)
+	return p
+}
+
+// StripJSONC exposes stripJSONC for testing.
+func StripJSONC(b []byte) ([]byte, error) {
+	return stripJSONC(b)
+}
+
+type ColumnType = columnType
+
+const (
+	ColumnText    = columnText
+	ColumnNumeric = columnNumeric
+)
+
+// RenderTypedList exposes RendererTable.renderTypedList for testing, rendering to w.
+func RenderTypedList(fields []string, types []ColumnType, items [][]string, w io.Writer) {
+	RendererTable{Writer: w}.renderTypedList(fields, types, items)
+}
+
+// ConfigHash exposes configHash for testing.
+func ConfigHash(config interface{}) (string, error) {
+	return configHash(config)
+}
+
+// LintChainConfig exposes lintChainConfig for testing, formatting the issues.
+func LintChainConfig(raw []byte, cfg interface{}, deprecated map[string]string) []string {
+	var issues []string
+	for _, i := range lintChainConfig(raw, cfg, deprecated) {
+		issues = append(issues, i.String())
+	}
+	return issues
+}
+
+// MaxChainConcurrency exposes maxChainConcurrency for testing.
+const MaxChainConcurrency = maxChainConcurrency
+
+// ForEachConcurrently exposes forEachConcurrently for testing.
+func ForEachConcurrently(concurrency, count int, fn func(i int) error) []error {
+	return forEachConcurrently(concurrency, count, fn)
+}
+
+// MergeChainConfigFiles exposes mergeChainConfigFiles for testing.
+func MergeChainConfigFiles(verbose io.Writer, paths []string, jsonc bool) (json.RawMessage, error) {
+	return mergeChainConfigFiles(verbose, paths, jsonc)
+}
+
+// ExecuteTemplateFile exposes executeTemplateFile for testing.
+func ExecuteTemplateFile(w io.Writer, path string, resources interface{}, once bool) error {
+	return executeTemplateFile(w, path, resources, once)
+}
+
+// WriteRaw exposes writeRaw for testing.
+func (cli *Client) WriteRaw(w io.Writer, requestURI string, page int) error {
+	return cli.writeRaw(w, requestURI, page)
+}
+
+// NormalizeSolanaChainConfig exposes normalizeSolanaChainConfig for testing.
+func NormalizeSolanaChainConfig(w io.Writer, config db.ChainCfg, fill bool) db.ChainCfg {
+	return normalizeSolanaChainConfig(w, config, fill)
+}
+
+// ReportChainDeletes exposes reportChainDeletes for testing.
+func ReportChainDeletes(w, stderr io.Writer, jsonOutput bool, chainIDs []string, errs []error) error {
+	return reportChainDeletes(w, stderr, jsonOutput, chainIDs, errs)
+}
+
+// ErrChainAbsent exposes errChainAbsent for testing.
+var ErrChainAbsent = errChainAbsent
+
+// WaitSolanaChainWith exposes waitSolanaChain for testing.
+func (cli *Client) WaitSolanaChainWith(w io.Writer, chainID string, timeout, interval time.Duration) error {
+	return cli.waitSolanaChain(w, chainID, timeout, interval)
+}
+
+// DeprecatedKeyWarnings exposes deprecatedKeyWarnings for testing.
+func DeprecatedKeyWarnings(deprecated map[string]string, config interface{}) ([]string, error) {
+	return deprecatedKeyWarnings(deprecated, config)
+}
+
+// WriteFieldsJSON exposes writeFieldsJSON for testing.
+func WriteFieldsJSON(w io.Writer, config interface{}) error {
+	return writeFieldsJSON(w, config)
+}
+
+// NewTimestampFormatter returns a formatter of server timestamps, as
+// formatTimestamp, against the clock now which warns to w.
+func NewTimestampFormatter(now func() time.Time, w io.Writer) func(time.Time) string {
+	return (&timestampFormatter{now: now, warn: w}).format
+}
+
+// MatchChainIDs exposes matchChainIDs for testing.
+func MatchChainIDs(pattern string, ids []string) ([]string, error) {
+	return matchChainIDs(pattern, ids)
+}
+
+// WriteProjected exposes writeProjected for testing, parsing the --jq
+// expression expr.
+func WriteProjected(w io.Writer, resources interface{}, expr string) error {
+	p, err := parseProjection(expr)
+	if err != nil {
+		return err
+	}
+	return writeProjected(w, resources, p)
+}
+
+// ConfirmConfigChanges exposes confirmConfigChanges for testing.
+func ConfirmConfigChanges(prompter Prompter, from, to interface{}) (interface{}, int, error) {
+	return confirmConfigChanges(prompter, from, to)
+}
+
+// GuessChainFamily exposes guessChainFamily for testing.
+func GuessChainFamily(family string, raw []byte) string {
+	return guessChainFamily(family, raw)
+}
+
+// WriteConfigEnv exposes writeConfigEnv for testing, with the default secret
+// patterns.
+func WriteConfigEnv(w io.Writer, config interface{}, showSecrets bool) error {
+	return writeConfigEnv(w, config, "", showSecrets, defaultSecretPatterns)
+}
+
+// Confirm exposes confirm for testing.
+func Confirm(prompter Prompter, prompt string, def bool) error {
+	return confirm(prompter, prompt, def)
+}
+
+// ErrNoTerminal exposes errNoTerminal for testing.
+var ErrNoTerminal = errNoTerminal
+
+// ConfirmBulkConfigure exposes confirmBulkConfigure for testing.
+func ConfirmBulkConfigure(prompter Prompter, w io.Writer, changes, chainIDs []string) error {
+	return confirmBulkConfigure(prompter, w, changes, chainIDs)
+}
+
+// ErrAborted exposes errAborted for testing.
+var ErrAborted = errAborted
+
+// CollectWarnings returns a collector of warnings, as with --output=json,
+// the renderer attaching them to what r renders, and the function writing the
+// unattached ones to stderr.
+func CollectWarnings(r Renderer, stderr io.Writer) (io.Writer, Renderer, func()) {
+	w := &commandWarnings{stderr: stderr, collect: true}
+	return w, w.renderer(r), w.flush
+}
+
+// Ping exposes pingNode for testing.
+func (cli *Client) Ping(w io.Writer, count int) error {
+	return cli.pingNode(w, count, 0)
+}
+
+// ReadChainIDsJSON exposes readChainIDsJSON for testing.
+func ReadChainIDsJSON(r io.Reader) ([]string, error) {
+	return readChainIDsJSON(r)
+}
+
+// CheckDuplicateKeys exposes checkDuplicateKeys for testing.
+func CheckDuplicateKeys(raw []byte) error {
+	return checkDuplicateKeys(raw)
+}
+
+// RenderPageWithCursors exposes renderPageWithCursors for testing, applying
+// the --after or --before cursor of c to requestURI.
+func (cli *Client) RenderPageWithCursors(c *clipkg.Context, w io.Writer, requestURI string, model interface{}) error {
+	uri, err := withPageCursor(c, requestURI)
+	if err != nil {
+		return err
+	}
+	return cli.renderPageWithCursors(c, w, uri, model)
+}
+
+// WriteConfigTree exposes writeConfigTree for testing.
+func WriteConfigTree(w io.Writer, config interface{}) error {
+	return writeConfigTree(w, config, "")
+}
+
+// ExplainConfigField exposes explainConfigField for testing.
+func ExplainConfigField(w io.Writer, cfg, defaults interface{}, docs map[string]string, path string) error {
+	return explainConfigField(w, cfg, defaults, docs, path)
+}
+
+// DiffSolanaChainBaselines exposes diffSolanaChainBaselines for testing.
+func DiffSolanaChainBaselines(cli *Client, w io.Writer, chainIDs []string, baseline string) error {
+	return cli.diffSolanaChainBaselines(w, chainIDs, baseline, false, false)
+}
+
+// ExplainSolanaChainBaselines exposes diffSolanaChainBaselines with
+// --explain-diff for testing.
+func ExplainSolanaChainBaselines(cli *Client, w io.Writer, chainIDs []string, baseline string) error {
+	return cli.diffSolanaChainBaselines(w, chainIDs, baseline, false, true)
+}
+
+// GetBufferFromJSON exposes getBufferFromJSON for testing.
+func GetBufferFromJSON(s string) (*bytes.Buffer, error) {
+	return getBufferFromJSON(s)
+}
+
+// EncodeChainBody exposes encodeChainBody for testing, in the given format.
+func EncodeChainBody(format, resourceType string, attributes map[string]interface{}) ([]byte, error) {
+	return (&Client{ChainBodyFormat: format}).encodeChainBody(resourceType, attributes)
+}
+
+// TrimChainConfig exposes trimChainConfig for testing.
+func TrimChainConfig(raw json.RawMessage) (json.RawMessage, int, error) {
+	return trimChainConfig(raw)
+}
+
+// WarnSecretConfigKeys exposes warnSecretConfigKeys for testing, with the
+// default secret patterns.
+func WarnSecretConfigKeys(w io.Writer, config interface{}) {
+	warnSecretConfigKeys(w, config, defaultSecretPatterns)
+}
+
+// ChainDisplayName exposes chainDisplayName for testing.
+func ChainDisplayName(chainID string, config interface{}) string {
+	return chainDisplayName(chainID, config)
+}
+
+// ChainAction exposes chainAction for testing.
+func (cli *Client) ChainAction(action func(*clipkg.Context) error) func(*clipkg.Context) error {
+	return cli.chainAction(action)
+}
+
+// UnsetConfigPaths exposes unsetConfigPaths for testing.
+func UnsetConfigPaths(config interface{}, paths []string, ignoreMissing bool) (map[string]interface{}, error) {
+	return unsetConfigPaths(config, paths, ignoreMissing)
+}
+
+// NewCachingHTTPClient exposes newCachingHTTPClient for testing.
+func NewCachingHTTPClient(h HTTPClient, maxBytes int) HTTPClient {
+	return newCachingHTTPClient(h, maxBytes)
+}
+
+// CachedGETCommand reports whether chainAction caches the GETs of the
+// command with name.
+func CachedGETCommand(name string) bool {
+	return cachedGETCommands[name]
+}
+
+// WriteConfigHCL exposes writeConfigHCL for testing.
+func WriteConfigHCL(w io.Writer, blockType, id string, enabled bool, config interface{}) error {
+	return writeConfigHCL(w, blockType, id, enabled, config)
+}
+
+// ApplySolanaChainPatchFrom exposes applySolanaChainPatch for testing, reading
+// the patch from stdin.
+func ApplySolanaChainPatchFrom(cli *Client, c *clipkg.Context, stdin io.Reader) error {
+	return cli.applySolanaChainPatch(c, stdin)
+}
+
+// ErrorOut exposes errorOut for testing.
+func (cli *Client) ErrorOut(err error) error {
+	return cli.errorOut(err)
+}
+
+// WithExitCode exposes withExitCode for testing.
+func WithExitCode(err error, code int) error {
+	return withExitCode(err, code)
+}
+
+// WriteConfigYAML exposes writeConfigYAML for testing.
+func WriteConfigYAML(w io.Writer, config interface{}) error {
+	return writeConfigYAML(w, config)
+}
+
+// ConfigFieldDefaults exposes configFieldDefaults for testing.
+func ConfigFieldDefaults(keys []string, cfg, defaults interface{}) (map[string]interface{}, error) {
+	return configFieldDefaults(keys, cfg, defaults)
+}
+
+// RoundTripIssues exposes roundTripIssues for testing.
+func RoundTripIssues(raw json.RawMessage, cfg interface{}) ([]RoundTripIssue, error) {
+	return roundTripIssues(raw, cfg)
+}
@@ -133,7 +133,7 @@ func (p *JobPresenter) RenderTable(rt RendererTable) error {
 		table.Append(r)
 	}
 
-	render("Jobs", table)
+	rt.render("Jobs", table)
 	return nil
 }
 
@@ -149,7 +149,7 @@ func (ps JobPresenters) RenderTable(rt RendererTable) error {
 		}
 	}
 
-	render("Jobs (V2)", table)
+	rt.render("Jobs (V2)", table)
 	return nil
 }
 
@@ -436,7 +436,7 @@ func (ps HealthCheckPresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return nil
 }
@@ -29,7 +29,7 @@ func (p *OCR2KeyBundlePresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 OCR Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -58,7 +58,7 @@ func (ps OCR2KeyBundlePresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 OCR Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
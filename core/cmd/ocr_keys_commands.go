@@ -28,7 +28,7 @@ func (p *OCRKeyBundlePresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Legacy OCR Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -56,7 +56,7 @@ func (ps OCRKeyBundlePresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Legacy OCR Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -27,7 +27,7 @@ func (p *P2PKeyPresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 P2P Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -56,7 +56,7 @@ func (ps P2PKeyPresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 P2P Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
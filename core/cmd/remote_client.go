@@ -11,10 +11,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/mitchellh/go-homedir"
@@ -32,11 +34,68 @@ import (
 	"github.com/smartcontractkit/chainlink/core/store/models"
 	"github.com/smartcontractkit/chainlink/core/utils"
 	"github.com/smartcontractkit/chainlink/core/web"
+	webauth "github.com/smartcontractkit/chainlink/core/web/auth"
 	webpresenters "github.com/smartcontractkit/chainlink/core/web/presenters"
 )
 
 var errUnauthorized = errors.New(http.StatusText(http.StatusUnauthorized))
 
+// errRateLimited is returned when the node responds with 429 Too Many Requests.
+type errRateLimited struct {
+	retryAfter time.Duration
+}
+
+func (e errRateLimited) Error() string {
+	if e.retryAfter <= 0 {
+		return "rate limited; retry later"
+	}
+	return fmt.Sprintf("rate limited; retry after %d seconds", int(e.retryAfter.Round(time.Second).Seconds()))
+}
+
+// errRetriesExhausted is returned when a request still fails after all
+// retries, to tell a persistent failure from a blip. status is zero if the
+// request got no response.
+type errRetriesExhausted struct {
+	retries int
+	elapsed time.Duration
+	status  int
+	err     error
+}
+
+func (e errRetriesExhausted) Error() string {
+	plural := "retries"
+	if e.retries == 1 {
+		plural = "retry"
+	}
+	if e.status == 0 {
+		return fmt.Sprintf("failed after %d %s over %s; last error: %v", e.retries, plural, e.elapsed.Round(100*time.Millisecond), e.err)
+	}
+	return fmt.Sprintf("failed after %d %s over %s; last error: HTTP %d: %v", e.retries, plural, e.elapsed.Round(100*time.Millisecond), e.status, e.err)
+}
+
+func (e errRetriesExhausted) Unwrap() error {
+	return e.err
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date. It returns zero if the value is missing
+// or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
 // CreateExternalInitiator adds an external initiator
 func (cli *Client) CreateExternalInitiator(c *clipkg.Context) (err error) {
 	if c.NArg() != 1 && c.NArg() != 2 {
@@ -97,9 +156,19 @@ func (cli *Client) DeleteExternalInitiator(c *clipkg.Context) (err error) {
 }
 
 func (cli *Client) getPage(requestURI string, page int, model interface{}) (err error) {
+	if _, err = cli.fetchPage(requestURI, page, model); err != nil {
+		return err
+	}
+	err = cli.errorOut(cli.Render(model))
+	return err
+}
+
+// fetchPage deserializes the given page of requestURI into model, returning
+// the pagination links of the response.
+func (cli *Client) fetchPage(requestURI string, page int, model interface{}) (links jsonapi.Links, err error) {
 	uri, err := url.Parse(requestURI)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	q := uri.Query()
 	if page > 0 {
@@ -109,7 +178,7 @@ func (cli *Client) getPage(requestURI string, page int, model interface{}) (err
 
 	resp, err := cli.HTTP.Get(uri.String())
 	if err != nil {
-		return cli.errorOut(err)
+		return nil, cli.errorOut(err)
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -117,12 +186,58 @@ func (cli *Client) getPage(requestURI string, page int, model interface{}) (err
 		}
 	}()
 
-	err = cli.deserializeAPIResponse(resp, model, &jsonapi.Links{})
+	err = cli.deserializeAPIResponse(resp, model, &links)
+	return links, err
+}
+
+// getAllPages follows the next links of the paginated requestURI, appending
+// the resources of every page to dst, which must be a pointer to a slice.
+func (cli *Client) getAllPages(h HTTPClient, requestURI string, dst interface{}) error {
+	dstV := reflect.ValueOf(dst).Elem()
+	for requestURI != "" {
+		page := reflect.New(dstV.Type())
+		links, err := cli.getResource(h, requestURI, page.Interface())
+		if err != nil {
+			return err
+		}
+		dstV.Set(reflect.AppendSlice(dstV, page.Elem()))
+		requestURI = links[web.KeyNextLink].Href
+	}
+	return nil
+}
+
+// forEachPage fetches each page of requestURI with h into a new value from
+// newPage, and passes it to fn before fetching the next one, so that memory
+// stays flat regardless of the number of items.
+func (cli *Client) forEachPage(h HTTPClient, requestURI string, newPage func() interface{}, fn func(page interface{}) error) error {
+	for requestURI != "" {
+		page := newPage()
+		links, err := cli.getResource(h, requestURI, page)
+		if err != nil {
+			return err
+		}
+		if err = fn(page); err != nil {
+			return err
+		}
+		requestURI = links[web.KeyNextLink].Href
+	}
+	return nil
+}
+
+// getResource fetches requestURI with h and deserializes the JSON API
+// response into dst, returning the document links.
+func (cli *Client) getResource(h HTTPClient, requestURI string, dst interface{}) (links jsonapi.Links, err error) {
+	resp, err := h.Get(requestURI)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = cli.errorOut(cli.Render(model))
-	return err
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	err = cli.deserializeAPIResponse(resp, dst, &links)
+	return links, err
 }
 
 // ReplayFromBlock replays chain data from the given block number until the most recent
@@ -307,19 +422,120 @@ func getTOMLString(s string) (string, error) {
 	return buf.String(), nil
 }
 
+// parseResponse returns the body of resp, or an error for failed requests
+// which is prefixed with the method, path and status code of the request.
 func (cli *Client) parseResponse(resp *http.Response) ([]byte, error) {
+	b, err := cli.checkResponse(resp)
+	if err != nil {
+		return nil, cli.errorOut(responseError(resp, err))
+	}
+	return b, nil
+}
+
+// checkResponse returns the body of resp, or an error for failed requests
+// including the JSON API errors of the body.
+func (cli *Client) checkResponse(resp *http.Response) ([]byte, error) {
+	b, err := cli.parseCheckedResponse(resp)
+	return b, withExitCode(err, statusExitCode(resp.StatusCode))
+}
+
+// statusExitCode returns the exit code for a request failing with status.
+func statusExitCode(status int) int {
+	switch status {
+	case http.StatusNotFound:
+		return ExitCodeNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitCodeAuth
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ExitCodeValidation
+	default:
+		return ExitCodeError
+	}
+}
+
+func (cli *Client) parseCheckedResponse(resp *http.Response) ([]byte, error) {
 	b, err := parseResponse(resp)
+	if errors.Is(err, errUnauthorized) && resp.Request != nil && resp.Request.Header.Get(webauth.APIKey) != "" {
+		return nil, multierr.Append(err, fmt.Errorf("the node rejected the token of --auth-token or %s", AuthTokenEnv))
+	}
 	if errors.Is(err, errUnauthorized) {
-		return nil, cli.errorOut(multierr.Append(err, fmt.Errorf("your credentials may be missing, invalid or you may need to login first using the CLI via 'chainlink admin login'")))
+		return nil, multierr.Append(err, fmt.Errorf("your credentials may be missing, invalid or you may need to login first using the CLI via 'chainlink admin login', or pass --auth-token"))
+	}
+	var rateLimited errRateLimited
+	if errors.As(err, &rateLimited) {
+		return nil, err
 	}
 	if err != nil {
 		jae := models.JSONAPIErrors{}
 		unmarshalErr := json.Unmarshal(b, &jae)
-		return nil, cli.errorOut(multierr.Combine(err, unmarshalErr, &jae))
+		return nil, multierr.Combine(err, unmarshalErr, &jae)
 	}
+	cli.writeServerWarnings(resp, b)
 	return b, err
 }
 
+// writeServerWarnings writes the warnings the node returned with resp, such
+// as a config field being deprecated server-side, to cli.ServerWarnings, or
+// stderr. They are taken from the Warning headers, and from the warnings
+// arrays of the JSON API document body b, at its top level or under meta.
+func (cli *Client) writeServerWarnings(resp *http.Response, b []byte) {
+	var msgs []string
+	for _, h := range resp.Header.Values("Warning") {
+		msgs = append(msgs, warningHeaderText(h))
+	}
+	if gjson.ValidBytes(b) {
+		for _, path := range []string{"warnings", "meta.warnings"} {
+			for _, w := range gjson.GetBytes(b, path).Array() {
+				if w.IsObject() {
+					// Warnings may be shaped like JSON API errors
+					w = w.Get("detail")
+				}
+				if msg := w.String(); msg != "" {
+					msgs = append(msgs, msg)
+				}
+			}
+		}
+	}
+	w := cli.ServerWarnings
+	if w == nil {
+		w = os.Stderr
+	}
+	for _, msg := range msgs {
+		fmt.Fprintln(w, "WARNING: "+msg)
+	}
+}
+
+// warningHeaderText returns the text of the Warning header value h, which is
+// formatted as `code agent "text" [date]`, e.g. `299 - "Deprecated"`. Values
+// not in this format are returned as they are.
+func warningHeaderText(h string) string {
+	parts := strings.SplitN(h, " ", 3)
+	if len(parts) < 3 || len(parts[0]) != 3 {
+		return h
+	}
+	quoted := parts[2]
+	if end := strings.LastIndex(quoted, `" "`); end > 0 {
+		// Drop the date
+		quoted = quoted[:end+1]
+	}
+	text, err := strconv.Unquote(quoted)
+	if err != nil {
+		return h
+	}
+	return text
+}
+
+// responseError prefixes err with the method, path and status code of the
+// request of resp, e.g. "PATCH /v2/chains/solana/foo -> 422: ", so that
+// failures read the same whichever helper handled the response. Responses
+// without a request are not prefixed.
+func responseError(resp *http.Response, err error) error {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return err
+	}
+	return errors.Wrapf(err, "%s %s -> %d", resp.Request.Method, resp.Request.URL.RequestURI(), resp.StatusCode)
+}
+
 func (cli *Client) printResponseBody(resp *http.Response) error {
 	b, err := parseResponse(resp)
 	if err != nil {
@@ -331,6 +547,10 @@ func (cli *Client) printResponseBody(resp *http.Response) error {
 }
 
 func (cli *Client) renderAPIResponse(resp *http.Response, dst interface{}, headers ...string) error {
+	if resp.StatusCode == http.StatusNoContent {
+		// Nothing to render, and the empty body is not a JSON API document
+		return nil
+	}
 	var links jsonapi.Links
 	if err := cli.deserializeAPIResponse(resp, dst, &links); err != nil {
 		return cli.errorOut(err)
@@ -510,6 +730,9 @@ func (cli *Client) SetLogPkg(c *clipkg.Context) (err error) {
 
 func getBufferFromJSON(s string) (*bytes.Buffer, error) {
 	if gjson.Valid(s) {
+		if err := checkUTF8([]byte(s)); err != nil {
+			return nil, errors.Wrap(err, "invalid JSON argument")
+		}
 		return bytes.NewBufferString(s), nil
 	}
 
@@ -519,7 +742,34 @@ func getBufferFromJSON(s string) (*bytes.Buffer, error) {
 	} else if err != nil {
 		return nil, fmt.Errorf("error reading from file '%s': %v", s, err)
 	}
-	return buf, nil
+	if err = checkUTF8(buf.Bytes()); err != nil {
+		return nil, errors.Wrapf(err, "file '%s' is not text, was a binary file passed by mistake?", s)
+	}
+	return bytes.NewBuffer(normalizeText(buf.Bytes())), nil
+}
+
+// utf8BOM is the byte order mark some editors, mostly on Windows, write at the
+// start of UTF-8 files.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// normalizeText strips a leading UTF-8 BOM from b, which json.Unmarshal
+// rejects as an invalid character, and converts CRLF line endings to LF, so
+// that files edited on any OS parse the same.
+func normalizeText(b []byte) []byte {
+	return bytes.ReplaceAll(bytes.TrimPrefix(b, utf8BOM), []byte("\r\n"), []byte("\n"))
+}
+
+// checkUTF8 fails if b is not valid UTF-8, giving the byte offset of the first
+// invalid sequence.
+func checkUTF8(b []byte) error {
+	for offset := 0; offset < len(b); {
+		r, size := utf8.DecodeRune(b[offset:])
+		if r == utf8.RuneError && size <= 1 {
+			return errors.Errorf("invalid UTF-8 at byte offset %d", offset)
+		}
+		offset += size
+	}
+	return nil
 }
 
 func fromFile(arg string) (*bytes.Buffer, error) {
@@ -536,9 +786,15 @@ func fromFile(arg string) (*bytes.Buffer, error) {
 
 // deserializeAPIResponse is distinct from deserializeResponse in that it supports JSONAPI responses with Links
 func (cli *Client) deserializeAPIResponse(resp *http.Response, dst interface{}, links *jsonapi.Links) error {
-	b, err := cli.parseResponse(resp)
+	b, err := cli.checkResponse(resp)
 	if err != nil {
-		return errors.Wrap(err, "parseResponse error")
+		return cli.errorOut(responseError(resp, errors.Wrap(err, "parseResponse error")))
+	}
+	if b, err = singleResourceDocument(b, dst); err != nil {
+		return cli.errorOut(responseError(resp, err))
+	}
+	if err = checkResourceAttributes(b, dst); err != nil {
+		return cli.errorOut(responseError(resp, err))
 	}
 	if err = web.ParsePaginatedResponse(b, dst, links); err != nil {
 		return cli.errorOut(err)
@@ -546,6 +802,64 @@ func (cli *Client) deserializeAPIResponse(resp *http.Response, dst interface{},
 	return nil
 }
 
+// singleResourceDocument returns the JSON API document b with a data array
+// of a single resource replaced by the resource, for destinations dst which
+// are not slices, as some servers and proxies wrap single resources in an
+// array. Arrays of several resources are an error, rather than picking one.
+func singleResourceDocument(b []byte, dst interface{}) ([]byte, error) {
+	if t := reflect.TypeOf(dst); t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() == reflect.Slice {
+		return b, nil
+	}
+	data := gjson.GetBytes(b, "data")
+	if !data.IsArray() {
+		return b, nil
+	}
+	elems := data.Array()
+	if len(elems) != 1 {
+		return nil, errors.Errorf("expected a single resource, but the response holds %d", len(elems))
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	doc["data"] = json.RawMessage(elems[0].Raw)
+	return json.Marshal(doc)
+}
+
+// sparseResource is implemented by the resources requested with sparse
+// fieldsets, of which the node may omit the attributes.
+type sparseResource interface {
+	sparseFieldset()
+}
+
+// checkResourceAttributes returns an error if a resource of the JSON API
+// document b has no attributes, which would otherwise be deserialized into a
+// zero-valued resource of dst, and render as if the node returned one,
+// masking bugs of the node or of proxies in between.
+func checkResourceAttributes(b []byte, dst interface{}) error {
+	t := reflect.TypeOf(dst)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t != nil && reflect.PtrTo(t).Implements(reflect.TypeOf((*sparseResource)(nil)).Elem()) {
+		return nil
+	}
+	data := gjson.GetBytes(b, "data")
+	resources := []gjson.Result{data}
+	if data.IsArray() {
+		resources = data.Array()
+	}
+	for _, resource := range resources {
+		if !resource.IsObject() {
+			continue
+		}
+		if !resource.Get("attributes").IsObject() {
+			return errors.New("malformed server response: resource has no attributes")
+		}
+	}
+	return nil
+}
+
 func parseResponse(resp *http.Response) ([]byte, error) {
 	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -553,8 +867,13 @@ func parseResponse(resp *http.Response) ([]byte, error) {
 	}
 	if resp.StatusCode == http.StatusUnauthorized {
 		return b, errUnauthorized
+	} else if resp.StatusCode == http.StatusTooManyRequests {
+		err = errRateLimited{retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
 	} else if resp.StatusCode >= http.StatusBadRequest {
-		return b, errors.New("Error")
+		err = errors.New("Error")
+	}
+	if retried, ok := resp.Body.(retriedBody); ok && err != nil {
+		err = errRetriesExhausted{retries: retried.retries, elapsed: retried.elapsed, status: resp.StatusCode, err: err}
 	}
 	return b, err
 }
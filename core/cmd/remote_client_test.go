@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -755,3 +757,39 @@ func TestClient_SetPkgLogLevel(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, logLevel, level)
 }
+
+func TestGetBufferFromJSON_InvalidUTF8(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("{\"cert\": \"ab\xff\xfecd\"}"), 0600))
+	_, err := cmd.GetBufferFromJSON(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("file '%s' is not text", path))
+	assert.Contains(t, err.Error(), "invalid UTF-8 at byte offset 12")
+
+	valid := filepath.Join(t.TempDir(), "valid.json")
+	require.NoError(t, ioutil.WriteFile(valid, []byte(`{"name": "café"}`), 0600))
+	buf, err := cmd.GetBufferFromJSON(valid)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "café"}`, buf.String())
+}
+
+func TestGetBufferFromJSON_BOMAndCRLF(t *testing.T) {
+	t.Parallel()
+
+	for name, content := range map[string]string{
+		"bom":      "\xef\xbb\xbf{\"name\": \"bridge\"}",
+		"crlf":     "{\r\n  \"name\": \"bridge\"\r\n}\r\n",
+		"bom+crlf": "\xef\xbb\xbf{\r\n  \"name\": \"bridge\"\r\n}\r\n",
+	} {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		buf, err := cmd.GetBufferFromJSON(path)
+		require.NoError(t, err, name)
+		assert.NotContains(t, buf.String(), "\r", name)
+		var parsed map[string]string
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed), name)
+		assert.Equal(t, "bridge", parsed["name"], name)
+	}
+}
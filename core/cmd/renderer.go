@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 
@@ -23,11 +27,20 @@ type Renderer interface {
 // RendererJSON is used to render JSON data.
 type RendererJSON struct {
 	io.Writer
+	// Compact writes JSON on a single line, without indentation, for line
+	// based log shippers.
+	Compact bool
 }
 
 // Render writes the given input as a JSON string.
 func (rj RendererJSON) Render(v interface{}, _ ...string) error {
-	b, err := utils.FormatJSON(v)
+	var b []byte
+	var err error
+	if rj.Compact {
+		b, err = json.Marshal(v)
+	} else {
+		b, err = utils.FormatJSON(v)
+	}
 	if err != nil {
 		return err
 	}
@@ -43,8 +56,44 @@ func (rj RendererJSON) Render(v interface{}, _ ...string) error {
 // RendererTable is used for data to be rendered as a table.
 type RendererTable struct {
 	io.Writer
+	// ShowSecrets disables the redaction of sensitive chain config fields.
+	ShowSecrets bool
+	// SecretPatterns overrides the key patterns identifying sensitive chain
+	// config fields. Defaults to defaultSecretPatterns when nil.
+	SecretPatterns []string
+	// NoHeader omits the header row of tables, and the field labels of
+	// lists, leaving only the values.
+	NoHeader bool
+	// SortConfigKeys sorts the keys of chain configs recursively. Redacted
+	// configs are always sorted.
+	SortConfigKeys bool
+	// ConfigFormat is how chain configs are formatted, one of
+	// chainConfigFormats. Defaults to pretty when empty.
+	ConfigFormat string
+	// Markdown renders tables and lists as GitHub-flavored Markdown tables,
+	// for pasting into documents. Pretty chain configs are minified, so that
+	// they fit on a table row.
+	Markdown bool
+	// Style is the style of the borders of tables and lists, one of
+	// tableStyles. Defaults to default when empty.
+	Style string
 }
 
+// The styles of RendererTable, set with the global --table-style flag.
+const (
+	// tableStyleDefault draws tables with double lines, and dashed lines
+	// between list items.
+	tableStyleDefault = "default"
+	// tableStyleBorderless draws no lines: table columns are separated by
+	// tabs and list items by blank lines, for piping into other tools.
+	tableStyleBorderless = "borderless"
+	// tableStyleHeavy draws tables and lists with heavy lines.
+	tableStyleHeavy = "heavy"
+)
+
+// tableStyles are the valid values of RendererTable.Style.
+var tableStyles = []string{tableStyleDefault, tableStyleBorderless, tableStyleHeavy}
+
 type TableRenderer interface {
 	RenderTable(rt RendererTable) error
 }
@@ -53,7 +102,7 @@ type TableRenderer interface {
 // and relevant information.
 func (rt RendererTable) Render(v interface{}, headers ...string) error {
 	for _, h := range headers {
-		fmt.Println(h)
+		fmt.Fprintln(rt.Writer, h)
 	}
 
 	switch typed := v.(type) {
@@ -86,7 +135,7 @@ func (rt RendererTable) renderLogPkgConfig(serviceLevelLog webpresenters.Service
 		})
 	}
 
-	render("ServiceLogConfig", table)
+	rt.render("ServiceLogConfig", table)
 	return nil
 }
 
@@ -101,7 +150,7 @@ func (rt RendererTable) renderVRFKeys(keys []VRFKeyPresenter) error {
 		})
 	}
 
-	renderList([]string{"Compressed", "Uncompressed", "Hash"}, rows, rt.Writer)
+	rt.renderList([]string{"Compressed", "Uncompressed", "Hash"}, rows)
 
 	return nil
 }
@@ -139,27 +188,160 @@ func (rt RendererTable) renderConfiguration(cp config.ConfigPrinter) error {
 		}
 	}
 
-	render("Configuration", table)
+	rt.render("Configuration", table)
 	return nil
 }
 
-func render(name string, table *tablewriter.Table) {
-	table.SetRowLine(true)
-	table.SetColumnSeparator("║")
-	table.SetRowSeparator("═")
-	table.SetCenterSeparator("╬")
-
-	fmt.Println("╔ " + name)
+// render renders table under the heading name to rt.Writer, styled as a
+// Markdown table if rt.Markdown is set, or else in rt.Style.
+func (rt RendererTable) render(name string, table *tablewriter.Table) {
+	if rt.Markdown {
+		fmt.Fprintln(rt.Writer, "**"+name+"**")
+		fmt.Fprintln(rt.Writer)
+		table.Render()
+		return
+	}
+	switch rt.Style {
+	case tableStyleBorderless:
+		table.SetBorder(false)
+		table.SetHeaderLine(false)
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetNoWhiteSpace(true)
+		table.SetTablePadding("\t")
+		fmt.Fprintln(rt.Writer, name)
+	case tableStyleHeavy:
+		table.SetRowLine(true)
+		table.SetColumnSeparator("┃")
+		table.SetRowSeparator("━")
+		table.SetCenterSeparator("╋")
+		fmt.Fprintln(rt.Writer, "┏ "+name)
+	default:
+		table.SetRowLine(true)
+		table.SetColumnSeparator("║")
+		table.SetRowSeparator("═")
+		table.SetCenterSeparator("╬")
+		fmt.Fprintln(rt.Writer, "╔ "+name)
+	}
 	table.Render()
 }
 
-func renderList(fields []string, items [][]string, writer io.Writer) {
+// clockSkewTolerance is how far in the future of the client's clock a server
+// timestamp may be before it is attributed to clock skew.
+const clockSkewTolerance = time.Second
+
+// timestampFormatter formats server timestamps, flagging those in the future
+// of the client's clock, which can only be due to a misconfigured clock on
+// either side. The first such timestamp also prints a warning.
+type timestampFormatter struct {
+	now  func() time.Time
+	warn io.Writer
+	once sync.Once
+}
+
+// serverTimestamps formats the timestamps of the rendered resources, so that
+// clock skew is warned about once per invocation.
+var serverTimestamps = &timestampFormatter{now: time.Now, warn: os.Stderr}
+
+// formatTimestamp formats a timestamp reported by the node.
+func formatTimestamp(t time.Time) string {
+	return serverTimestamps.format(t)
+}
+
+func (f *timestampFormatter) format(t time.Time) string {
+	ahead := t.Sub(f.now())
+	if ahead <= clockSkewTolerance {
+		return t.String()
+	}
+	ahead = ahead.Round(time.Second)
+	f.once.Do(func() {
+		fmt.Fprintf(f.warn, "WARNING: the node reported a timestamp %s in the future; check the clocks of the node and of this machine\n", ahead)
+	})
+	return fmt.Sprintf("%s (in %s, clock skew?)", t, ahead)
+}
+
+// columnType is a hint for how renderTypedList aligns the values of a field.
+type columnType int
+
+const (
+	// columnText values are left-aligned.
+	columnText columnType = iota
+	// columnNumeric values, such as numbers and booleans, are right-justified
+	// to the widest value of the field across the items.
+	columnNumeric
+)
+
+// renderList renders a list of items to rt, in its style, like
+// renderTypedList with every field text.
+func (rt RendererTable) renderList(fields []string, items [][]string) {
+	rt.renderTypedList(fields, nil, items)
+}
+
+// renderTypedList renders a list of items to rt, aligning the values of each
+// field according to its columnType in types, with the field labels unless
+// rt.NoHeader is set. Fields without a type are text.
+func (rt RendererTable) renderTypedList(fields []string, types []columnType, items [][]string) {
+	if rt.Markdown {
+		writeMarkdownTable(fields, types, items, rt.Writer)
+		return
+	}
+	writeTypedList(fields, types, items, rt.Writer, !rt.NoHeader, rt.Style)
+}
+
+// markdownEscaper escapes the values of Markdown table cells, which must not
+// contain unescaped pipes or line breaks.
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+// writeMarkdownTable writes items as a GitHub-flavored Markdown table with a
+// column per field. Columns of columnNumeric fields are right-aligned.
+func writeMarkdownTable(fields []string, types []columnType, items [][]string, writer io.Writer) {
+	var b strings.Builder
+	separators := make([]string, len(fields))
+	for i := range fields {
+		separators[i] = "---"
+		if i < len(types) && types[i] == columnNumeric {
+			separators[i] = "---:"
+		}
+	}
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + markdownEscaper.Replace(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(fields)
+	b.WriteString("|" + strings.Join(separators, "|") + "|\n")
+	for _, row := range items {
+		writeRow(row)
+	}
+	if _, err := writer.Write([]byte(b.String())); err != nil {
+		// Handles errcheck
+		return
+	}
+}
+
+// writeTypedList writes items to writer as a list of their fields, one per
+// line, in the style of the borders between items, one of tableStyles.
+func writeTypedList(fields []string, types []columnType, items [][]string, writer io.Writer, labels bool, style string) {
 	var maxLabelLength int
 	for _, field := range fields {
 		if len(field) > maxLabelLength {
 			maxLabelLength = len(field)
 		}
 	}
+	valueWidths := make([]int, len(fields))
+	for i := range fields {
+		if i >= len(types) || types[i] != columnNumeric {
+			continue
+		}
+		for _, row := range items {
+			if len(row[i]) > valueWidths[i] && !strings.Contains(row[i], "\n") {
+				valueWidths[i] = len(row[i])
+			}
+		}
+	}
 	var itemsRendered []string
 	var maxLineLength int
 	for _, row := range items {
@@ -167,7 +349,14 @@ func renderList(fields []string, items [][]string, writer io.Writer) {
 		for i, field := range fields {
 			diff := maxLabelLength - len(field)
 			spaces := strings.Repeat(" ", diff)
-			line := fmt.Sprintf("%v: %v%v", field, spaces, row[i])
+			value := row[i]
+			if pad := valueWidths[i] - len(value); pad > 0 && !strings.Contains(value, "\n") {
+				value = strings.Repeat(" ", pad) + value
+			}
+			line := value
+			if labels {
+				line = fmt.Sprintf("%v: %v%v", field, spaces, value)
+			}
 			for _, l := range strings.Split(line, "\n") {
 				if len(l) > maxLineLength {
 					maxLineLength = len(l)
@@ -177,8 +366,17 @@ func renderList(fields []string, items [][]string, writer io.Writer) {
 		}
 		itemsRendered = append(itemsRendered, strings.Join(lines, "\n"))
 	}
-	divider := strings.Repeat("-", maxLineLength)
-	listRendered := divider + "\n" + strings.Join(itemsRendered, "\n"+divider+"\n")
+	var listRendered string
+	switch style {
+	case tableStyleBorderless:
+		listRendered = strings.Join(itemsRendered, "\n\n")
+	case tableStyleHeavy:
+		divider := strings.Repeat("━", maxLineLength)
+		listRendered = divider + "\n" + strings.Join(itemsRendered, "\n"+divider+"\n")
+	default:
+		divider := strings.Repeat("-", maxLineLength)
+		listRendered = divider + "\n" + strings.Join(itemsRendered, "\n"+divider+"\n")
+	}
 	_, err := writer.Write([]byte(listRendered))
 	if err != nil {
 		// Handles errcheck
@@ -196,13 +394,24 @@ func (rt RendererTable) renderExternalInitiatorAuthentication(eia webpresenters.
 		eia.OutgoingToken,
 		eia.OutgoingSecret,
 	})
-	render("External Initiator Credentials:", table)
+	rt.render("External Initiator Credentials:", table)
 	return nil
 }
 
 func (rt RendererTable) newTable(headers []string) *tablewriter.Table {
 	table := tablewriter.NewWriter(rt)
-	table.SetHeader(headers)
+	if rt.Markdown {
+		// A Markdown table always has a header row
+		table.SetHeader(headers)
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		table.SetBorders(tablewriter.Border{Left: true, Right: true})
+		table.SetCenterSeparator("|")
+		return table
+	}
+	if !rt.NoHeader {
+		table.SetHeader(headers)
+	}
 	return table
 }
 
@@ -213,7 +422,7 @@ func (rt RendererTable) renderConfigPatchResponse(config *web.ConfigPatchRespons
 		config.EvmGasPriceDefault.From,
 		config.EvmGasPriceDefault.To,
 	})
-	render("Configuration Changes", table)
+	rt.render("Configuration Changes", table)
 	return nil
 }
 
@@ -232,6 +441,6 @@ func (rt RendererTable) renderPipelineRun(run webpresenters.PipelineRunResource)
 	}
 	table.Append(row)
 
-	render("Pipeline Run", table)
+	rt.render("Pipeline Run", table)
 	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io/ioutil"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/smartcontractkit/chainlink/core/cmd"
 	"github.com/smartcontractkit/chainlink/core/config"
@@ -33,6 +35,19 @@ func TestRendererJSON_RenderVRFKeys(t *testing.T) {
 	assert.NoError(t, r.Render(&keys))
 }
 
+func TestRendererJSON_Compact(t *testing.T) {
+	t.Parallel()
+
+	v := map[string]interface{}{"id": "devnet", "config": map[string]interface{}{"Commitment": "confirmed"}}
+	var b bytes.Buffer
+	require.NoError(t, cmd.RendererJSON{Writer: &b, Compact: true}.Render(v))
+	assert.Equal(t, `{"config":{"Commitment":"confirmed"},"id":"devnet"}`+"\n", b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.RendererJSON{Writer: &b}.Render(v))
+	assert.Greater(t, strings.Count(b.String(), "\n"), 1)
+}
+
 func TestRendererTable_RenderConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -120,3 +135,115 @@ func TestRendererTable_RenderUnknown(t *testing.T) {
 	anon := struct{ Name string }{"Romeo"}
 	assert.Error(t, r.Render(&anon))
 }
+
+func TestRenderTypedList(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	cmd.RenderTypedList([]string{"ID", "Enabled"}, []cmd.ColumnType{cmd.ColumnText, cmd.ColumnNumeric}, [][]string{
+		{"devnet", "true"},
+		{"testnet-long", "false"},
+	}, &b)
+	assert.Equal(t, "---------------------\n"+
+		"ID:      devnet\n"+
+		"Enabled:  true\n"+
+		"---------------------\n"+
+		"ID:      testnet-long\n"+
+		"Enabled: false", b.String())
+}
+
+func TestRendererTable_NoHeader(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	rt := cmd.RendererTable{Writer: &b, NoHeader: true}
+	two := 2
+	require.NoError(t, rt.Render(&cmd.ChainCountsPresenter{Families: []cmd.ChainFamilyCount{{Family: "solana", Count: &two}}, Total: 2}))
+	assert.NotContains(t, b.String(), "FAMILY")
+	assert.Contains(t, b.String(), "solana")
+
+	b.Reset()
+	chain := cmd.SolanaChainPresenter{}
+	chain.ID = "devnet"
+	require.NoError(t, rt.Render(&chain))
+	assert.NotContains(t, b.String(), "ID:")
+	assert.True(t, strings.HasPrefix(strings.SplitN(b.String(), "\n", 3)[1], "devnet"))
+
+	// Lists of nodes too
+	b.Reset()
+	node := cmd.EVMNodePresenter{}
+	node.Name = "primary"
+	require.NoError(t, rt.Render(&cmd.EVMNodePresenters{node}))
+	assert.NotContains(t, b.String(), "Name:")
+	assert.Contains(t, b.String(), "primary")
+}
+
+func TestTimestampFormatter_ClockSkew(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	var warnings bytes.Buffer
+	format := cmd.NewTimestampFormatter(func() time.Time { return now }, &warnings)
+
+	past := now.Add(-time.Hour)
+	assert.Equal(t, past.String(), format(past))
+	withinTolerance := now.Add(500 * time.Millisecond)
+	assert.Equal(t, withinTolerance.String(), format(withinTolerance))
+	assert.Empty(t, warnings.String())
+
+	future := now.Add(3 * time.Minute)
+	assert.Equal(t, future.String()+" (in 3m0s, clock skew?)", format(future))
+	assert.Equal(t, "WARNING: the node reported a timestamp 3m0s in the future; check the clocks of the node and of this machine\n", warnings.String())
+
+	further := now.Add(time.Hour)
+	assert.Equal(t, further.String()+" (in 1h0m0s, clock skew?)", format(further))
+	assert.Equal(t, 1, strings.Count(warnings.String(), "WARNING"), "skew should be warned about once")
+}
+
+func TestRendererTable_Styles(t *testing.T) {
+	t.Parallel()
+
+	patch := web.ConfigPatchResponse{EvmGasPriceDefault: web.Change{From: "98721", To: "53276"}}
+	devnet, testnet := cmd.SolanaChainPresenter{}, cmd.SolanaChainPresenter{}
+	devnet.ID, testnet.ID = "devnet", "testnet"
+	chains := cmd.SolanaChainPresenters{devnet, testnet}
+
+	for _, tt := range []struct {
+		style             string
+		table, list       []string
+		notTable, notList []string
+	}{
+		{"", []string{"║", "╬", "═"}, []string{"-----"}, nil, nil},
+		{"default", []string{"║", "╬", "═"}, []string{"-----"}, nil, nil},
+		{"borderless", []string{"EvmGasPriceDefault\t98721"}, []string{"\n\n"}, []string{"║", "|", "+", "-"}, []string{"-----"}},
+		{"heavy", []string{"┃", "╋", "━"}, []string{"━━━━━"}, []string{"║"}, []string{"-----"}},
+	} {
+		var table, list bytes.Buffer
+		require.NoError(t, cmd.RendererTable{Writer: &table, Style: tt.style}.Render(&patch), tt.style)
+		require.NoError(t, cmd.RendererTable{Writer: &list, Style: tt.style}.Render(&chains), tt.style)
+		// The heading is written along with the table
+		heading := map[string]string{"": "╔ ", "default": "╔ ", "borderless": "", "heavy": "┏ "}[tt.style] + "Configuration Changes\n"
+		assert.True(t, strings.HasPrefix(table.String(), heading), "%s: %q", tt.style, table.String())
+		for _, s := range tt.table {
+			assert.Contains(t, table.String(), s, tt.style)
+		}
+		for _, s := range tt.notTable {
+			assert.NotContains(t, table.String(), s, tt.style)
+		}
+		for _, s := range tt.list {
+			assert.Contains(t, list.String(), s, tt.style)
+		}
+		for _, s := range tt.notList {
+			assert.NotContains(t, list.String(), s, tt.style)
+		}
+		assert.Contains(t, list.String(), "testnet", tt.style)
+	}
+
+	var markdown bytes.Buffer
+	require.NoError(t, cmd.RendererTable{Writer: &markdown, Markdown: true}.Render(&patch))
+	assert.True(t, strings.HasPrefix(markdown.String(), "**Configuration Changes**\n\n|"), markdown.String())
+
+	app := cmd.NewApp(&cmd.Client{Config: cltest.NewTestGeneralConfig(t), Renderer: cmd.RendererTable{Writer: ioutil.Discard}})
+	err := app.Run([]string{"chainlink", "--table-style", "fancy", "chains", "solana", "ls"})
+	assert.EqualError(t, err, "unsupported --table-style 'fancy', options: [default, borderless, heavy]")
+}
@@ -1,18 +1,36 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 	"go.uber.org/multierr"
+	"gopkg.in/guregu/null.v4"
 
+	solanacfg "github.com/smartcontractkit/chainlink-solana/pkg/solana/config"
+	"github.com/smartcontractkit/chainlink-solana/pkg/solana/db"
+
+	"github.com/smartcontractkit/chainlink/core/logger"
+	"github.com/smartcontractkit/chainlink/core/services"
+	"github.com/smartcontractkit/chainlink/core/static"
+	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/utils"
+	"github.com/smartcontractkit/chainlink/core/web"
 	"github.com/smartcontractkit/chainlink/core/web/presenters"
 )
 
@@ -21,190 +39,3141 @@ type SolanaChainPresenter struct {
 	presenters.SolanaChainResource
 }
 
-// ToRow presents the SolanaChainResource as a slice of strings.
-func (p *SolanaChainPresenter) ToRow() []string {
-	// NOTE: it's impossible to omitempty null fields when serializing to JSON: https://github.com/golang/go/issues/11939
-	config, err := json.MarshalIndent(p.Config, "", "    ")
+// ToRow presents the SolanaChainResource as a slice of strings. It panics if
+// the config cannot be formatted, see Row.
+func (p *SolanaChainPresenter) ToRow(rt RendererTable) []string {
+	row, err := p.Row(rt)
 	if err != nil {
 		panic(err)
 	}
+	return row
+}
+
+// Row presents the SolanaChainResource as a slice of strings, or returns an
+// error if its config cannot be formatted.
+func (p *SolanaChainPresenter) Row(rt RendererTable) ([]string, error) {
+	config, err := rt.formatChainConfig(p.Config)
+	if err != nil {
+		return nil, err
+	}
 
 	row := []string{
 		p.GetID(),
 		strconv.FormatBool(p.Enabled),
-		string(config),
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		config,
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 	}
-	return row
+	return row, nil
 }
 
 // RenderTable implements TableRenderer
 // Just renders a single row
 func (p SolanaChainPresenter) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
-	rows := [][]string{}
-	rows = append(rows, p.ToRow())
+	row, err := p.Row(rt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render chain %s", p.GetID())
+	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, [][]string{row})
 
 	return nil
 }
 
+// SolanaChainWithNodesPresenter implements TableRenderer for a Solana chain
+// and its nodes, as shown with --include=nodes.
+type SolanaChainWithNodesPresenter struct {
+	SolanaChainPresenter
+	Nodes SolanaNodePresenters `json:"nodes"`
+}
+
+// RenderTable implements TableRenderer
+// Renders the chain followed by the table of its nodes
+func (p SolanaChainWithNodesPresenter) RenderTable(rt RendererTable) error {
+	if err := p.SolanaChainPresenter.RenderTable(rt); err != nil {
+		return err
+	}
+	fmt.Fprintln(rt.Writer)
+	if len(p.Nodes) == 0 {
+		fmt.Fprintln(rt.Writer, "No nodes")
+		return nil
+	}
+	return p.Nodes.RenderTable(rt)
+}
+
 // SolanaChainPresenters implements TableRenderer for a slice of SolanaChainPresenters.
 type SolanaChainPresenters []SolanaChainPresenter
 
 // RenderTable implements TableRenderer
 func (ps SolanaChainPresenters) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
 	rows := [][]string{}
+	var failed rowErrors
 
 	for _, p := range ps {
-		rows = append(rows, p.ToRow())
+		row, err := p.Row(rt)
+		if err != nil {
+			failed.add(p.GetID(), err)
+			continue
+		}
+		rows = append(rows, row)
 	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, rows)
 
-	return nil
+	return failed.report(rt)
 }
 
-// IndexSolanaChains returns all Solana chains.
-func (cli *Client) IndexSolanaChains(c *cli.Context) (err error) {
-	return cli.getPage("/v2/chains/solana", c.Int("page"), &SolanaChainPresenters{})
+// SolanaChainConfigVersion is a past config of a Solana chain, as served by
+// nodes which record chain config history. Its ID is the version number.
+type SolanaChainConfigVersion struct {
+	presenters.JAID
+	Config    db.ChainCfg `json:"config"`
+	ChangedAt time.Time   `json:"changedAt"`
+	ChangedBy string      `json:"changedBy"`
 }
 
-// CreateSolanaChain adds a new Solana chain.
-func (cli *Client) CreateSolanaChain(c *cli.Context) (err error) {
-	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in the chain's parameters [-id string] [JSON blob | JSON filepath]"))
+// GetName implements the api2go EntityNamer interface
+func (SolanaChainConfigVersion) GetName() string {
+	return "solana_chain_config_versions"
+}
+
+// SolanaChainConfigVersionPresenters implements TableRenderer for the config
+// history of a Solana chain.
+type SolanaChainConfigVersionPresenters []SolanaChainConfigVersion
+
+// RenderTable implements TableRenderer
+func (ps SolanaChainConfigVersionPresenters) RenderTable(rt RendererTable) error {
+	rows := [][]string{}
+	for _, p := range ps {
+		config, err := rt.formatChainConfig(p.Config)
+		if err != nil {
+			return err
+		}
+		changedBy := p.ChangedBy
+		if changedBy == "" {
+			changedBy = "unknown"
+		}
+		rows = append(rows, []string{p.GetID(), formatTimestamp(p.ChangedAt), changedBy, config})
 	}
+	rt.renderTypedList(
+		[]string{"Version", "Changed At", "Changed By", "Config"},
+		[]columnType{columnNumeric, columnText, columnText, columnText},
+		rows,
+	)
+	return nil
+}
+
+// errChainHistoryUnsupported is returned by HistorySolanaChain when the node
+// does not serve chain config history.
+var errChainHistoryUnsupported = errors.New("this node does not record chain config history: 'solana chains history' requires a node which serves /v2/chains/solana/:ID/history")
+
+// HistorySolanaChain lists the past configs of a Solana chain. This requires
+// the node to record chain config history, which nodes which only store the
+// current config do not; it then fails with errChainHistoryUnsupported rather
+// than a bare 404.
+func (cli *Client) HistorySolanaChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
 	chainID := c.String("id")
 	if chainID == "" {
-		return cli.errorOut(errors.New("missing chain ID [-id string]"))
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
 	}
 
-	buf, err := getBufferFromJSON(c.Args().First())
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID + "/history")
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
 
-	params := map[string]interface{}{
-		"chainID": chainID,
-		"config":  json.RawMessage(buf.Bytes()),
+	if resp.StatusCode == http.StatusNotFound {
+		// Tell a missing chain apart from a missing history endpoint.
+		var chain SolanaChainPresenter
+		if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to get chain %s", chainID))
+		}
+		return cli.errorOut(errChainHistoryUnsupported)
 	}
+	return cli.renderAPIResponse(resp, &SolanaChainConfigVersionPresenters{})
+}
 
-	body, err := json.Marshal(params)
+// solanaChainSummary is the part of a Solana chain fetched by ids.
+type solanaChainSummary struct {
+	presenters.JAID
+	Enabled bool `json:"enabled"`
+}
+
+// GetName implements the api2go EntityNamer interface.
+func (solanaChainSummary) GetName() string {
+	return "solana_chain"
+}
+
+// sparseFieldset implements sparseResource, as the IDs are requested without
+// attributes.
+func (solanaChainSummary) sparseFieldset() {}
+
+// SolanaChainIDs prints the IDs of all Solana chains, one per line, for
+// scripts. Only the fields needed are requested, with a JSON API sparse
+// fieldset, falling back to the full chains for nodes which reject it.
+// --enabled and --disabled are pushed to the node as the 'enabled' query
+// parameter, and applied client-side as well, as for list.
+func (cli *Client) SolanaChainIDs(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, flagConflict{"enabled", "disabled"}); err != nil {
+		return cli.errorOut(err)
+	}
+	uri, err := chainsPageURI(c, "solana")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var enabled *bool
+	if c.Bool("enabled") || c.Bool("disabled") {
+		state := c.Bool("enabled")
+		enabled = &state
+		if uri, err = withQueryParam(uri, "enabled", strconv.FormatBool(state)); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	fields := "id"
+	if enabled != nil {
+		fields = "id,enabled"
+	}
+	sparse, err := withQueryParam(uri, "fields[solana_chain]", fields)
 	if err != nil {
 		return cli.errorOut(err)
 	}
 
-	var resp *http.Response
-	resp, err = cli.HTTP.Post("/v2/chains/solana", bytes.NewBuffer(body))
+	resp, err := cli.HTTP.Get(sparse)
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	if resp.StatusCode == http.StatusBadRequest {
+		// The node does not support sparse fieldsets
+		if err = resp.Body.Close(); err != nil {
+			return cli.errorOut(err)
+		}
+		if resp, err = cli.HTTP.Get(uri); err != nil {
+			return cli.errorOut(err)
+		}
+	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
 			err = multierr.Append(err, cerr)
 		}
 	}()
-
-	return cli.renderAPIResponse(resp, &SolanaChainPresenter{})
+	var chains []solanaChainSummary
+	links := jsonapi.Links{}
+	if err = cli.deserializeAPIResponse(resp, &chains, &links); err != nil {
+		return cli.errorOut(err)
+	}
+	if next := links[web.KeyNextLink].Href; next != "" {
+		if err = cli.getAllPages(cli.HTTP, next, &chains); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	for _, chain := range chains {
+		if enabled == nil || chain.Enabled == *enabled {
+			fmt.Fprintln(cli.stdout(), chain.ID)
+		}
+	}
+	return nil
 }
 
-// RemoveSolanaChain removes a specific Solana Chain by id.
-func (cli *Client) RemoveSolanaChain(c *cli.Context) (err error) {
-	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass the id of the chain to be removed"))
+// IndexSolanaChains returns all Solana chains, rendered in the format of
+// --output. When --sort, --select, --wide, --only-unhealthy or
+// --template-file is set, every page is fetched, and the chains are filtered
+// and sorted before being rendered, having their selected fields written or
+// being passed to the template.
+func (cli *Client) IndexSolanaChains(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, solanaIndexChainsConflicts...); err != nil {
+		return cli.errorOut(err)
 	}
-	chainID := c.Args().First()
-	resp, err := cli.HTTP.Delete("/v2/chains/solana/" + chainID)
+	restore, err := cli.useOutputRenderer(c)
 	if err != nil {
 		return cli.errorOut(err)
 	}
-	_, err = cli.parseResponse(resp)
+	defer restore()
+	cli.setChainRenderOpts(c)
+	uri, err := chainsPageURI(c, "solana")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if uri, err = withPageCursor(c, uri); err != nil {
+		return cli.errorOut(err)
+	}
+	enabled, err := enabledFilter(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if enabled != nil {
+		if uri, err = withQueryParam(uri, "enabled", strconv.FormatBool(*enabled)); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if c.Bool("raw") {
+		return cli.errorOut(cli.writeRaw(cli.stdout(), uri, c.Int("page")))
+	}
+	if c.Bool("with-links") {
+		return cli.errorOut(cli.renderPageWithLinks(c, uri, &SolanaChainPresenters{}))
+	}
+	proj, err := jqFlag(c)
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	sortKey, fields, onlyUnhealthy := c.String("sort"), selectFields(c), c.Bool("only-unhealthy")
+	templateFile := c.String("template-file")
+	namesOnly := c.Bool("names-only")
+	maxRows := c.Int("max-rows")
+	if maxRows < 0 {
+		return cli.errorOut(usageError(c, errors.Errorf("invalid --max-rows %d: must be at least 1", maxRows)))
+	}
+	if sortKey == "" && len(fields) == 0 && !c.Bool("wide") && !onlyUnhealthy && enabled == nil && templateFile == "" && proj == nil && !namesOnly && maxRows == 0 {
+		return cli.errorOut(cli.renderPageWithCursors(c, cli.stderr(), uri, &SolanaChainPresenters{}))
+	}
 
-	fmt.Printf("Chain %v deleted\n", c.Args().First())
-	return nil
+	var chains SolanaChainPresenters
+	if err = cli.getAllPages(cli.HTTP, uri, &chains); err != nil {
+		return cli.errorOut(err)
+	}
+	if enabled != nil {
+		// In case the node ignored the filter
+		filtered := chains[:0]
+		for _, chain := range chains {
+			if chain.Enabled == *enabled {
+				filtered = append(filtered, chain)
+			}
+		}
+		chains = filtered
+	}
+	if onlyUnhealthy {
+		if chains, err = cli.unhealthySolanaChains(chains); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if sortKey != "" {
+		if err = sortSolanaChains(chains, sortKey); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if maxRows > 0 && len(chains) > maxRows {
+		// Noted on stderr, so that truncated JSON stays valid
+		hidden := len(chains) - maxRows
+		chains = chains[:maxRows]
+		defer func() {
+			if err == nil {
+				fmt.Fprintf(cli.stderr(), "... %d more not shown\n", hidden)
+			}
+		}()
+	}
+	if len(fields) > 0 {
+		return cli.errorOut(writeSelected(cli.stdout(), chains, fields, c.Bool("headers")))
+	}
+	if namesOnly {
+		for _, chain := range chains {
+			fmt.Fprintln(cli.stdout(), chainDisplayName(chain.ID, chain.Config))
+		}
+		return nil
+	}
+	if templateFile != "" {
+		return cli.errorOut(executeTemplateFile(cli.stdout(), templateFile, chains, c.Bool("template-once")))
+	}
+	if proj != nil {
+		return cli.errorOut(writeProjected(cli.stdout(), chains, proj))
+	}
+	if c.Bool("wide") {
+		wide, werr := cli.wideChains("solana", chains)
+		if werr != nil {
+			return cli.errorOut(werr)
+		}
+		return cli.errorOut(cli.Render(&wide))
+	}
+	return cli.errorOut(cli.Render(&chains))
 }
 
-// ConfigureSolanaChain configures an existing Solana chain.
-func (cli *Client) ConfigureSolanaChain(c *cli.Context) (err error) {
-	chainID := c.String("id")
-	if chainID == "" {
-		return cli.errorOut(errors.New("missing chain ID (usage: chainlink solana chains configure [-id string] [key1=value1 key2=value2 ...])"))
+// errNodeHealthUnsupported is returned by --only-unhealthy when the node
+// reports no health checks for its Solana nodes.
+var errNodeHealthUnsupported = errors.New("this node does not report the health of its Solana nodes, so --only-unhealthy cannot be applied")
+
+// solanaNodeCheckName is the name of the health check of a Solana node.
+func solanaNodeCheckName(node presenters.SolanaNodeResource) string {
+	return "Solana." + node.SolanaChainID + "." + node.Name
+}
+
+// unhealthySolanaChains keeps the chains with at least one node whose health
+// check is not passing, from a single fetch of all nodes and of /health.
+// Nodes without a check are assumed healthy.
+func (cli *Client) unhealthySolanaChains(chains SolanaChainPresenters) (SolanaChainPresenters, error) {
+	health, err := cli.solanaNodeHealth(errNodeHealthUnsupported)
+	if err != nil {
+		return nil, err
 	}
 
-	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in at least one chain configuration parameters (usage: chainlink solana chains configure [-id string] [key1=value1 key2=value2 ...])"))
+	filtered := SolanaChainPresenters{}
+	for _, chain := range chains {
+		for _, passing := range health[chain.ID] {
+			if !passing {
+				filtered = append(filtered, chain)
+				break
+			}
+		}
 	}
+	return filtered, nil
+}
 
-	// Fetch existing config
-	resp, err := cli.HTTP.Get(fmt.Sprintf("/v2/chains/solana/%s", chainID))
+// solanaNodeHealth returns whether the health check of each Solana node is
+// passing, by chain ID, from a single fetch of all nodes and of /health.
+// Nodes without a check are assumed healthy. If the node reports the health of
+// none of its Solana nodes, it fails with unsupported.
+func (cli *Client) solanaNodeHealth(unsupported error) (map[string][]bool, error) {
+	var nodes []presenters.SolanaNodeResource
+	if err := cli.getAllPages(cli.HTTP, "/v2/nodes/solana", &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to get nodes")
+	}
+	checks, err := cli.healthChecks()
 	if err != nil {
-		return cli.errorOut(err)
+		return nil, err
+	}
+
+	reported := false
+	health := map[string][]bool{}
+	for _, node := range nodes {
+		status, ok := checks[solanaNodeCheckName(node)]
+		reported = reported || ok
+		health[node.SolanaChainID] = append(health[node.SolanaChainID], !ok || status == services.StatusPassing)
+	}
+	if !reported && len(nodes) > 0 {
+		return nil, unsupported
+	}
+	return health, nil
+}
+
+// healthChecks returns the status of each health check of the node by name.
+// /health responds with 503 when any check fails, so the body is read
+// regardless of the status.
+func (cli *Client) healthChecks() (checks map[string]services.Status, err error) {
+	resp, err := cli.HTTP.Get("/health")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get health checks")
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
 			err = multierr.Append(err, cerr)
 		}
 	}()
-	var chain presenters.SolanaChainResource
-	if err = cli.deserializeAPIResponse(resp, &chain, &jsonapi.Links{}); err != nil {
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read health checks")
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, errors.Errorf("failed to get health checks: %s", resp.Status)
+	}
+	var list []presenters.Check
+	if err = jsonapi.Unmarshal(b, &list); err != nil {
+		return nil, errors.Wrap(err, "failed to parse health checks")
+	}
+	checks = map[string]services.Status{}
+	for _, check := range list {
+		checks[check.Name] = check.Status
+	}
+	return checks, nil
+}
+
+// solanaIndexChainsConflicts are the conflicting flags of IndexSolanaChains.
+var solanaIndexChainsConflicts = append([]flagConflict{
+	{"with-links", "sort"}, {"with-links", "only-unhealthy"},
+	{"raw", "sort"}, {"raw", "only-unhealthy"},
+	{"after", "before"},
+	{"names-only", "with-links"}, {"names-only", "raw"}, {"names-only", "select"}, {"names-only", "template-file"},
+	{"names-only", "wide"}, {"names-only", "jq"},
+	{"max-rows", "raw"}, {"max-rows", "with-links"},
+}, indexChainsConflicts...)
+
+// showSolanaChainConflicts are the conflicting flags of ShowSolanaChain.
+var showSolanaChainConflicts = []flagConflict{
+	{"fields-json", "raw"}, {"fields-json", "field"}, {"fields-json", "expect-config"}, {"fields-json", "template-file"},
+	{"raw", "field"}, {"raw", "expect-config"}, {"raw", "template-file"},
+	{"field", "expect-config"}, {"field", "template-file"},
+	{"expect-config", "template-file"},
+	{"jq", "fields-json"}, {"jq", "raw"}, {"jq", "field"}, {"jq", "expect-config"}, {"jq", "template-file"},
+	{"output", "fields-json"}, {"output", "raw"}, {"output", "field"}, {"output", "expect-config"}, {"output", "template-file"}, {"output", "jq"},
+	{"config-diff", "fields-json"}, {"config-diff", "raw"}, {"config-diff", "field"}, {"config-diff", "expect-config"},
+	{"config-diff", "template-file"}, {"config-diff", "jq"}, {"config-diff", "output"},
+	{"id", "match"},
+	{"include", "fields-json"}, {"include", "raw"}, {"include", "field"}, {"include", "expect-config"},
+	{"include", "template-file"}, {"include", "jq"}, {"include", "config-diff"},
+}
+
+// configureChainConflicts are the conflicting flags of ConfigureSolanaChain.
+var configureChainConflicts = []flagConflict{
+	{"no-render", "changed-only"}, {"no-render", "output=json"},
+	{"id", "match"},
+	{"from-show", "config-file"},
+}
+
+// ChainAge identifies a chain by its ID and creation time.
+type ChainAge struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SolanaChainStatsPresenter implements TableRenderer for the aggregate
+// metrics of all Solana chains.
+type SolanaChainStatsPresenter struct {
+	Total    int `json:"total"`
+	Enabled  int `json:"enabled"`
+	Disabled int `json:"disabled"`
+	// AvgConfigBytes is the average size of the chain configs as JSON.
+	AvgConfigBytes float64 `json:"avgConfigBytes"`
+	// ZeroAliveNodes is the number of chains without a healthy node, or nil
+	// without --health.
+	ZeroAliveNodes *int      `json:"zeroAliveNodes"`
+	Oldest         *ChainAge `json:"oldest"`
+	Newest         *ChainAge `json:"newest"`
+}
+
+// RenderTable implements TableRenderer
+func (p SolanaChainStatsPresenter) RenderTable(rt RendererTable) error {
+	zeroAlive := "n/a"
+	if p.ZeroAliveNodes != nil {
+		zeroAlive = strconv.Itoa(*p.ZeroAliveNodes)
+	}
+	age := func(a *ChainAge) string {
+		if a == nil {
+			return "n/a"
+		}
+		return a.ID + " (" + formatTimestamp(a.CreatedAt) + ")"
+	}
+	table := rt.newTable([]string{"Metric", "Value"})
+	table.AppendBulk([][]string{
+		{"Total", strconv.Itoa(p.Total)},
+		{"Enabled", strconv.Itoa(p.Enabled)},
+		{"Disabled", strconv.Itoa(p.Disabled)},
+		{"Avg config size", strconv.FormatFloat(p.AvgConfigBytes, 'f', 1, 64) + " bytes"},
+		{"Without alive nodes", zeroAlive},
+		{"Oldest", age(p.Oldest)},
+		{"Newest", age(p.Newest)},
+	})
+	rt.render("Solana chain stats", table)
+	return nil
+}
+
+// errNodeHealthUnsupportedStats is returned by stats --health when the node
+// reports no health checks for its Solana nodes.
+var errNodeHealthUnsupportedStats = errors.New("this node does not report the health of its Solana nodes, so --health cannot be applied")
+
+// StatsSolanaChains summarizes all Solana chains: how many there are, enabled
+// and disabled, their average config size, and the oldest and newest. With
+// --health, it also counts the chains without a healthy node.
+func (cli *Client) StatsSolanaChains(c *cli.Context) (err error) {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	var chains SolanaChainPresenters
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
 		return cli.errorOut(err)
 	}
-	config := chain.Config
+	p, err := solanaChainStats(chains)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("health") {
+		health, herr := cli.solanaNodeHealth(errNodeHealthUnsupportedStats)
+		if herr != nil {
+			return cli.errorOut(herr)
+		}
+		zeroAlive := 0
+		for _, chain := range chains {
+			alive := false
+			for _, passing := range health[chain.ID] {
+				alive = alive || passing
+			}
+			if !alive {
+				zeroAlive++
+			}
+		}
+		p.ZeroAliveNodes = &zeroAlive
+	}
+	return cli.errorOut(r.Render(&p))
+}
 
-	// Parse new key-value pairs
-	params := map[string]interface{}{}
-	for _, arg := range c.Args() {
-		parts := strings.SplitN(arg, "=", 2)
-		if len(parts) != 2 {
-			return cli.errorOut(errors.Errorf("invalid parameter: %v", arg))
+// solanaChainStats aggregates the metrics of chains which do not need node
+// health.
+func solanaChainStats(chains SolanaChainPresenters) (p SolanaChainStatsPresenter, err error) {
+	configBytes := 0
+	for _, chain := range chains {
+		p.Total++
+		if chain.Enabled {
+			p.Enabled++
+		} else {
+			p.Disabled++
+		}
+		b, err := json.Marshal(chain.Config)
+		if err != nil {
+			return p, errors.Wrapf(err, "failed to marshal the config of chain %s", chain.ID)
 		}
+		configBytes += len(b)
+		if p.Oldest == nil || chain.CreatedAt.Before(p.Oldest.CreatedAt) {
+			p.Oldest = &ChainAge{ID: chain.ID, CreatedAt: chain.CreatedAt}
+		}
+		if p.Newest == nil || chain.CreatedAt.After(p.Newest.CreatedAt) {
+			p.Newest = &ChainAge{ID: chain.ID, CreatedAt: chain.CreatedAt}
+		}
+	}
+	if p.Total > 0 {
+		p.AvgConfigBytes = float64(configBytes) / float64(p.Total)
+	}
+	return p, nil
+}
 
-		var value interface{}
-		if err = json.Unmarshal([]byte(parts[1]), &value); err != nil {
-			// treat it as a string
-			value = parts[1]
+// WaitSolanaChain polls a Solana chain until it is ready, with at least one
+// node passing its health check, or --timeout elapses. Status is written to
+// stderr after each poll. If the node reports no health checks for its Solana
+// nodes, an enabled chain with at least one node is considered ready.
+func (cli *Client) WaitSolanaChain(c *cli.Context) error {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	timeout, interval := c.Duration("timeout"), c.Duration("interval")
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return cli.errorOut(cli.waitSolanaChain(cli.stderr(), chainID, timeout, interval))
+}
+
+func (cli *Client) waitSolanaChain(w io.Writer, chainID string, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		ready, status, err := cli.solanaChainReadiness(chainID)
+		if errors.Is(err, context.Canceled) {
+			return err
+		} else if err != nil {
+			status = err.Error()
+		}
+		if ready {
+			fmt.Fprintf(w, "Chain %s is ready: %s\n", chainID, status)
+			return nil
 		}
-		// TODO: handle `key=nil` and `key=` besides just null?
-		params[parts[0]] = value
+		fmt.Fprintf(w, "Chain %s is not ready: %s\n", chainID, status)
+		if time.Now().Add(interval).After(deadline) {
+			return errors.Errorf("chain %s not ready after %s: %s", chainID, timeout, status)
+		}
+		time.Sleep(interval)
 	}
+}
 
-	// Combine new values with the existing config
-	// (serialize to a partial JSON map, deserialize to the old config struct)
-	rawUpdates, err := json.Marshal(params)
+// solanaChainReadiness reports whether the chain is ready, describing its
+// state in status.
+func (cli *Client) solanaChainReadiness(chainID string) (ready bool, status string, err error) {
+	var chain SolanaChainPresenter
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
+		return false, "", errors.Wrap(err, "failed to get chain")
+	}
+	if !chain.Enabled {
+		return false, "chain is disabled", nil
+	}
+	var nodes []presenters.SolanaNodeResource
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana/"+chainID+"/nodes", &nodes); err != nil {
+		return false, "", errors.Wrap(err, "failed to get nodes")
+	}
+	if len(nodes) == 0 {
+		return false, "chain has no nodes", nil
+	}
+	checks, err := cli.healthChecks()
 	if err != nil {
-		return cli.errorOut(err)
+		return false, "", err
+	}
+	alive, reported := 0, false
+	for _, node := range nodes {
+		if s, ok := checks[solanaNodeCheckName(node)]; ok {
+			reported = true
+			if s == services.StatusPassing {
+				alive++
+			}
+		}
+	}
+	if !reported {
+		return true, fmt.Sprintf("%d node(s), whose health the node does not report", len(nodes)), nil
+	}
+	return alive > 0, fmt.Sprintf("%d of %d node(s) alive", alive, len(nodes)), nil
+}
+
+// solanaChainSortKeys maps the --sort keys to their ordering.
+var solanaChainSortKeys = map[string]func(a, b SolanaChainPresenter) int{
+	"id": func(a, b SolanaChainPresenter) int { return strings.Compare(a.ID, b.ID) },
+	"enabled": func(a, b SolanaChainPresenter) int {
+		switch {
+		case a.Enabled == b.Enabled:
+			return 0
+		case b.Enabled:
+			return -1
+		}
+		return 1
+	},
+	"created": func(a, b SolanaChainPresenter) int { return compareTimes(a.CreatedAt, b.CreatedAt) },
+	"updated": func(a, b SolanaChainPresenter) int { return compareTimes(a.UpdatedAt, b.UpdatedAt) },
+}
+
+// sortSolanaChains stably sorts chains by key, which may be prefixed with '-'
+// for descending order. Ties are always broken by ascending ID, so that
+// listings are reproducible across runs.
+func sortSolanaChains(chains SolanaChainPresenters, key string) error {
+	desc := strings.HasPrefix(key, "-")
+	cmp, ok := solanaChainSortKeys[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return errors.Errorf("unsupported sort key %q (options: id, enabled, created, updated)", key)
 	}
+	sort.SliceStable(chains, func(i, j int) bool {
+		a, b := chains[i], chains[j]
+		if r := cmp(a, b); r != 0 {
+			return r < 0 != desc
+		}
+		return a.ID < b.ID
+	})
+	return nil
+}
 
-	err = json.Unmarshal(rawUpdates, &config)
+// ShowSolanaChain shows a specific Solana chain by id, or a single value of
+// its config when --field is set. With --expect-config, it instead checks that
+// the chain's config matches the expected file.
+func (cli *Client) ShowSolanaChain(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, showSolanaChainConflicts...); err != nil {
+		return cli.errorOut(err)
+	}
+	cli.setChainRenderOpts(c)
+	chainID, pattern := c.String("id"), c.String("match")
+	if pattern != "" && c.String("config-diff") == "" {
+		return cli.errorOut(usageError(c, errors.New("--match requires --config-diff")))
+	}
+	if c.Bool("explain-diff") && c.String("config-diff") == "" {
+		return cli.errorOut(usageError(c, errors.New("--explain-diff requires --config-diff")))
+	}
+	if chainID == "" && pattern == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	if baseline := c.String("config-diff"); baseline != "" {
+		chainIDs := []string{chainID}
+		if pattern != "" {
+			if chainIDs, err = cli.matchSolanaChains(cli.stderr(), pattern); err != nil {
+				return cli.errorOut(err)
+			}
+		}
+		return cli.errorOut(cli.diffSolanaChainBaselines(cli.stdout(), chainIDs, baseline, pattern != "", c.Bool("explain-diff")))
+	}
+	proj, err := jqFlag(c)
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	if c.Bool("raw") {
+		return cli.errorOut(cli.writeRaw(cli.stdout(), "/v2/chains/solana/"+chainID, 0))
+	}
 
-	// Send the new config
-	params = map[string]interface{}{
-		"enabled": chain.Enabled,
-		"config":  config,
+	if prefix := c.String("env-prefix"); prefix != "" {
+		if c.String("output") != "env" {
+			return cli.errorOut(usageError(c, errors.New("--env-prefix requires --output env")))
+		}
+		if err = checkEnvPrefix(prefix); err != nil {
+			return cli.errorOut(usageError(c, err))
+		}
 	}
-	body, err := json.Marshal(params)
-	if err != nil {
+	if include := c.String("include"); include != "" {
+		if include != "nodes" {
+			return cli.errorOut(usageError(c, errors.Errorf("unsupported --include '%s', options: [nodes]", include)))
+		}
+		if output := c.String("output"); output == "env" || output == "tree" || output == "hcl" {
+			return cli.errorOut(usageError(c, errors.Errorf("--include cannot be used with --output %s", output)))
+		}
+		var p SolanaChainWithNodesPresenter
+		if p, err = cli.getSolanaChainWithNodes(chainID); err != nil {
+			return cli.errorOut(err)
+		}
+		r, err := cli.outputRenderer(c)
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		return cli.errorOut(r.Render(&p))
+	}
+
+	var chain SolanaChainPresenter
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
 		return cli.errorOut(err)
 	}
-	resp, err = cli.HTTP.Patch(fmt.Sprintf("/v2/chains/solana/%s", chainID), bytes.NewBuffer(body))
+
+	if file := c.String("expect-config"); file != "" {
+		return cli.errorOut(expectChainConfig(cli.stdout(), chainID, chain.Config, file, c.Bool("expect-subset")))
+	}
+	if c.String("output") == "env" {
+		return cli.errorOut(writeConfigEnv(cli.stdout(), chain.Config, c.String("env-prefix"), c.Bool("show-secrets"), secretPatternsFlag(c)))
+	}
+	if c.String("output") == "hcl" {
+		var config interface{} = chain.Config
+		if !c.Bool("show-secrets") {
+			if config, err = redactConfig(chain.Config, secretPatternsFlag(c)); err != nil {
+				return cli.errorOut(err)
+			}
+		}
+		return cli.errorOut(writeConfigHCL(cli.stdout(), "solana_chain", chain.ID, chain.Enabled, config))
+	}
+	if c.String("output") == "tree" {
+		var config interface{} = chain.Config
+		if !c.Bool("show-secrets") {
+			if config, err = redactConfig(chain.Config, secretPatternsFlag(c)); err != nil {
+				return cli.errorOut(err)
+			}
+		}
+		state := "disabled"
+		if chain.Enabled {
+			state = "enabled"
+		}
+		fmt.Fprintf(cli.stdout(), "%s (%s)\n", chain.ID, state)
+		return cli.errorOut(writeConfigTree(cli.stdout(), config, "  "))
+	}
+	if file := c.String("template-file"); file != "" {
+		return cli.errorOut(executeTemplateFile(cli.stdout(), file, []SolanaChainPresenter{chain}, false))
+	}
+	if proj != nil {
+		return cli.errorOut(writeProjected(cli.stdout(), []SolanaChainPresenter{chain}, proj))
+	}
+	if c.Bool("fields-json") {
+		return cli.errorOut(writeFieldsJSON(cli.stdout(), chain.Config))
+	}
+	if field := c.String("field"); field != "" {
+		var value interface{}
+		if value, err = lookupConfigPath(chain.Config, field); err != nil {
+			return cli.errorOut(err)
+		}
+		fmt.Fprintln(cli.stdout(), formatScalar(value))
+		return nil
+	}
+	r, err := cli.outputRenderer(c)
 	if err != nil {
 		return cli.errorOut(err)
 	}
+	return cli.errorOut(r.Render(&chain))
+}
+
+// getSolanaChainWithNodes fetches the chain chainID together with its nodes,
+// asking the node to include them in the same response. Nodes which ignore
+// ?include and return no included resources have them fetched from the nodes
+// endpoint of the chain instead.
+func (cli *Client) getSolanaChainWithNodes(chainID string) (p SolanaChainWithNodesPresenter, err error) {
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID + "?include=nodes")
+	if err != nil {
+		return p, err
+	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
 			err = multierr.Append(err, cerr)
 		}
 	}()
+	b, err := cli.checkResponse(resp)
+	if err != nil {
+		return p, responseError(resp, errors.Wrap(err, "parseResponse error"))
+	}
+	var links jsonapi.Links
+	if err = web.ParsePaginatedResponse(b, &p.SolanaChainPresenter, &links); err != nil {
+		return p, err
+	}
+	var doc struct {
+		Included []json.RawMessage `json:"included"`
+	}
+	if err = json.Unmarshal(b, &doc); err != nil {
+		return p, errors.Wrap(err, "failed to parse included resources")
+	}
+	if doc.Included == nil {
+		p.Nodes = SolanaNodePresenters{}
+		return p, cli.getAllPages(cli.HTTP, "/v2/chains/solana/"+chainID+"/nodes", &p.Nodes)
+	}
+	p.Nodes = make(SolanaNodePresenters, 0, len(doc.Included))
+	for _, raw := range doc.Included {
+		var resource struct {
+			Type string `json:"type"`
+		}
+		if err = json.Unmarshal(raw, &resource); err != nil {
+			return p, errors.Wrap(err, "failed to parse included resource")
+		}
+		if resource.Type != "solana_node" {
+			continue
+		}
+		var node SolanaNodePresenter
+		if err = web.ParsePaginatedResponse([]byte(`{"data":`+string(raw)+`}`), &node, &links); err != nil {
+			return p, errors.Wrap(err, "failed to parse included node")
+		}
+		p.Nodes = append(p.Nodes, node)
+	}
+	return p, nil
+}
 
-	return cli.renderAPIResponse(resp, &SolanaChainPresenter{})
+// diffSolanaChainBaselines writes the differences between the live config of
+// each of chainIDs and its baseline to w, and fails if any differs. The
+// baseline is the JSON config file at baseline or, if it is a directory or
+// perDir is set, the file <chain ID>.json in it. Chains whose config or
+// baseline cannot be read are reported and count as differing.
+func (cli *Client) diffSolanaChainBaselines(w io.Writer, chainIDs []string, baseline string, perDir, explain bool) error {
+	if info, err := os.Stat(baseline); err == nil && info.IsDir() {
+		perDir = true
+	}
+	differing := 0
+	for _, id := range chainIDs {
+		file := baseline
+		if perDir {
+			file = filepath.Join(baseline, id+".json")
+		}
+		differs, err := cli.diffSolanaChainBaseline(w, id, file, explain)
+		if err != nil {
+			if len(chainIDs) == 1 {
+				return err
+			}
+			fmt.Fprintf(w, "Chain %s: %v\n", id, err)
+		}
+		if differs || err != nil {
+			differing++
+		}
+	}
+	switch {
+	case differing == 0:
+		return nil
+	case len(chainIDs) == 1:
+		return errors.Errorf("config of chain %s differs from %s", chainIDs[0], baseline)
+	default:
+		return errors.Errorf("config of %d of %d chains differs from the baselines in %s", differing, len(chainIDs), baseline)
+	}
+}
+
+func (cli *Client) diffSolanaChainBaseline(w io.Writer, chainID, file string, explain bool) (bool, error) {
+	expected, err := chainConfigFile(file, false)
+	if err != nil {
+		return false, err
+	}
+	var chain presenters.SolanaChainResource
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
+		return false, err
+	}
+	var explainer func(string) string
+	if explain {
+		explainer = configFieldExplainer(db.ChainCfg{}, configFieldDocs["solana"])
+	}
+	return writeConfigDiff(w, file, "chain "+chainID, expected, chain.Config, explainer)
+}
+
+// CreateSolanaChain adds a new Solana chain, rendering it in the format of
+// --output.
+func (cli *Client) CreateSolanaChain(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, flagConflict{"show-effective", "no-render"}); err != nil {
+		return cli.errorOut(err)
+	}
+	restore, err := cli.useOutputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer restore()
+	cli.setChainRenderOpts(c)
+	chainID, err := validateChainID(c.String("id"))
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	if path, ok := cli.streamableConfigFile(c); ok {
+		stream, serr := streamSolanaChainBody(chainID, path)
+		if serr != nil {
+			return cli.errorOut(serr)
+		}
+		defer stream.Close()
+		return cli.postSolanaChain(c, stream, nil)
+	}
+
+	config, err := chainConfigFromFlags(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("trim-config") {
+		var trimmed int
+		if config, trimmed, err = trimChainConfig(config); err != nil {
+			return cli.errorOut(err)
+		}
+		if c.Bool("verbose") {
+			fmt.Fprintf(cli.stderr(), "Trimmed %d null or empty config fields\n", trimmed)
+		}
+	}
+	if c.Bool("check-version") {
+		cli.warnChainConfigCompatibility(cli.stderr(), static.Version, static.Sha)
+	}
+
+	warnChainFamilyMismatch(c, cli.stderr(), "solana", config)
+	warnDeprecatedConfigKeys(cli.stderr(), "solana", config)
+	if !c.Bool("no-secret-warnings") {
+		warnSecretConfigKeys(cli.stderr(), config, secretPatternsFlag(c))
+	}
+
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"chainID": chainID,
+		"config":  config,
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	dump, err := openRequestDump(c, cli.stdout())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := dump.close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	if err = dump.write(body); err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("dry-run") {
+		return nil
+	}
+	if cli.CheckMode {
+		fmt.Fprintf(cli.stdout(), "Would create chain %s\n", chainID)
+		return nil
+	}
+	return cli.postSolanaChain(c, bytes.NewBuffer(body), config)
+}
+
+// postSolanaChain sends body to create a Solana chain, and renders the
+// created chain unless --no-render is set. With --show-effective, the config
+// fields the node filled in or normalized are rendered after the chain,
+// against the submitted config.
+func (cli *Client) postSolanaChain(c *cli.Context, body io.Reader, submitted json.RawMessage) (err error) {
+	resp, err := cli.HTTP.Post("/v2/chains/solana", body)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	if c.Bool("no-render") {
+		_, err = cli.parseResponse(resp)
+		return err
+	}
+	if !c.Bool("show-effective") {
+		return cli.renderAPIResponse(resp, &SolanaChainPresenter{})
+	}
+	var chain SolanaChainPresenter
+	if err = cli.deserializeAPIResponse(resp, &chain, &jsonapi.Links{}); err != nil {
+		return err
+	}
+	if err = cli.Render(&chain); err != nil {
+		return cli.errorOut(err)
+	}
+	var submittedConfig db.ChainCfg
+	if err = json.Unmarshal(submitted, &submittedConfig); err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid submitted config"))
+	}
+	fields, err := diffConfigs(submittedConfig, chain.Config)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(&EffectiveChainConfig{ID: chain.ID, Fields: fields}))
+}
+
+// streamConfigThreshold is the size of a --config-file from which create
+// streams it into the request body instead of reading it into memory.
+const streamConfigThreshold = 1 << 20
+
+// streamableConfigFile returns the single --config-file of c if it is large
+// enough to be streamed by create, and nothing needs the whole config in
+// memory first: the JSONC and duplicate key handling, trimming, request dumps,
+// dry runs, check mode, --show-effective and non-plain body formats all do.
+// Streamed configs are not checked for deprecated or secret-looking keys.
+func (cli *Client) streamableConfigFile(c *cli.Context) (string, bool) {
+	files := c.StringSlice("config-file")
+	if len(files) != 1 || cli.CheckMode || (cli.ChainBodyFormat != "" && cli.ChainBodyFormat != "plain") {
+		return "", false
+	}
+	for _, f := range []string{"jsonc", "strict-json", "trim-config", "dry-run", "show-effective"} {
+		if c.Bool(f) {
+			return "", false
+		}
+	}
+	if c.String("dump-request") != "" {
+		return "", false
+	}
+	info, err := os.Stat(files[0])
+	if err != nil || info.Size() < streamConfigThreshold {
+		return "", false
+	}
+	return files[0], true
+}
+
+// streamSolanaChainBody returns the plain body creating the chain with
+// chainID, with the config streamed from the file at path as it is read,
+// rather than buffered. The config is validated as it streams, and the body
+// fails to read if it is not a single JSON object. Closing the body stops the
+// stream. Its length is unknown, so it is sent chunked, and with --retries it
+// is still buffered once, to be resent.
+func streamSolanaChainBody(chainID, path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config file '%s'", path)
+	}
+	id, err := json.Marshal(chainID)
+	if err != nil {
+		return nil, multierr.Append(err, f.Close())
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		err := streamJSONObject(pw, f, []byte(`{"chainID":`+string(id)+`,"config":`), []byte(`}`))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			err = errors.Errorf("config file '%s' %v", path, err)
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// streamJSONObject copies the JSON object read from r to w, between prefix
+// and suffix, checking that r holds exactly one valid object as it goes.
+func streamJSONObject(w io.Writer, r io.Reader, prefix, suffix []byte) error {
+	if _, err := w.Write(prefix); err != nil {
+		return err
+	}
+	d := json.NewDecoder(io.TeeReader(r, w))
+	depth, values := 0, 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "is not valid JSON")
+		}
+		if depth == 0 {
+			if values++; values > 1 {
+				return errors.New("has data after the config object")
+			}
+			if tok != json.Delim('{') {
+				return errors.New("is not a JSON object")
+			}
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	if values == 0 {
+		return errors.New("is empty")
+	}
+	if depth != 0 {
+		return errors.New("is not valid JSON: unexpected end of file")
+	}
+	_, err := w.Write(suffix)
+	return err
+}
+
+// GetSolanaChainConfigValue writes the value of the config field at the
+// dotted path --key of a Solana chain as plain text, for provisioning scripts.
+// A missing or null field is written as --default instead, if given, and
+// fails the command with --required. Otherwise an empty line is written.
+func (cli *Client) GetSolanaChainConfigValue(c *cli.Context) (err error) {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	key := c.String("key")
+	if key == "" {
+		return cli.errorOut(usageError(c, errors.New("missing config field [--key path]")))
+	}
+	if c.IsSet("default") && c.Bool("required") {
+		return cli.errorOut(usageError(c, errors.New("--default cannot be combined with --required")))
+	}
+	var chain presenters.SolanaChainResource
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
+		return cli.errorOut(err)
+	}
+	value, lerr := lookupConfigPath(chain.Config, key)
+	if lerr == nil && value != nil {
+		fmt.Fprintln(cli.stdout(), formatScalar(value))
+		return nil
+	}
+	switch {
+	case c.IsSet("default"):
+		fmt.Fprintln(cli.stdout(), c.String("default"))
+	case c.Bool("required"):
+		if lerr != nil {
+			return cli.errorOut(errors.Wrapf(lerr, "chain %s", chainID))
+		}
+		return cli.errorOut(errors.Errorf("chain %s: config field %s is not set", chainID, key))
+	default:
+		fmt.Fprintln(cli.stdout())
+	}
+	return nil
+}
+
+// GetSolanaChainConfig writes the config of a Solana chain as pretty-printed
+// JSON, with sorted keys, to --file or stdout. The file can be passed back to
+// 'solana chains create --config-file'. It is written atomically, so that an
+// interrupted write leaves an existing file intact.
+func (cli *Client) GetSolanaChainConfig(c *cli.Context) (err error) {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	var chain presenters.SolanaChainResource
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &chain); err != nil {
+		return cli.errorOut(err)
+	}
+	sorted, err := toGenericConfig(chain.Config)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b, err := utils.FormatJSON(sorted)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b = append(b, '\n')
+
+	path := c.String("file")
+	if path == "" || path == "-" {
+		_, err = cli.stdout().Write(b)
+		return cli.errorOut(err)
+	}
+	if err = writeFileAtomic(path, b); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to write '%s'", path))
+	}
+	fmt.Fprintf(cli.stderr(), "Wrote the config of chain %s to %s\n", chainID, path)
+	return nil
+}
+
+// solanaChainExport is a line of the output of ExportSolanaChains.
+type solanaChainExport struct {
+	ID      string      `json:"id"`
+	Enabled bool        `json:"enabled"`
+	Config  db.ChainCfg `json:"config"`
+}
+
+// ExportSolanaChains writes all Solana chains as JSON lines to --file, or
+// stdout. Chains are streamed out page by page rather than collected first,
+// and each page is flushed, so that if the export is interrupted the file is
+// valid JSONL up to the last chain written. With --output-file instead, the
+// file is only written once the export is complete.
+func (cli *Client) ExportSolanaChains(c *cli.Context) (err error) {
+	if format := c.String("output"); format != "" && format != "jsonl" {
+		return cli.errorOut(usageError(c, errors.Errorf("unsupported output format %q (options: jsonl)", format)))
+	}
+	if err = checkFlagConflicts(c, flagConflict{"file", "output-file"}); err != nil {
+		return cli.errorOut(err)
+	}
+	out := cli.stdout()
+	if path := c.String("file"); path != "" && path != "-" {
+		f, ferr := os.Create(path)
+		if ferr != nil {
+			return cli.errorOut(errors.Wrapf(ferr, "failed to create export file '%s'", path))
+		}
+		defer func() {
+			if cerr := f.Close(); cerr != nil {
+				err = multierr.Append(err, cerr)
+			}
+		}()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	exported := 0
+	err = cli.forEachPage(cli.HTTP, "/v2/chains/solana", func() interface{} { return &SolanaChainPresenters{} }, func(page interface{}) error {
+		for _, chain := range *page.(*SolanaChainPresenters) {
+			if err := enc.Encode(solanaChainExport{ID: chain.ID, Enabled: chain.Enabled, Config: chain.Config}); err != nil {
+				return err
+			}
+			exported++
+		}
+		return w.Flush()
+	})
+	if err != nil {
+		fmt.Fprintf(cli.stderr(), "Export stopped after %d chains\n", exported)
+		return cli.errorOut(multierr.Append(err, w.Flush()))
+	}
+	return cli.errorOut(w.Flush())
+}
+
+// ApplySolanaChains makes the Solana chains of the node match the JSON lines
+// of --file, in the format written by export: missing chains are created, and
+// chains whose config or enabled state differ are updated. Chains which are
+// not in the file are left alone, so applying the same file twice changes
+// nothing the second time. A summary of the outcomes is written at the end.
+func (cli *Client) ApplySolanaChains(c *cli.Context) (err error) {
+	var jsonOutput bool
+	switch format := c.String("output"); format {
+	case "", "table":
+	case "json":
+		jsonOutput = true
+	default:
+		return cli.errorOut(errors.Errorf("unsupported output format %q (options: table, json)", format))
+	}
+	path := c.String("file")
+	if path == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass the file of chains to apply [--file FILE]")))
+	}
+	in := io.Reader(os.Stdin)
+	if path != "-" {
+		f, ferr := os.Open(path)
+		if ferr != nil {
+			return cli.errorOut(errors.Wrapf(ferr, "failed to open '%s'", path))
+		}
+		defer func() {
+			if cerr := f.Close(); cerr != nil {
+				err = multierr.Append(err, cerr)
+			}
+		}()
+		in = f
+	}
+	lines, err := readKeyValueLines(in)
+	if err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to read '%s'", path))
+	}
+	// Check the whole file before changing anything
+	chains := make([]solanaChainExport, len(lines))
+	for i, line := range lines {
+		if err = json.Unmarshal([]byte(line), &chains[i]); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "%s: invalid chain %d", path, i+1))
+		}
+		if strings.TrimSpace(chains[i].ID) == "" {
+			return cli.errorOut(errors.Errorf("%s: invalid chain %d: missing id", path, i+1))
+		}
+		if chains[i].ID, err = validateChainID(chains[i].ID); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "%s: invalid chain %d", path, i+1))
+		}
+	}
+
+	var summary ChainApplySummary
+	var progress io.Writer
+	if !jsonOutput {
+		progress = cli.stdout()
+	}
+	for _, chain := range chains {
+		outcome, aerr := cli.applySolanaChain(chain)
+		summary.add(progress, chain.ID, outcome, aerr)
+	}
+	return cli.errorOut(summary.report(cli.stdout(), jsonOutput))
+}
+
+// reconcileStep is a change of the plan of ReconcileSolanaChains.
+type reconcileStep struct {
+	outcome chainApplyOutcome
+	desired solanaChainExport
+	// changed are the config keys which an update changes.
+	changed []string
+}
+
+// ReconcileSolanaChains makes the Solana chains of the node match the desired
+// state in --dir, a directory of chain config files named <chain ID>.json:
+// missing chains are created, and chains whose config drifted are updated,
+// keeping their enabled state. With --prune, chains without a file are
+// deleted. The plan is printed first, and must be confirmed unless --yes is
+// set.
+func (cli *Client) ReconcileSolanaChains(c *cli.Context) (err error) {
+	dir := c.String("dir")
+	if dir == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass the directory of chain configs to reconcile [--dir DIR]")))
+	}
+	desired, err := readSolanaChainDir(dir, c.Bool("jsonc"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var current []presenters.SolanaChainResource
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana", &current); err != nil {
+		return cli.errorOut(errors.Wrap(err, "failed to get chains"))
+	}
+
+	plan, unchanged, err := planSolanaReconcile(desired, current, c.Bool("prune"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	w := cli.stdout()
+	if len(plan) == 0 {
+		fmt.Fprintf(w, "Nothing to reconcile, all %d chains match %s\n", unchanged, dir)
+		return nil
+	}
+	for _, step := range plan {
+		switch step.outcome {
+		case chainCreated:
+			fmt.Fprintf(w, "+ create %s\n", step.desired.ID)
+		case chainUpdated:
+			fmt.Fprintf(w, "~ update %s: %s\n", step.desired.ID, strings.Join(step.changed, ", "))
+		case chainDeleted:
+			fmt.Fprintf(w, "- delete %s\n", step.desired.ID)
+		}
+	}
+	if cli.CheckMode {
+		return nil
+	}
+	if err = cli.confirm(c, fmt.Sprintf("Apply %d change(s)?", len(plan)), false, fmt.Sprintf("apply %d change(s)", len(plan)), "yes"); err != nil {
+		return cli.errorOut(err)
+	}
+
+	summary := ChainApplySummary{Unchanged: unchanged}
+	for _, step := range plan {
+		outcome, aerr := step.outcome, error(nil)
+		if step.outcome == chainDeleted {
+			aerr = cli.removeSolanaChain(step.desired.ID)
+		} else {
+			outcome, aerr = cli.applySolanaChain(step.desired)
+		}
+		if aerr != nil {
+			outcome = chainApplyFailed
+		}
+		summary.add(w, step.desired.ID, outcome, aerr)
+	}
+	return cli.errorOut(summary.report(w, false))
+}
+
+// readSolanaChainDir reads the desired chain configs in dir, by the chain IDs
+// their <chain ID>.json file names give. Other files are ignored.
+func readSolanaChainDir(dir string, jsonc bool) (map[string]db.ChainCfg, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, errors.Errorf("'%s' is not a directory", dir)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	desired := map[string]db.ChainCfg{}
+	for _, path := range paths {
+		id, verr := validateChainID(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if verr != nil {
+			return nil, errors.Wrapf(verr, "invalid chain config file name '%s'", path)
+		}
+		raw, rerr := chainConfigFile(path, jsonc)
+		if rerr != nil {
+			return nil, rerr
+		}
+		var config db.ChainCfg
+		if err = json.Unmarshal(raw, &config); err != nil {
+			return nil, errors.Wrapf(err, "invalid chain config file '%s'", path)
+		}
+		desired[id] = config
+	}
+	return desired, nil
+}
+
+// planSolanaReconcile returns the steps making current match desired, in
+// chain ID order, and how many chains already match.
+func planSolanaReconcile(desired map[string]db.ChainCfg, current []presenters.SolanaChainResource, prune bool) (plan []reconcileStep, unchanged int, err error) {
+	existing := map[string]presenters.SolanaChainResource{}
+	for _, chain := range current {
+		existing[chain.ID] = chain
+	}
+	ids := make([]string, 0, len(desired))
+	for id := range desired {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		chain, ok := existing[id]
+		if !ok {
+			plan = append(plan, reconcileStep{outcome: chainCreated, desired: solanaChainExport{ID: id, Enabled: true, Config: desired[id]}})
+			continue
+		}
+		diff, derr := diffConfigs(chain.Config, desired[id])
+		if derr != nil {
+			return nil, 0, errors.Wrapf(derr, "failed to diff chain %s", id)
+		}
+		if len(diff) == 0 {
+			unchanged++
+			continue
+		}
+		var changed []string
+		for _, d := range diff {
+			changed = append(changed, d.Key)
+		}
+		plan = append(plan, reconcileStep{outcome: chainUpdated, desired: solanaChainExport{ID: id, Enabled: chain.Enabled, Config: desired[id]}, changed: changed})
+	}
+	if prune {
+		var stale []string
+		for id := range existing {
+			if _, ok := desired[id]; !ok {
+				stale = append(stale, id)
+			}
+		}
+		sort.Strings(stale)
+		for _, id := range stale {
+			plan = append(plan, reconcileStep{outcome: chainDeleted, desired: solanaChainExport{ID: id}})
+		}
+	}
+	return plan, unchanged, nil
+}
+
+// applySolanaChain creates or updates the chain with the ID of desired to
+// match it, comparing it with the chain's current state first.
+func (cli *Client) applySolanaChain(desired solanaChainExport) (chainApplyOutcome, error) {
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + desired.ID)
+	if err != nil {
+		return chainApplyFailed, err
+	}
+	var current presenters.SolanaChainResource
+	found := resp.StatusCode != http.StatusNotFound
+	if found {
+		err = cli.deserializeAPIResponse(resp, &current, &jsonapi.Links{})
+	} else {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+	}
+	if cerr := resp.Body.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return chainApplyFailed, err
+	}
+
+	if !found {
+		if err = cli.sendSolanaChain(cli.HTTP.Post, "/v2/chains/solana", map[string]interface{}{
+			"chainID": desired.ID,
+			"config":  desired.Config,
+		}); err != nil {
+			return chainApplyFailed, err
+		}
+		// Chains are created enabled
+		if !desired.Enabled {
+			if _, _, err = cli.setSolanaChainEnabled(desired.ID, false); err != nil {
+				return chainApplyFailed, errors.Wrap(err, "created, but failed to disable")
+			}
+		}
+		return chainCreated, nil
+	}
+
+	diff, err := diffConfigs(current.Config, desired.Config)
+	if err != nil {
+		return chainApplyFailed, err
+	}
+	if current.Enabled == desired.Enabled && len(diff) == 0 {
+		return chainUnchanged, nil
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": desired.Enabled,
+		"config":  desired.Config,
+	})
+	if err != nil {
+		return chainApplyFailed, err
+	}
+	if _, err = cli.patchSolanaChain(desired.ID, body); err != nil {
+		return chainApplyFailed, err
+	}
+	return chainUpdated, nil
+}
+
+// errChainAbsent marks chains which --ignore-not-found found to be deleted
+// already.
+var errChainAbsent = errors.New("chain already absent")
+
+// RemoveSolanaChain removes one or more Solana chains by id, up to
+// --max-concurrency at a time. With --ignore-not-found, chains which do not
+// exist are reported as already absent instead of failing, so that teardown
+// scripts are idempotent. With --output json, the outcomes are written as
+// ChainDeleteResults for scripts. With --match, the chains whose IDs match a
+// glob pattern are deleted, after confirmation. With --on-error=abort, the
+// chains not being deleted yet when one fails are skipped.
+func (cli *Client) RemoveSolanaChain(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, flagConflict{"match", "ids-stdin"}, flagConflict{"confirm-exists", "ignore-not-found"}); err != nil {
+		return cli.errorOut(err)
+	}
+	pattern, idsStdin := c.String("match"), c.Bool("ids-stdin")
+	if !c.Args().Present() && pattern == "" && !idsStdin {
+		return cli.errorOut(usageError(c, errors.New("must pass the id of the chain to be removed")))
+	}
+	if c.Args().Present() && pattern != "" {
+		return cli.errorOut(usageError(c, errors.New("--match cannot be combined with chain ID arguments")))
+	}
+	if c.Args().Present() && idsStdin {
+		return cli.errorOut(usageError(c, errors.New("--ids-stdin cannot be combined with chain ID arguments")))
+	}
+	concurrency, err := concurrencyFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	abort, err := onErrorFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var jsonOutput bool
+	switch format := c.String("output"); format {
+	case "", "table":
+	case "json":
+		jsonOutput = true
+	default:
+		return cli.errorOut(errors.Errorf("unsupported output format %q (options: table, json)", format))
+	}
+	chainIDs := []string(c.Args())
+	if pattern != "" {
+		if chainIDs, err = cli.matchSolanaChains(cli.stderr(), pattern); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if idsStdin {
+		if chainIDs, err = readChainIDsJSON(os.Stdin); err != nil {
+			return cli.errorOut(usageError(c, err))
+		}
+	}
+	if cli.CheckMode {
+		for _, id := range chainIDs {
+			fmt.Fprintf(cli.stdout(), "Would delete chain %s\n", id)
+		}
+		return nil
+	}
+	if pattern != "" || idsStdin {
+		if err = cli.confirmChainMatches(c, "Delete", chainIDs); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if c.Bool("confirm-exists") {
+		if err = cli.checkSolanaChainsExist(cli.stdout(), chainIDs); err != nil {
+			return cli.errorOut(err)
+		}
+		what := fmt.Sprintf("the %d chain(s) above", len(chainIDs))
+		if err = cli.confirm(c, "Delete "+what+"?", false, "delete "+what, "force"); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	prog := newProgress(c, len(chainIDs))
+	ignoreNotFound := c.Bool("ignore-not-found")
+	remove := func(i int) error {
+		prog.step("deleting chain %s...", chainIDs[i])
+		rerr := cli.removeSolanaChain(chainIDs[i])
+		if rerr != nil && ignoreNotFound && !errors.Is(rerr, context.Canceled) && cli.solanaChainAbsent(chainIDs[i]) {
+			return errChainAbsent
+		}
+		return rerr
+	}
+	if abort {
+		remove = abortOnFailure(remove)
+	}
+	errs := forEachConcurrently(concurrency, len(chainIDs), remove)
+	return cli.errorOut(reportChainDeletes(cli.stdout(), cli.stderr(), jsonOutput, chainIDs, errs))
+}
+
+// checkSolanaChainsExist checks that each of chainIDs exists before they are
+// deleted, writing a summary of each to w. A missing chain fails the check,
+// suggesting similar chain IDs in case of a typo, so that nothing is deleted.
+func (cli *Client) checkSolanaChainsExist(w io.Writer, chainIDs []string) error {
+	for _, id := range chainIDs {
+		chain, err := cli.getSolanaChain(id)
+		if err != nil {
+			return err
+		}
+		config, err := redactConfig(chain.Config, defaultSecretPatterns)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		state := "disabled"
+		if chain.Enabled {
+			state = "enabled"
+		}
+		fmt.Fprintf(w, "Chain %s (%s): %s\n", chain.ID, state, b)
+	}
+	return nil
+}
+
+// getSolanaChain returns the chain with chainID, or an error listing similar
+// chain IDs if it does not exist.
+func (cli *Client) getSolanaChain(chainID string) (chain *presenters.SolanaChainResource, err error) {
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, cli.solanaChainNotFound(chainID)
+	}
+	chain = &presenters.SolanaChainResource{}
+	if err = cli.deserializeAPIResponse(resp, chain, &jsonapi.Links{}); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+// ChainDeleteResult is the outcome of deleting a chain, as written by the
+// delete commands with --output json.
+type ChainDeleteResult struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+	Absent  bool   `json:"absent,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// reportChainDeletes writes the outcome of deleting each of chainIDs, whose
+// errors are errs, to w, and returns the combined error of the failed deletes.
+// Output is a sentence per chain, or with jsonOutput a ChainDeleteResult, in
+// an array if more than one chain was deleted. With jsonOutput interruptions
+// are written to stderr instead, keeping w valid JSON.
+func reportChainDeletes(w, stderr io.Writer, jsonOutput bool, chainIDs []string, errs []error) (err error) {
+	results := make([]ChainDeleteResult, len(chainIDs))
+	deleted, skipped := 0, 0
+	var canceled error
+	for i, rerr := range errs {
+		results[i].ID = chainIDs[i]
+		switch {
+		case rerr == nil:
+			deleted++
+			results[i].Deleted = true
+			if !jsonOutput {
+				fmt.Fprintf(w, "Chain %v deleted\n", chainIDs[i])
+			}
+		case errors.Is(rerr, errChainAbsent):
+			results[i].Absent = true
+			if !jsonOutput {
+				fmt.Fprintf(w, "Chain %v already absent\n", chainIDs[i])
+			}
+		case errors.Is(rerr, context.Canceled):
+			results[i].Error = rerr.Error()
+			if canceled == nil {
+				canceled = rerr
+			}
+		case errors.Is(rerr, errSkippedAfterFailure):
+			skipped++
+			results[i].Error = rerr.Error()
+		default:
+			results[i].Error = rerr.Error()
+			err = multierr.Append(err, errors.Wrapf(rerr, "failed to delete chain %s", chainIDs[i]))
+		}
+	}
+	if jsonOutput {
+		var v interface{} = results
+		if len(results) == 1 {
+			v = results[0]
+		}
+		b, merr := json.Marshal(v)
+		if merr != nil {
+			return multierr.Append(err, merr)
+		}
+		fmt.Fprintln(w, string(b))
+	}
+	if canceled != nil {
+		interrupted := w
+		if jsonOutput {
+			interrupted = stderr
+		}
+		fmt.Fprintf(interrupted, "Interrupted after deleting %d of %d chains\n", deleted, len(chainIDs))
+		return canceled
+	}
+	if skipped > 0 {
+		aborted := w
+		if jsonOutput {
+			aborted = stderr
+		}
+		fmt.Fprintf(aborted, "Aborted after the first failure, skipping %d of %d chains\n", skipped, len(chainIDs))
+	}
+	return err
+}
+
+// solanaChainAbsent reports whether the node has no chain with chainID. The
+// node fails to delete missing chains with a 500 rather than a 404, so this
+// is checked separately after a failed delete.
+func (cli *Client) solanaChainAbsent(chainID string) bool {
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID)
+	if err != nil {
+		return false
+	}
+	// Read the body to the end, so that the connection is reused by the
+	// following deletes
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusNotFound
+}
+
+func (cli *Client) removeSolanaChain(chainID string) (err error) {
+	resp, err := cli.HTTP.Delete("/v2/chains/solana/" + chainID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	_, err = cli.parseResponse(resp)
+	return err
+}
+
+// RenameSolanaChain changes the ID of a Solana chain. The node has no rename
+// endpoint, so the chain is recreated under the new ID with the same config,
+// its nodes are moved over, and the old chain is deleted. This is not atomic:
+// if a step fails, the steps before it are not undone.
+func (cli *Client) RenameSolanaChain(c *cli.Context) (err error) {
+	oldID := c.String("id")
+	if oldID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	newID, err := validateChainID(c.String("new-id"))
+	if err != nil {
+		return cli.errorOut(usageError(c, errors.Wrap(err, "invalid --new-id")))
+	}
+	if newID == oldID {
+		return cli.errorOut(errors.New("--new-id must differ from --id"))
+	}
+
+	fmt.Fprintln(cli.stderr(), "WARNING: renaming is not atomic. The chain is recreated under the new ID and its nodes are moved over, and a failed step is not rolled back.")
+
+	var chain presenters.SolanaChainResource
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+oldID, &chain); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to read chain %s", oldID))
+	}
+	var nodes []presenters.SolanaNodeResource
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana/"+oldID+"/nodes", &nodes); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to read the nodes of chain %s", oldID))
+	}
+
+	if err = cli.sendSolanaChain(cli.HTTP.Post, "/v2/chains/solana", map[string]interface{}{
+		"chainID": newID,
+		"config":  chain.Config,
+	}); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to create chain %s", newID))
+	}
+	fmt.Fprintf(cli.stdout(), "Created chain %s\n", newID)
+
+	if !chain.Enabled {
+		patch := func(uri string, body io.Reader) (*http.Response, error) { return cli.HTTP.Patch(uri, body) }
+		if err = cli.sendSolanaChain(patch, "/v2/chains/solana/"+newID, map[string]interface{}{
+			"enabled": false,
+			"config":  chain.Config,
+		}); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to disable chain %s", newID))
+		}
+		fmt.Fprintf(cli.stdout(), "Disabled chain %s\n", newID)
+	}
+
+	for _, node := range nodes {
+		// Node names are unique, so the old node must be deleted first
+		if err = cli.removeSolanaNode(node.ID); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to delete node %s (%s) of chain %s", node.Name, node.ID, oldID))
+		}
+		if err = cli.sendJSON(cli.HTTP.Post, "/v2/nodes/solana", db.NewNode{
+			Name:          node.Name,
+			SolanaChainID: newID,
+			SolanaURL:     node.SolanaURL,
+		}); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to recreate node %s (%s) on chain %s after deleting it", node.Name, node.SolanaURL, newID))
+		}
+		fmt.Fprintf(cli.stdout(), "Moved node %s to chain %s\n", node.Name, newID)
+	}
+
+	if err = cli.removeSolanaChain(oldID); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to delete chain %s", oldID))
+	}
+	fmt.Fprintf(cli.stdout(), "Deleted chain %s\n", oldID)
+	fmt.Fprintf(cli.stdout(), "Chain %s renamed to %s\n", oldID, newID)
+	return nil
+}
+
+// CopySolanaChain creates a Solana chain with the config of an existing one,
+// with the key=value arguments applied on top. Only the config is copied: the
+// new chain has no nodes, and is enabled.
+func (cli *Client) CopySolanaChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
+	fromID := c.String("from")
+	if fromID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing source chain ID [--from string]")))
+	}
+	toID, err := validateChainID(c.String("to"))
+	if err != nil {
+		return cli.errorOut(usageError(c, errors.Wrap(err, "invalid --to")))
+	}
+	if toID == fromID {
+		return cli.errorOut(usageError(c, errors.New("--to must differ from --from")))
+	}
+	params, err := parseTypedConfigParams(c.Args(), db.ChainCfg{})
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+
+	var chain presenters.SolanaChainResource
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+fromID, &chain); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "failed to read chain %s", fromID))
+	}
+	if err = cli.solanaChainNotExists(toID); err != nil {
+		return cli.errorOut(err)
+	}
+
+	config := chain.Config
+	rawUpdates, err := json.Marshal(params)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if err = json.Unmarshal(rawUpdates, &config); err != nil {
+		return cli.errorOut(err)
+	}
+
+	warnDeprecatedConfigKeys(cli.stderr(), "solana", config)
+
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"chainID": toID,
+		"config":  config,
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/chains/solana", bytes.NewBuffer(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	return cli.renderAPIResponse(resp, &SolanaChainPresenter{})
+}
+
+// solanaChainNotExists returns an error unless the node reports that it has
+// no chain with chainID.
+func (cli *Client) solanaChainNotExists(chainID string) (err error) {
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil
+	case http.StatusOK:
+		return errors.Errorf("chain %s already exists", chainID)
+	default:
+		_, err = cli.parseResponse(resp)
+		return errors.Wrapf(err, "failed to check whether chain %s exists", chainID)
+	}
+}
+
+// EnableSolanaChain enables a Solana chain, or with --all or --match every
+// matching chain.
+func (cli *Client) EnableSolanaChain(c *cli.Context) error {
+	return cli.toggleSolanaChains(c, true)
+}
+
+// DisableSolanaChain disables a Solana chain, or with --all or --match every
+// matching chain.
+func (cli *Client) DisableSolanaChain(c *cli.Context) error {
+	return cli.toggleSolanaChains(c, false)
+}
+
+// SetSolanaChainEnabled sets whether a Solana chain is enabled to --value,
+// for scripts computing the desired state rather than choosing between the
+// enable and disable commands. The rest of the chain is left as it is.
+func (cli *Client) SetSolanaChainEnabled(c *cli.Context) error {
+	if c.String("id") == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	if !c.IsSet("value") {
+		return cli.errorOut(usageError(c, errors.New("missing --value, must be true or false")))
+	}
+	enabled, err := parseBoolLiteral(c.String("value"))
+	if err != nil {
+		return cli.errorOut(usageError(c, errors.Wrap(err, "invalid --value")))
+	}
+	return cli.toggleSolanaChains(c, enabled)
+}
+
+// toggleSolanaChainConflicts are the conflicting flags of EnableSolanaChain
+// and DisableSolanaChain.
+var toggleSolanaChainConflicts = []flagConflict{
+	{"id", "all"}, {"id", "match"}, {"id", "ids-stdin"},
+	{"all", "match"}, {"all", "ids-stdin"},
+	{"match", "ids-stdin"},
+}
+
+// toggleSolanaChains sets the chain selected on c to enabled and renders it,
+// or with --all, --match or --ids-stdin, after confirmation, sets every
+// selected chain and reports the outcome for each, stopping at the first
+// failure with --on-error=abort.
+func (cli *Client) toggleSolanaChains(c *cli.Context, enabled bool) (err error) {
+	if err = checkFlagConflicts(c, toggleSolanaChainConflicts...); err != nil {
+		return cli.errorOut(err)
+	}
+	cli.setChainRenderOpts(c)
+	action, state := "Disable", "disabled"
+	if enabled {
+		action, state = "Enable", "enabled"
+	}
+	chainID, pattern := c.String("id"), c.String("match")
+	if chainID != "" {
+		var chain *SolanaChainPresenter
+		if chain, _, err = cli.setSolanaChainEnabled(chainID, enabled); err != nil {
+			return cli.errorOut(err)
+		}
+		return cli.errorOut(cli.Render(chain))
+	}
+	if !c.Bool("all") && pattern == "" && !c.Bool("ids-stdin") {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string | --all | --match pattern | --ids-stdin]")))
+	}
+
+	var chainIDs []string
+	if c.Bool("ids-stdin") {
+		if chainIDs, err = readChainIDsJSON(os.Stdin); err != nil {
+			return cli.errorOut(usageError(c, err))
+		}
+	} else if pattern != "" {
+		if chainIDs, err = cli.matchSolanaChains(cli.stderr(), pattern); err != nil {
+			return cli.errorOut(err)
+		}
+	} else {
+		var chains []presenters.SolanaChainResource
+		if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+			return cli.errorOut(err)
+		}
+		if len(chains) == 0 {
+			return cli.errorOut(errors.New("there are no chains"))
+		}
+		for _, chain := range chains {
+			chainIDs = append(chainIDs, chain.ID)
+		}
+	}
+	abort, err := onErrorFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if err = cli.confirmChainMatches(c, action, chainIDs); err != nil {
+		return cli.errorOut(err)
+	}
+
+	failed := 0
+	for i, id := range chainIDs {
+		_, changed, terr := cli.setSolanaChainEnabled(id, enabled)
+		switch {
+		case terr != nil:
+			failed++
+			fmt.Fprintf(cli.stdout(), "Chain %s failed: %v\n", id, terr)
+		case changed:
+			fmt.Fprintf(cli.stdout(), "Chain %s %s\n", id, state)
+		default:
+			fmt.Fprintf(cli.stdout(), "Chain %s already %s\n", id, state)
+		}
+		if terr != nil && abort {
+			if skipped := len(chainIDs) - i - 1; skipped > 0 {
+				fmt.Fprintf(cli.stdout(), "Aborted after the first failure, skipping %d of %d chains\n", skipped, len(chainIDs))
+			}
+			break
+		}
+	}
+	if failed > 0 {
+		return cli.errorOut(errors.Errorf("failed to %s %d of %d chains", strings.ToLower(action), failed, len(chainIDs)))
+	}
+	return nil
+}
+
+// TouchSolanaChain re-sends the current state of a Solana chain unchanged, so
+// that the node bumps its updatedAt. It is a testing utility, e.g. for
+// monitoring which alerts on config changes, and should not be needed in
+// production.
+func (cli *Client) TouchSolanaChain(c *cli.Context) (err error) {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	var current SolanaChainPresenter
+	if _, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &current); err != nil {
+		return cli.errorOut(err)
+	}
+	if cli.CheckMode {
+		fmt.Fprintf(cli.stdout(), "Would touch chain %s\n", chainID)
+		return nil
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": current.Enabled,
+		"config":  current.Config,
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	updated, err := cli.patchSolanaChain(chainID, body)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Fprintf(cli.stdout(), "Touched chain %s, updated at %s (was %s)\n", chainID, formatTimestamp(updated.UpdatedAt), formatTimestamp(current.UpdatedAt))
+	return nil
+}
+
+// setSolanaChainEnabled sets the chain with chainID to enabled, leaving its
+// config untouched, and returns the chain. Chains already in that state are
+// not updated, and changed is false. A chain which does not exist is reported
+// with the similar chain IDs, as by show.
+func (cli *Client) setSolanaChainEnabled(chainID string, enabled bool) (chain *SolanaChainPresenter, changed bool, err error) {
+	resource, err := cli.getSolanaChain(chainID)
+	if err != nil {
+		return nil, false, err
+	}
+	current := SolanaChainPresenter{*resource}
+	if current.Enabled == enabled {
+		return &current, false, nil
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": enabled,
+		"config":  current.Config,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	updated, err := cli.patchSolanaChain(chainID, body)
+	if err != nil {
+		return nil, false, err
+	}
+	return &updated, true, nil
+}
+
+// patchSolanaChain sends body as the new state of the chain with chainID and
+// returns the updated chain. Nodes answering with 204 No Content have no
+// body to decode, so the chain is fetched again instead.
+func (cli *Client) patchSolanaChain(chainID string, body []byte) (updated SolanaChainPresenter, err error) {
+	resp, err := cli.HTTP.Patch("/v2/chains/solana/"+chainID, bytes.NewReader(body))
+	if err != nil {
+		return updated, err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	if resp.StatusCode == http.StatusNoContent {
+		_, err = cli.getResource(cli.HTTP, "/v2/chains/solana/"+chainID, &updated)
+		return updated, err
+	}
+	err = cli.deserializeAPIResponse(resp, &updated, &jsonapi.Links{})
+	return updated, err
+}
+
+// sendJSON sends params as JSON to requestURI with send, discarding the
+// response body.
+func (cli *Client) sendJSON(send func(string, io.Reader) (*http.Response, error), requestURI string, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return cli.sendBody(send, requestURI, body)
+}
+
+// sendSolanaChain sends attributes to requestURI with send as the body of a
+// Solana chain request, shaped by encodeChainBody, discarding the response
+// body.
+func (cli *Client) sendSolanaChain(send func(string, io.Reader) (*http.Response, error), requestURI string, attributes map[string]interface{}) error {
+	body, err := cli.encodeChainBody("solana_chain", attributes)
+	if err != nil {
+		return err
+	}
+	return cli.sendBody(send, requestURI, body)
+}
+
+// sendBody sends body to requestURI with send, discarding the response body.
+func (cli *Client) sendBody(send func(string, io.Reader) (*http.Response, error), requestURI string, body []byte) (err error) {
+	resp, err := send(requestURI, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	_, err = cli.parseResponse(resp)
+	return err
+}
+
+// DoctorSolanaChains runs diagnostics for the Solana chain commands: that the
+// node is reachable, the session is valid, the node's build matches the CLI's,
+// and that every chain has at least one node. It exits non-zero if any check
+// fails.
+func (cli *Client) DoctorSolanaChains(c *cli.Context) (err error) {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var p DoctorPresenter
+	cli.runSolanaDoctor(&p, static.Version, static.Sha)
+	if err = r.Render(&p); err != nil {
+		return cli.errorOut(err)
+	}
+	if failed := p.Failed(); failed > 0 {
+		return cli.errorOut(errors.Errorf("%d of %d checks failed", failed, len(p.Checks)))
+	}
+	return nil
+}
+
+func (cli *Client) runSolanaDoctor(p *DoctorPresenter, cliVersion, cliSha string) {
+	resp, err := cli.HTTP.Get("/health")
+	if err != nil {
+		p.fail("connectivity", err.Error(), "check that the node is running and that CLIENT_NODE_URL points to it")
+		p.skip("authentication", "node unreachable")
+		return
+	}
+	_ = resp.Body.Close()
+	p.pass("connectivity", "node is reachable")
+
+	resp, err = cli.HTTP.Get("/v2/build_info")
+	if err != nil {
+		p.fail("authentication", err.Error(), "check the connection to the node")
+		return
+	}
+	b, err := parseResponse(resp)
+	_ = resp.Body.Close()
+	if errors.Is(err, errUnauthorized) {
+		p.fail("authentication", "session is missing or expired", "log in with 'chainlink admin login', or set "+SessionCookieEnv)
+		p.skip("version", "not authenticated")
+		p.skip("chains", "not authenticated")
+		return
+	} else if err != nil {
+		p.fail("authentication", fmt.Sprintf("%s: %s", err, b), "check the node logs")
+		return
+	}
+	p.pass("authentication", "session is valid")
+
+	var info map[string]string
+	if err = json.Unmarshal(b, &info); err != nil {
+		p.fail("version", "invalid build info: "+err.Error(), "check that CLIENT_NODE_URL points to a Chainlink node")
+	} else if remote := info["version"] + "@" + info["commitSHA"]; remote != cliVersion+"@"+cliSha {
+		p.fail("version", fmt.Sprintf("node build %s differs from CLI build %s@%s", remote, cliVersion, cliSha), "use a CLI of the same version as the node")
+	} else {
+		p.pass("version", "node and CLI builds match ("+remote+")")
+	}
+
+	var chains []presenters.SolanaChainResource
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+		p.fail("chains", err.Error(), "check that SOLANA_ENABLED=true is set on the node")
+		return
+	}
+	p.pass("chains", fmt.Sprintf("%d Solana chain(s)", len(chains)))
+
+	for _, chain := range chains {
+		name := fmt.Sprintf("chain %s nodes", chain.ID)
+		var nodes []presenters.SolanaNodeResource
+		if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana/"+chain.ID+"/nodes", &nodes); err != nil {
+			p.fail(name, err.Error(), "check the node logs")
+		} else if len(nodes) == 0 {
+			p.fail(name, "no nodes", fmt.Sprintf("add one with 'chainlink nodes solana create --chain-id %s'", chain.ID))
+		} else {
+			p.pass(name, fmt.Sprintf("%d node(s)", len(nodes)))
+		}
+	}
+}
+
+// LintSolanaChainConfig checks a Solana chain config file for unknown fields
+// and invalid values, without talking to a node.
+func (cli *Client) LintSolanaChainConfig(c *cli.Context) error {
+	return cli.errorOut(lintChainConfigFile(c, cli.stdout(), db.ChainCfg{}, deprecatedConfigKeys["solana"]))
+}
+
+// solanaConfigPresets are the named base configs of Solana chains, which
+// config-template render expands with overrides.
+var solanaConfigPresets = map[string]map[string]interface{}{
+	"mainnet-base": {
+		"BalancePollPeriod": "5s",
+		"Commitment":        "finalized",
+		"ConfirmPollPeriod": "500ms",
+		"SkipPreflight":     false,
+		"TxTimeout":         "1m0s",
+	},
+	"devnet": {
+		"Commitment":    "confirmed",
+		"SkipPreflight": true,
+		"TxTimeout":     "30s",
+	},
+	"localnet": {
+		"Commitment":        "processed",
+		"ConfirmPollPeriod": "100ms",
+		"SkipPreflight":     true,
+		"TxTimeout":         "10s",
+	},
+}
+
+// RenderSolanaChainConfigTemplate prints the config of the preset of
+// --preset, with the key=value overrides of the arguments applied, without
+// talking to a node or creating a chain. The result is linted like lint, and
+// errors fail the command.
+func (cli *Client) RenderSolanaChainConfigTemplate(c *cli.Context) error {
+	name := c.String("preset")
+	if name == "" {
+		names := make([]string, 0, len(solanaConfigPresets))
+		for n := range solanaConfigPresets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return cli.errorOut(usageError(c, errors.Errorf("must pass --preset, options: %s", strings.Join(names, ", "))))
+	}
+	format := c.String("output")
+	switch format {
+	case "", "json", "yaml":
+	default:
+		return cli.errorOut(usageError(c, errors.Errorf("unsupported output format '%s', options: json, yaml", format)))
+	}
+	config, err := renderConfigPreset(solanaConfigPresets, name, c.Args(), db.ChainCfg{})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var errs int
+	for _, issue := range lintChainConfig(b, db.ChainCfg{}, deprecatedConfigKeys["solana"]) {
+		if !issue.warning {
+			errs++
+		}
+		fmt.Fprintf(cli.stderr(), "%s: %s\n", name, issue)
+	}
+	if errs > 0 {
+		return cli.errorOut(errors.Errorf("%s: %d error(s)", name, errs))
+	}
+	if format == "yaml" {
+		return cli.errorOut(writeConfigYAML(cli.stdout(), config))
+	}
+	fmt.Fprintln(cli.stdout(), string(b))
+	return nil
+}
+
+// rawSolanaChain is a Solana chain with its config as received from the node,
+// keeping any fields unknown to the CLI.
+type rawSolanaChain struct {
+	presenters.SolanaChainResource
+	Config json.RawMessage `json:"config"`
+}
+
+// CheckSolanaChainRoundTrip reports the config fields of the chain with -id
+// which change when the CLI decodes and encodes the config returned by the
+// node, as configure does, such as unknown fields being dropped or values
+// being coerced. It fails if any field does not round-trip.
+func (cli *Client) CheckSolanaChainRoundTrip(c *cli.Context) (err error) {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	resp, err := cli.HTTP.Get("/v2/chains/solana/" + chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return cli.errorOut(cli.solanaChainNotFound(chainID))
+	}
+	var chain rawSolanaChain
+	if err = cli.deserializeAPIResponse(resp, &chain, &jsonapi.Links{}); err != nil {
+		return err
+	}
+	issues, err := roundTripIssues(chain.Config, db.ChainCfg{})
+	if err != nil {
+		return cli.errorOut(errors.Wrapf(err, "chain %s", chainID))
+	}
+	if err = r.Render(&ConfigRoundTrip{ID: chainID, Issues: issues}); err != nil {
+		return cli.errorOut(err)
+	}
+	if len(issues) > 0 {
+		return cli.errorOut(errors.Errorf("the config of chain %s does not round-trip: %d issue(s)", chainID, len(issues)))
+	}
+	return nil
+}
+
+// ValidateAllSolanaChains lints the config of every Solana chain of the node,
+// like lint, to catch unknown and deprecated fields set by older CLIs or
+// directly through the API. It fails if any chain has errors, or with
+// --strict, warnings.
+func (cli *Client) ValidateAllSolanaChains(c *cli.Context) error {
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	var chains []rawSolanaChain
+	if err = cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+		return cli.errorOut(err)
+	}
+	configs := make(map[string]json.RawMessage, len(chains))
+	for _, chain := range chains {
+		configs[chain.ID] = chain.Config
+	}
+	issues, failed := validateChainConfigs(configs, db.ChainCfg{}, deprecatedConfigKeys["solana"], c.Bool("strict"))
+	if err = r.Render(&issues); err != nil {
+		return cli.errorOut(err)
+	}
+	if len(failed) > 0 {
+		return cli.errorOut(errors.Errorf("%d of %d chain(s) have invalid configs: %s", len(failed), len(chains), strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+// ExplainSolanaChainConfigField prints the type, default and documentation of
+// a Solana chain config field, without talking to a node.
+func (cli *Client) ExplainSolanaChainConfigField(c *cli.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(usageError(c, errors.New("must pass the name of the config field to explain")))
+	}
+	defaults := normalizeSolanaChainConfig(ioutil.Discard, db.ChainCfg{}, true)
+	return cli.errorOut(explainConfigField(cli.stdout(), db.ChainCfg{}, defaults, configFieldDocs["solana"], c.Args().First()))
+}
+
+// SolanaChainConfigSchema prints the JSON Schema of Solana chain configs, of
+// only the comma-separated top-level fields of --only-fields if set.
+func (cli *Client) SolanaChainConfigSchema(c *cli.Context) error {
+	var only []string
+	if fields := c.String("only-fields"); fields != "" {
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				only = append(only, f)
+			}
+		}
+	}
+	schema, err := configSchema(db.ChainCfg{}, configFieldDocs["solana"], only)
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Fprintln(cli.stdout(), string(b))
+	return nil
+}
+
+// NormalizeSolanaChainConfig prints the canonical form of a Solana chain
+// config file, as the node would store and apply it. The node has no endpoint
+// to normalize a config without persisting it, so this is done client-side:
+// keys take their canonical names, unknown keys are dropped with a warning,
+// durations are formatted, and unset fields are filled with the
+// chainlink-solana defaults unless --no-defaults is set.
+func (cli *Client) NormalizeSolanaChainConfig(c *cli.Context) error {
+	if !c.Args().Present() {
+		return cli.errorOut(usageError(c, errors.New("must pass the path of the config file to normalize")))
+	}
+	path := c.Args().First()
+	raw, err := chainConfigFile(path, c.Bool("jsonc"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	var errs int
+	for _, issue := range lintChainConfig(raw, db.ChainCfg{}, deprecatedConfigKeys["solana"]) {
+		if !issue.warning {
+			errs++
+		}
+		fmt.Fprintf(cli.stderr(), "%s: %s\n", path, issue)
+	}
+	if errs > 0 {
+		return cli.errorOut(errors.Errorf("%s: %d error(s)", path, errs))
+	}
+
+	var config db.ChainCfg
+	if err = json.Unmarshal(raw, &config); err != nil {
+		return cli.errorOut(errors.Wrapf(err, "invalid config in '%s'", path))
+	}
+	config = normalizeSolanaChainConfig(cli.stderr(), config, !c.Bool("no-defaults"))
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Fprintln(cli.stdout(), string(b))
+	return nil
+}
+
+// normalizeSolanaChainConfig returns config with its unset fields filled with
+// the values the node would use, if fill is set. Values the node would ignore
+// in favor of a default are reported to w.
+func normalizeSolanaChainConfig(w io.Writer, config db.ChainCfg, fill bool) db.ChainCfg {
+	effective := solanacfg.NewConfig(config, logger.NullLogger)
+	if config.Commitment.Valid && config.Commitment.String != string(effective.Commitment()) {
+		fmt.Fprintf(w, "WARNING: Commitment %q is not supported, the node uses %q instead\n", config.Commitment.String, effective.Commitment())
+	}
+	if !fill {
+		return config
+	}
+
+	duration := func(field **models.Duration, d time.Duration) {
+		if *field == nil {
+			md := models.MustMakeDuration(d)
+			*field = &md
+		}
+	}
+	duration(&config.BalancePollPeriod, effective.BalancePollPeriod())
+	duration(&config.ConfirmPollPeriod, effective.ConfirmPollPeriod())
+	duration(&config.OCR2CachePollPeriod, effective.OCR2CachePollPeriod())
+	duration(&config.OCR2CacheTTL, effective.OCR2CacheTTL())
+	duration(&config.TxTimeout, effective.TxTimeout())
+	if !config.SkipPreflight.Valid {
+		config.SkipPreflight = null.BoolFrom(effective.SkipPreflight())
+	}
+	if !config.Commitment.Valid {
+		config.Commitment = null.StringFrom(string(effective.Commitment()))
+	}
+	return config
+}
+
+// ConfigureSolanaChain configures an existing Solana chain, or with --match
+// every chain whose ID matches a glob pattern. With --interactive, each
+// changed field is confirmed before the chain is updated. With --from-show,
+// the config of another chain is copied onto them, to keep chains in sync.
+func (cli *Client) ConfigureSolanaChain(c *cli.Context) (err error) {
+	if err = checkFlagConflicts(c, configureChainConflicts...); err != nil {
+		return cli.errorOut(err)
+	}
+	cli.setChainRenderOpts(c)
+	chainIDs, pattern := chainIDsFlag(c), c.String("match")
+	if len(chainIDs) == 0 && pattern == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID (usage: chainlink solana chains configure [-id string... | --match pattern] [key1=value1 key2=value2 ...])")))
+	}
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	warn := newCommandWarnings(c, cli.stderr())
+	defer warn.flush()
+	r = warn.renderer(r)
+	// Collect the warnings of the node along with those of the command
+	defer func(w io.Writer) { cli.ServerWarnings = w }(cli.ServerWarnings)
+	cli.ServerWarnings = warn
+
+	args := []string(c.Args())
+	if c.Bool("stdin") {
+		var lines []string
+		lines, err = readKeyValueLines(os.Stdin)
+		if err != nil {
+			return cli.errorOut(errors.Wrap(err, "failed to read stdin"))
+		}
+		if len(lines) == 0 {
+			return cli.errorOut(errors.New("--stdin was set but no key=value pairs were read from stdin"))
+		}
+		// Arguments are applied last, so they override values from stdin
+		args = append(lines, args...)
+	}
+	configFile, resets, source := c.String("config-file"), c.StringSlice("reset-to-default"), c.String("from-show")
+	if len(args) == 0 && configFile == "" && len(resets) == 0 && source == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass in at least one chain configuration parameters (usage: chainlink solana chains configure [-id string] [key1=value1 key2=value2 ...])")))
+	}
+	var fileUpdates json.RawMessage
+	if configFile != "" {
+		if fileUpdates, err = chainConfigFile(configFile, c.Bool("jsonc")); err != nil {
+			return cli.errorOut(err)
+		}
+		if c.Bool("strict-json") {
+			if err = checkDuplicateKeys(fileUpdates); err != nil {
+				return cli.errorOut(errors.Wrapf(err, "config file '%s'", configFile))
+			}
+		}
+	}
+	if source != "" {
+		// The whole config of the source chain, nulls included, replaces that
+		// of the targets, which arguments override
+		chain, serr := cli.getSolanaChain(source)
+		if serr != nil {
+			return cli.errorOut(errors.Wrap(serr, "--from-show"))
+		}
+		if fileUpdates, err = json.Marshal(chain.Config); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	// Parse new key-value pairs
+	params, err := parseTypedConfigParams(args, db.ChainCfg{})
+	if err != nil {
+		return cli.errorOut(usageError(c, err))
+	}
+	if len(resets) > 0 {
+		// Fields reset to their defaults are set like arguments, to the
+		// values the node falls back to
+		defaults, derr := configFieldDefaults(resets, db.ChainCfg{}, normalizeSolanaChainConfig(ioutil.Discard, db.ChainCfg{}, true))
+		if derr != nil {
+			return cli.errorOut(usageError(c, errors.Wrap(derr, "invalid --reset-to-default")))
+		}
+		keys := make([]string, 0, len(defaults))
+		for key := range defaults {
+			if _, ok := params[key]; ok {
+				return cli.errorOut(usageError(c, errors.Errorf("config field %s cannot be both set and reset to its default", key)))
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			params[key] = defaults[key]
+			fmt.Fprintf(warn, "Resetting %s to its default %s\n", key, formatConfigValue(defaults[key]))
+		}
+	}
+	// Combine new values with the existing config
+	// (serialize to a partial JSON map, deserialize to the old config struct)
+	rawUpdates, err := json.Marshal(params)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("interactive") && !cli.assumeYes(c, "yes") && !cli.confirmationPrompter().IsTerminal() {
+		return cli.errorOut(usageError(c, errors.New("--interactive requires a terminal; pass --yes to apply all changes without confirmation")))
+	}
+	if c.Bool("check-version") {
+		cli.warnChainConfigCompatibility(warn, static.Version, static.Sha)
+	}
+	if !c.Bool("no-secret-warnings") {
+		for _, updates := range []json.RawMessage{fileUpdates, rawUpdates} {
+			if updates != nil {
+				warnSecretConfigKeys(warn, updates, secretPatternsFlag(c))
+			}
+		}
+	}
+
+	if pattern != "" {
+		if chainIDs, err = cli.matchSolanaChains(cli.stderr(), pattern); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	if (pattern != "" || len(chainIDs) > 1) && !cli.assumeYes(c, "yes", "force") {
+		// Show the blast radius of bulk changes before applying any
+		changes := args
+		if configFile != "" {
+			changes = append([]string{fmt.Sprintf("the config in '%s'", configFile)}, args...)
+		}
+		if source != "" {
+			changes = append([]string{fmt.Sprintf("the config of chain %s", source)}, args...)
+		}
+		for _, key := range resets {
+			changes = append(changes, fmt.Sprintf("reset %s to its default", key))
+		}
+		if err = confirmBulkConfigure(cli.confirmationPrompter(), cli.stdout(), changes, chainIDs); err != nil {
+			return cli.errorOut(err)
+		}
+	}
+	dump, err := openRequestDump(c, cli.stdout())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := dump.close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	for _, id := range chainIDs {
+		if err = cli.configureSolanaChain(c, r, warn, dump, id, fileUpdates, rawUpdates); err != nil {
+			if len(chainIDs) > 1 {
+				err = errors.Wrapf(err, "failed to configure chain %s", id)
+			}
+			return cli.errorOut(err)
+		}
+	}
+	return nil
+}
+
+// configureSolanaChain applies the partial config fileUpdates, then
+// rawUpdates, to the chain with chainID, and renders the result with r, or
+// with --from-show the changes, before they are applied. Warnings about the
+// new config are written to warn, and the request body to dump.
+func (cli *Client) configureSolanaChain(c *cli.Context, r Renderer, warn io.Writer, dump *requestDump, chainID string, fileUpdates, rawUpdates json.RawMessage) (err error) {
+	// Fetch existing config
+	resp, err := cli.HTTP.Get(fmt.Sprintf("/v2/chains/solana/%s", chainID))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return cli.solanaChainNotFound(chainID)
+	}
+	var chain presenters.SolanaChainResource
+	if err = cli.deserializeAPIResponse(resp, &chain, &jsonapi.Links{}); err != nil {
+		return err
+	}
+	// Apply the updates to a copy, as decoding into the duration fields, which
+	// are pointers, would otherwise change chain.Config as well
+	original, err := json.Marshal(chain.Config)
+	if err != nil {
+		return err
+	}
+	var config db.ChainCfg
+	if err = json.Unmarshal(original, &config); err != nil {
+		return err
+	}
+
+	// Apply the partial config from --config-file, which arguments override
+	if fileUpdates != nil {
+		if err = json.Unmarshal(fileUpdates, &config); err != nil {
+			return errors.Wrapf(err, "invalid config in '%s'", c.String("config-file"))
+		}
+	}
+	// Resolve references to other fields against the config as it stands
+	if rawUpdates, err = interpolateConfigUpdates(rawUpdates, config); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(rawUpdates, &config); err != nil {
+		return err
+	}
+
+	warnDeprecatedConfigKeys(warn, "solana", config)
+
+	if c.Bool("interactive") && !cli.assumeYes(c, "yes") {
+		var approved interface{}
+		var changed int
+		fmt.Fprintf(cli.stdout(), "Changes to chain %s:\n", chainID)
+		if approved, changed, err = confirmConfigChanges(cli.confirmationPrompter(), chain.Config, config); err != nil {
+			return err
+		}
+		if changed == 0 {
+			fmt.Fprintf(cli.stdout(), "No changes applied to chain %s\n", chainID)
+			return nil
+		}
+		var b []byte
+		if b, err = json.Marshal(approved); err != nil {
+			return err
+		}
+		config = db.ChainCfg{}
+		if err = json.Unmarshal(b, &config); err != nil {
+			return err
+		}
+	}
+
+	// Send the new config, without the fields unset by the arguments
+	sent, err := withoutUnsetConfigKeys(config, rawUpdates)
+	if err != nil {
+		return err
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": chain.Enabled,
+		"config":  sent,
+	})
+	if err != nil {
+		return err
+	}
+	if err = dump.write(body); err != nil {
+		return err
+	}
+	fromShow := c.String("from-show") != ""
+	if fromShow && !c.Bool("no-render") {
+		// Show what the copy changes on the target, rather than the whole
+		// chain, before applying it
+		var fields []ConfigFieldDiff
+		if fields, err = diffConfigs(chain.Config, config); err != nil {
+			return err
+		}
+		if err = r.Render(&ChainConfigDiff{ID: chainID, Fields: fields}); err != nil {
+			return err
+		}
+	}
+	if c.Bool("dry-run") {
+		return nil
+	}
+	if cli.CheckMode {
+		var fields []ConfigFieldDiff
+		if fields, err = diffConfigs(chain.Config, config); err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			fmt.Fprintf(cli.stdout(), "Would leave chain %s unchanged\n", chainID)
+			return nil
+		}
+		keys := make([]string, len(fields))
+		for i, f := range fields {
+			keys[i] = f.Key
+		}
+		fmt.Fprintf(cli.stdout(), "Would update chain %s: %s\n", chainID, strings.Join(keys, ", "))
+		return nil
+	}
+	updated, err := cli.patchSolanaChain(chainID, body)
+	if err != nil {
+		return err
+	}
+	if c.Bool("assert-applied") {
+		var unapplied []string
+		if unapplied, err = unappliedConfigFields(chain.Config, config, updated.Config); err != nil {
+			return err
+		}
+		if len(unapplied) > 0 {
+			return errors.Errorf("chain %s was updated, but the node did not apply: %s", chainID, strings.Join(unapplied, ", "))
+		}
+	}
+	if c.Bool("no-render") || fromShow {
+		return nil
+	}
+	if c.Bool("changed-only") {
+		// Only show the fields which the server reports as changed
+		var fields []ConfigFieldDiff
+		if fields, err = diffConfigs(chain.Config, updated.Config); err != nil {
+			return err
+		}
+		return r.Render(&ChainConfigDiff{ID: chainID, Fields: fields})
+	}
+	return r.Render(&updated)
+}
+
+// SolanaChainConfigHash prints the SHA-256 of the canonical JSON config of the
+// chain with -id, or with --all, the ID and hash of every chain, so that
+// scripts can detect config drift without diffing whole configs.
+func (cli *Client) SolanaChainConfigHash(c *cli.Context) error {
+	if err := checkFlagConflicts(c, flagConflict{"id", "all"}); err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("all") {
+		var chains SolanaChainPresenters
+		if err := cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+			return cli.errorOut(err)
+		}
+		for _, chain := range chains {
+			digest, err := configDigest(chain.Config)
+			if err != nil {
+				return cli.errorOut(errors.Wrapf(err, "failed to hash the config of chain %s", chain.ID))
+			}
+			fmt.Fprintf(cli.stdout(), "%s  %s\n", digest, chain.ID)
+		}
+		return nil
+	}
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string | --all]")))
+	}
+	chain, err := cli.getSolanaChain(chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	digest, err := configDigest(chain.Config)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Fprintln(cli.stdout(), digest)
+	return nil
+}
+
+// UnsetSolanaChainConfig removes the config fields at the dotted paths of the
+// arguments from the Solana chain with -id, and renders the updated chain.
+// Fields which are not set fail the command, unless --ignore-missing is set.
+func (cli *Client) UnsetSolanaChainConfig(c *cli.Context) error {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID (usage: chainlink solana chains config-unset -id string key1 [key2 ...])")))
+	}
+	if !c.Args().Present() {
+		return cli.errorOut(usageError(c, errors.New("must pass at least one config field to unset (usage: chainlink solana chains config-unset -id string key1 [key2 ...])")))
+	}
+	cli.setChainRenderOpts(c)
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	chain, err := cli.getSolanaChain(chainID)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	config, err := unsetConfigPaths(chain.Config, c.Args(), c.Bool("ignore-missing"))
+	if err != nil {
+		return cli.errorOut(errors.Wrapf(err, "chain %s", chainID))
+	}
+	if cli.CheckMode {
+		fmt.Fprintf(cli.stdout(), "Would unset %s on chain %s\n", strings.Join(c.Args(), ", "), chainID)
+		return nil
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": chain.Enabled,
+		"config":  config,
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	updated, err := cli.patchSolanaChain(chainID, body)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(r.Render(&updated))
+}
+
+// solanaChainPatch is the planned update of a chain by config-set-many.
+type solanaChainPatch struct {
+	chain  *presenters.SolanaChainResource
+	config db.ChainCfg
+	fields []ConfigFieldDiff
+}
+
+// ConfigSetManySolanaChains deep-merges the JSON object of --patch into the
+// config of every Solana chain matching the glob pattern of --match. The
+// changes to each chain are shown and confirmed before any is applied, then
+// the changed chains are updated, with the outcome reported per chain. With
+// --on-error=abort, the chains remaining after a failure are skipped.
+func (cli *Client) ConfigSetManySolanaChains(c *cli.Context) error {
+	path, pattern := c.String("patch"), c.String("match")
+	if path == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass the file of the config patch [--patch FILE]")))
+	}
+	if pattern == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass the chains to patch [--match pattern]")))
+	}
+	concurrency, err := concurrencyFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	abort, err := onErrorFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	raw, err := chainConfigFile(path, c.Bool("jsonc"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	patch, ok := decodeConfigPatch(raw)
+	if !ok {
+		return cli.errorOut(errors.Errorf("config patch '%s' must contain a JSON object", path))
+	}
+	chainIDs, err := cli.matchSolanaChains(cli.stderr(), pattern)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	patches := make([]solanaChainPatch, len(chainIDs))
+	changed := 0
+	for i, id := range chainIDs {
+		if patches[i], err = cli.planSolanaChainPatch(id, patch); err != nil {
+			return cli.errorOut(errors.Wrapf(err, "failed to patch chain %s", id))
+		}
+		if err = cli.Render(&ChainConfigDiff{ID: id, Fields: patches[i].fields}); err != nil {
+			return cli.errorOut(err)
+		}
+		if len(patches[i].fields) > 0 {
+			changed++
+		}
+	}
+	if changed == 0 {
+		fmt.Fprintln(cli.stdout(), "No chains to update")
+		return nil
+	}
+	if cli.CheckMode {
+		fmt.Fprintf(cli.stdout(), "Would update %d of %d chain(s)\n", changed, len(chainIDs))
+		return nil
+	}
+	what := fmt.Sprintf("update %d chain(s)", changed)
+	if err = cli.confirm(c, fmt.Sprintf("Apply the changes above to %d chain(s)?", changed), false, what, "yes"); err != nil {
+		return cli.errorOut(err)
+	}
+
+	apply := func(i int) error {
+		p := patches[i]
+		if len(p.fields) == 0 {
+			return nil
+		}
+		body, berr := cli.encodeChainBody("solana_chain", map[string]interface{}{
+			"enabled": p.chain.Enabled,
+			"config":  p.config,
+		})
+		if berr != nil {
+			return berr
+		}
+		_, berr = cli.patchSolanaChain(p.chain.ID, body)
+		return berr
+	}
+	if abort {
+		apply = abortOnFailure(apply)
+	}
+	errs := forEachConcurrently(concurrency, len(patches), apply)
+	var failed error
+	for i, perr := range errs {
+		switch {
+		case errors.Is(perr, errSkippedAfterFailure):
+			fmt.Fprintf(cli.stdout(), "Chain %s skipped after an earlier failure\n", chainIDs[i])
+		case perr != nil:
+			failed = multierr.Append(failed, errors.Wrapf(perr, "failed to patch chain %s", chainIDs[i]))
+		case len(patches[i].fields) == 0:
+			fmt.Fprintf(cli.stdout(), "Chain %s unchanged\n", chainIDs[i])
+		default:
+			fmt.Fprintf(cli.stdout(), "Chain %s updated\n", chainIDs[i])
+		}
+	}
+	return cli.errorOut(failed)
+}
+
+// decodeConfigPatch decodes the JSON object of a config patch, keeping numbers
+// as they are written. It reports false if raw is not a JSON object.
+func decodeConfigPatch(raw []byte) (map[string]interface{}, bool) {
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var patch map[string]interface{}
+	if err := d.Decode(&patch); err != nil || patch == nil {
+		return nil, false
+	}
+	return patch, true
+}
+
+// ApplySolanaChainPatch deep-merges the JSON Merge Patch read from stdin with
+// --patch-from-stdin into the config of the Solana chain with -id, for
+// pipelines which compute the patch. Null fields of the patch unset the
+// field. The changes are shown before being applied, and only shown with
+// --dry-run.
+func (cli *Client) ApplySolanaChainPatch(c *cli.Context) error {
+	return cli.applySolanaChainPatch(c, os.Stdin)
+}
+
+func (cli *Client) applySolanaChainPatch(c *cli.Context, stdin io.Reader) error {
+	chainID := c.String("id")
+	if chainID == "" {
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
+	}
+	if !c.Bool("patch-from-stdin") {
+		return cli.errorOut(usageError(c, errors.New("must pass the config patch [--patch-from-stdin]")))
+	}
+	raw, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "failed to read stdin"))
+	}
+	patch, ok := decodeConfigPatch(raw)
+	if !ok {
+		return cli.errorOut(errors.New("config patch from stdin must contain a JSON object"))
+	}
+	p, err := cli.planSolanaChainPatch(chainID, patch)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if err = cli.Render(&ChainConfigDiff{ID: chainID, Fields: p.fields}); err != nil {
+		return cli.errorOut(err)
+	}
+	switch {
+	case len(p.fields) == 0:
+		fmt.Fprintf(cli.stdout(), "Chain %s unchanged\n", chainID)
+		return nil
+	case c.Bool("dry-run") || cli.CheckMode:
+		fmt.Fprintf(cli.stdout(), "Would update chain %s\n", chainID)
+		return nil
+	}
+	body, err := cli.encodeChainBody("solana_chain", map[string]interface{}{
+		"enabled": p.chain.Enabled,
+		"config":  p.config,
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if _, err = cli.patchSolanaChain(chainID, body); err != nil {
+		return cli.errorOut(err)
+	}
+	fmt.Fprintf(cli.stdout(), "Chain %s updated\n", chainID)
+	return nil
+}
+
+// planSolanaChainPatch fetches the chain with chainID and deep-merges patch
+// into its config, returning the new config and the fields it changes.
+func (cli *Client) planSolanaChainPatch(chainID string, patch map[string]interface{}) (p solanaChainPatch, err error) {
+	if p.chain, err = cli.getSolanaChain(chainID); err != nil {
+		return p, err
+	}
+	b, err := json.Marshal(p.chain.Config)
+	if err != nil {
+		return p, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var merged map[string]interface{}
+	if err = d.Decode(&merged); err != nil {
+		return p, err
+	}
+	deepMergeConfig(merged, patch, "", func(string) {})
+	if b, err = json.Marshal(merged); err != nil {
+		return p, err
+	}
+	if err = json.Unmarshal(b, &p.config); err != nil {
+		return p, errors.Wrap(err, "invalid config patch")
+	}
+	p.fields, err = diffConfigs(p.chain.Config, p.config)
+	return p, err
+}
+
+// solanaChainNotFound returns the error for a missing chain with chainID,
+// listing the IDs of existing chains similar to it, in case of a typo.
+func (cli *Client) solanaChainNotFound(chainID string) error {
+	return withExitCode(cli.similarSolanaChainsError(chainID), ExitCodeNotFound)
+}
+
+func (cli *Client) similarSolanaChainsError(chainID string) error {
+	var chains []presenters.SolanaChainResource
+	if err := cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+		return errors.Errorf("chain %s not found", chainID)
+	}
+	ids := make([]string, len(chains))
+	for i, chain := range chains {
+		ids[i] = chain.ID
+	}
+	if similar := similarChainIDs(chainID, ids); len(similar) > 0 {
+		return errors.Errorf("chain %s not found; similar chain IDs: %s", chainID, strings.Join(similar, ", "))
+	}
+	return errors.Errorf("chain %s not found", chainID)
+}
+
+// matchSolanaChains returns the IDs of the Solana chains matching the glob
+// pattern, which are listed to w.
+func (cli *Client) matchSolanaChains(w io.Writer, pattern string) ([]string, error) {
+	var chains []presenters.SolanaChainResource
+	if err := cli.getAllPages(cli.HTTP, "/v2/chains/solana", &chains); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(chains))
+	for i, chain := range chains {
+		ids[i] = chain.ID
+	}
+	matched, err := matchChainIDs(pattern, ids)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, "Chains matching '%s': %s\n", pattern, strings.Join(matched, ", "))
+	return matched, nil
+}
+
+// DiffSolanaChains compares the Solana chains of two nodes, printing the chain
+// IDs missing on either side and the config fields differing between shared
+// chains. It exits non-zero if any differences are found.
+func (cli *Client) DiffSolanaChains(c *cli.Context) (err error) {
+	from, to := c.String("from"), c.String("to")
+	if from == "" || to == "" {
+		return cli.errorOut(usageError(c, errors.New("must pass the URLs of both nodes to compare [--from URL] [--to URL]")))
+	}
+	r, err := cli.outputRenderer(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fetch := func(nodeURL string) (map[string]interface{}, error) {
+		h, err2 := cli.newRemoteHTTPClient(nodeURL, c.String("file"))
+		if err2 != nil {
+			return nil, err2
+		}
+		var chains []presenters.SolanaChainResource
+		if err2 = cli.getAllPages(h, "/v2/chains/solana", &chains); err2 != nil {
+			return nil, errors.Wrapf(err2, "failed to fetch chains from %s", nodeURL)
+		}
+		configs := map[string]interface{}{}
+		for _, chain := range chains {
+			configs[chain.ID] = chain.Config
+		}
+		return configs, nil
+	}
+	fromConfigs, err := fetch(from)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	toConfigs, err := fetch(to)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	diff, err := diffChains(fromConfigs, toConfigs)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	diff.From, diff.To = from, to
+	if err = r.Render(&diff); err != nil {
+		return cli.errorOut(err)
+	}
+	if !diff.Empty() {
+		return cli.errorOut(errors.New("solana chains differ between nodes"))
+	}
+	return nil
 }
@@ -1,7 +1,22 @@
 package cmd_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -9,11 +24,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/smartcontractkit/chainlink/core/cmd"
 	"github.com/smartcontractkit/chainlink/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/core/internal/testutils/solanatest"
 	"github.com/smartcontractkit/chainlink/core/store/models"
+	"github.com/smartcontractkit/chainlink/core/web/presenters"
 )
 
 func TestClient_IndexSolanaChains(t *testing.T) {
@@ -64,6 +81,32 @@ func TestClient_CreateSolanaChain(t *testing.T) {
 	assertTableRenders(t, r)
 }
 
+func TestClient_CreateSolanaChain_TrimsID(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, _ := app.NewClientAndRenderer()
+
+	solanaChainID := solanatest.RandomChainID()
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "  "+solanaChainID+" ", "")
+	set.Parse([]string{`{}`})
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.CreateSolanaChain(c))
+
+	ch, err := app.Chains.Solana.ORM().Chain(solanaChainID)
+	require.NoError(t, err)
+	assert.Equal(t, solanaChainID, ch.ID)
+
+	set = flag.NewFlagSet("cli", 0)
+	set.String("id", "   ", "")
+	set.Parse([]string{`{}`})
+	c = cli.NewContext(nil, set, nil)
+
+	assert.EqualError(t, client.CreateSolanaChain(c), "missing chain ID [-id string]")
+}
+
 func TestClient_RemoveSolanaChain(t *testing.T) {
 	t.Parallel()
 
@@ -137,3 +180,3149 @@ func TestClient_ConfigureSolanaChain(t *testing.T) {
 	assert.Equal(t, original.TxTimeout, ch.Cfg.TxTimeout)
 	assertTableRenders(t, r)
 }
+
+func TestClient_DiffSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, _ := app.NewClientAndRenderer()
+
+	orm := app.Chains.Solana.ORM()
+	_, err := orm.CreateChain(solanatest.RandomChainID(), db.ChainCfg{})
+	require.NoError(t, err)
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("from", app.Server.URL, "")
+	set.String("to", app.Server.URL, "")
+	set.String("file", "../internal/fixtures/apicredentials", "")
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.DiffSolanaChains(c))
+}
+
+func TestClient_ConfigureSolanaChain_ChangedOnly(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, r := app.NewClientAndRenderer()
+
+	solanaChainID := solanatest.RandomChainID()
+	minute := models.MustMakeDuration(time.Minute)
+	hour := models.MustMakeDuration(time.Hour)
+	_, err := app.Chains.Solana.ORM().CreateChain(solanaChainID, db.ChainCfg{
+		ConfirmPollPeriod: &minute,
+		TxTimeout:         &hour,
+	})
+	require.NoError(t, err)
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", solanaChainID, "param")
+	set.Bool("changed-only", true, "")
+	set.Parse([]string{"TxTimeout=2h"})
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.ConfigureSolanaChain(c))
+
+	require.Len(t, r.Renders, 1)
+	diff := r.Renders[0].(*cmd.ChainConfigDiff)
+	assert.Equal(t, solanaChainID, diff.ID)
+	assert.Equal(t, []cmd.ConfigFieldDiff{{Key: "TxTimeout", From: "1h0m0s", To: "2h0m0s"}}, diff.Fields)
+	assertTableRenders(t, r)
+}
+
+func TestClient_ShowSolanaChain(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, r := app.NewClientAndRenderer()
+
+	solanaChainID := solanatest.RandomChainID()
+	_, err := app.Chains.Solana.ORM().CreateChain(solanaChainID, db.ChainCfg{})
+	require.NoError(t, err)
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", solanaChainID, "")
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.ShowSolanaChain(c))
+	chain := r.Renders[0].(*cmd.SolanaChainPresenter)
+	assert.Equal(t, solanaChainID, chain.ID)
+	assertTableRenders(t, r)
+
+	set = flag.NewFlagSet("cli", 0)
+	set.String("id", solanaChainID, "")
+	set.String("field", "Missing", "")
+	c = cli.NewContext(nil, set, nil)
+
+	assert.EqualError(t, client.ShowSolanaChain(c), "config field Missing not found")
+}
+
+func TestClient_ShowSolanaChain_OutputFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "chain.json")
+	show := func(force bool, responses ...*http.Response) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: responses}, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("output", "json", "")
+		set.String("output-file", file, "")
+		set.Bool("force", force, "")
+		err := client.ChainAction(client.ShowSolanaChain)(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+	chain := func(commitment string) *http.Response {
+		return stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"`+commitment+`"}}}}`)
+	}
+	readFile := func() string {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	out, err := show(false, chain("confirmed"))
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Contains(t, readFile(), `"Commitment": "confirmed"`)
+
+	_, err = show(false, chain("finalized"))
+	assert.EqualError(t, err, fmt.Sprintf("output file '%s' already exists: pass --force to overwrite it", file))
+
+	_, err = show(true, stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`))
+	require.Error(t, err)
+	assert.Contains(t, readFile(), `"Commitment": "confirmed"`)
+
+	_, err = show(true, chain("finalized"))
+	require.NoError(t, err)
+	assert.Contains(t, readFile(), `"Commitment": "finalized"`)
+}
+
+func TestClient_SolanaChainIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := func(enabled, disabled bool, responses ...*http.Response) (*stubHTTPClient, string, error) {
+		stub := &stubHTTPClient{responses: responses}
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.Bool("enabled", false, "")
+		set.Bool("disabled", false, "")
+		var args []string
+		if enabled {
+			args = append(args, "-enabled")
+		}
+		if disabled {
+			args = append(args, "-disabled")
+		}
+		require.NoError(t, set.Parse(args))
+		err := client.SolanaChainIDs(cli.NewContext(nil, set, nil))
+		return stub, b.String(), err
+	}
+
+	// Only the IDs are requested, page by page
+	stub, out, err := ids(false, false,
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet"}],"links":{"next":"/v2/chains/solana?page=2"}}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"testnet"}]}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "devnet\ntestnet\n", out)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, "/v2/chains/solana?fields%5Bsolana_chain%5D=id&size=100", stub.requests[0].path)
+
+	// Nodes rejecting sparse fieldsets are asked for the full chains, which
+	// are filtered in case the node ignores the enabled parameter
+	stub, out, err = ids(false, true,
+		stubResponse(http.StatusBadRequest, `{"errors":[{"detail":"unknown parameter fields"}]}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}},{"type":"solana_chain","id":"testnet","attributes":{"enabled":false,"config":{}}}]}`),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "testnet\n", out)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, "/v2/chains/solana?enabled=false&fields%5Bsolana_chain%5D=id%2Cenabled&size=100", stub.requests[0].path)
+	assert.Equal(t, "/v2/chains/solana?enabled=false&size=100", stub.requests[1].path)
+
+	stub, _, err = ids(true, true)
+	require.Error(t, err)
+	assert.Empty(t, stub.requests)
+}
+
+func TestClient_ShowSolanaChain_ResponseShapes(t *testing.T) {
+	t.Parallel()
+
+	show := func(body string) (*cltest.RendererMock, error) {
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, body)}}, Renderer: r}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		return r, client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+	}
+	chain := `{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}`
+
+	for name, body := range map[string]string{
+		"object":          `{"data":` + chain + `}`,
+		"singleton array": `{"data":[` + chain + `]}`,
+	} {
+		body := body
+		t.Run(name, func(t *testing.T) {
+			r, err := show(body)
+			require.NoError(t, err)
+			require.Len(t, r.Renders, 1)
+			p := r.Renders[0].(*cmd.SolanaChainPresenter)
+			assert.Equal(t, "devnet", p.ID)
+			assert.Equal(t, "confirmed", p.Config.Commitment.String)
+		})
+	}
+
+	_, err := show(`{"data":[` + chain + `,` + chain + `]}`)
+	assert.EqualError(t, err, "expected a single resource, but the response holds 2")
+	_, err = show(`{"data":[]}`)
+	assert.EqualError(t, err, "expected a single resource, but the response holds 0")
+}
+
+func TestClient_SolanaChains_MissingAttributes(t *testing.T) {
+	t.Parallel()
+
+	for name, body := range map[string]string{
+		"missing": `{"data":{"type":"solana_chain","id":"devnet"}}`,
+		"null":    `{"data":{"type":"solana_chain","id":"devnet","attributes":null}}`,
+	} {
+		body := body
+		t.Run(name, func(t *testing.T) {
+			r := &cltest.RendererMock{}
+			client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, body)}}, Renderer: r}
+			set := flag.NewFlagSet("cli", 0)
+			set.String("id", "devnet", "")
+			err := client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+			assert.EqualError(t, err, "malformed server response: resource has no attributes")
+			assert.Empty(t, r.Renders)
+		})
+	}
+
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}},{"type":"solana_chain","id":""}]}`),
+	}}, Renderer: r}
+	err := client.IndexSolanaChains(cli.NewContext(nil, flag.NewFlagSet("cli", 0), nil))
+	assert.EqualError(t, err, "malformed server response: resource has no attributes")
+	assert.Empty(t, r.Renders)
+}
+
+func TestClient_ConfigureSolanaChain_SingletonArray(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}]}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized"}}}]}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	require.NoError(t, set.Parse([]string{"Commitment=finalized"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+	require.Len(t, r.Renders, 1)
+	assert.Equal(t, "finalized", r.Renders[0].(*cmd.SolanaChainPresenter).Config.Commitment.String)
+}
+
+func TestClient_ShowSolanaChain_EnvPrefix(t *testing.T) {
+	t.Parallel()
+
+	show := func(output, prefix string) (string, error) {
+		var b bytes.Buffer
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"config":{"Commitment":"confirmed"}}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("output", output, "")
+		set.String("env-prefix", prefix, "")
+		err := client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	out, err := show("env", "SOLANA_")
+	require.NoError(t, err)
+	assert.Contains(t, out, "SOLANA_CONFIG_COMMITMENT='confirmed'\n")
+	assert.NotContains(t, out, "\nCONFIG_")
+
+	_, err = show("env", "1SOLANA")
+	assert.EqualError(t, err, "invalid --env-prefix '1SOLANA': must start with a letter or underscore, followed by letters, digits or underscores")
+	_, err = show("json", "SOLANA_")
+	assert.EqualError(t, err, "--env-prefix requires --output env")
+}
+
+func TestClient_ShowSolanaChain_HCL(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("output", "hcl", "")
+	require.NoError(t, client.ShowSolanaChain(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, `solana_chain "devnet" {
+  enabled = false
+  config {
+    Commitment = "confirmed"
+    TxTimeout  = "1m0s"
+  }
+}
+`, b.String())
+}
+
+func TestClient_ShowSolanaChain_CompactJSON(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}, CompactJSON: true}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("output", "json", "")
+	require.NoError(t, client.ChainAction(client.ShowSolanaChain)(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, 1, strings.Count(b.String(), "\n"))
+	assert.Contains(t, b.String(), `"Commitment":"confirmed"`)
+}
+
+func TestClient_RemoveSolanaChain_Multiple(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, _ := app.NewClientAndRenderer()
+
+	orm := app.Chains.Solana.ORM()
+	_, initialCount, err := orm.Chains(0, 25)
+	require.NoError(t, err)
+
+	ids := []string{solanatest.RandomChainID(), solanatest.RandomChainID()}
+	for _, id := range ids {
+		_, err = orm.CreateChain(id, db.ChainCfg{})
+		require.NoError(t, err)
+	}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.Bool("quiet", true, "")
+	set.Parse(ids)
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.RemoveSolanaChain(c))
+
+	_, count, err := orm.Chains(0, 25)
+	require.NoError(t, err)
+	assert.Equal(t, initialCount, count)
+}
+
+func TestClient_CreateSolanaChain_NoRender(t *testing.T) {
+	t.Parallel()
+
+	app := solanaStartNewApplication(t)
+	client, r := app.NewClientAndRenderer()
+
+	solanaChainID := solanatest.RandomChainID()
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", solanaChainID, "")
+	set.Bool("no-render", true, "")
+	set.Parse([]string{`{}`})
+	c := cli.NewContext(nil, set, nil)
+
+	require.NoError(t, client.CreateSolanaChain(c))
+	assert.Empty(t, r.Renders)
+
+	_, err := app.Chains.Solana.ORM().Chain(solanaChainID)
+	require.NoError(t, err)
+}
+
+func TestClient_CreateSolanaChain_ShowEffective(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.Bool("show-effective", true, "")
+	require.NoError(t, set.Parse([]string{`{"TxTimeout": "1m"}`}))
+	require.NoError(t, client.CreateSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, r.Renders, 2)
+	assert.Equal(t, "devnet", r.Renders[0].(*cmd.SolanaChainPresenter).ID)
+	effective := r.Renders[1].(*cmd.EffectiveChainConfig)
+	assert.Equal(t, "devnet", effective.ID)
+	require.Len(t, effective.Fields, 1)
+	assert.Equal(t, "Commitment", effective.Fields[0].Key)
+	assert.Nil(t, effective.Fields[0].From)
+	assert.Equal(t, "confirmed", effective.Fields[0].To)
+
+	var b bytes.Buffer
+	require.NoError(t, cmd.EffectiveChainConfig{ID: "devnet"}.RenderTable(cmd.RendererTable{Writer: &b}))
+	assert.Equal(t, "The node applied the config of chain devnet as submitted\n", b.String())
+}
+
+func TestClient_CreateSolanaChain_ConfigFlags(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`{"txTimeout": "1m"}`), 0600))
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, ioutil.WriteFile(invalidFile, []byte(`{nope}`), 0600))
+	baseFile := filepath.Join(t.TempDir(), "base.json")
+	require.NoError(t, ioutil.WriteFile(baseFile, []byte(`{"txTimeout": "10s", "commitment": "confirmed", "nested": {"a": 1, "b": 2}}`), 0600))
+	arrayFile := filepath.Join(t.TempDir(), "array.json")
+	require.NoError(t, ioutil.WriteFile(arrayFile, []byte(`[]`), 0600))
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`{"txTimeout": "1m", "nested": {"b": 3}}`), 0600))
+	configServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/solana/mainnet.json":
+			_, _ = w.Write([]byte(`{"commitment": "finalized"}`))
+		case "/big.json":
+			_, _ = w.Write([]byte(`{"pad": "` + strings.Repeat("x", 1<<20) + `"}`))
+		default:
+			_, _ = w.Write([]byte(`<html></html>`))
+		}
+	}))
+	defer configServer.Close()
+	auth := "Authorization: Bearer s3cret"
+
+	for _, tt := range []struct {
+		name   string
+		flags  map[string]string
+		files  []string
+		args   []string
+		config string
+		err    string
+	}{
+		{name: "json", flags: map[string]string{"config-json": `{"skipPreflight":true}`}, config: `{"skipPreflight":true}`},
+		{name: "file", flags: map[string]string{"config-file": configFile}, config: `{"txTimeout":"1m","nested":{"b":3}}`},
+		{name: "merged files", files: []string{baseFile, configFile}, config: `{"txTimeout":"1m","commitment":"confirmed","nested":{"a":1,"b":3}}`},
+		{name: "merge non-object", files: []string{configFile, arrayFile}, err: "config file '" + arrayFile + "' must contain a JSON object to be merged"},
+		{name: "positional", args: []string{`{}`}, config: `{}`},
+		{name: "url", flags: map[string]string{"config-url": configServer.URL + "/solana/mainnet.json", "config-url-header": auth}, config: `{"commitment":"finalized"}`},
+		{name: "url without auth", flags: map[string]string{"config-url": configServer.URL + "/solana/mainnet.json"}, err: "failed to fetch the config from " + configServer.URL + "/solana/mainnet.json: 403 Forbidden"},
+		{name: "url too large", flags: map[string]string{"config-url": configServer.URL + "/big.json", "config-url-header": auth}, err: "config at " + configServer.URL + "/big.json exceeds the maximum size of 1048576 bytes"},
+		{name: "url not json", flags: map[string]string{"config-url": configServer.URL + "/index.html", "config-url-header": auth}, err: "config at " + configServer.URL + "/index.html is not valid JSON: at offset 1: invalid character '<' looking for beginning of value"},
+		{name: "url scheme", flags: map[string]string{"config-url": "file:///etc/passwd"}, err: `invalid --config-url "file:///etc/passwd": must be an http or https URL`},
+		{name: "missing", err: "must pass in the chain's parameters [-id string] [--config-json JSON blob | --config-file JSON filepath | --config-url URL]"},
+		{name: "both", flags: map[string]string{"config-json": `{}`, "config-file": configFile}, err: "only one of --config-json, --config-file, --config-url or a positional config argument may be given"},
+		{name: "jsonc", flags: map[string]string{"config-json": `{"skipPreflight": true, // skip it
+}`, "jsonc": "true"}, config: `{"skipPreflight":true}`},
+		{name: "comments without jsonc", flags: map[string]string{"config-json": `{} // comment`}, err: "inline config is not valid JSON: at offset 4: invalid character '/' after top-level value"},
+		{name: "invalid json", flags: map[string]string{"config-json": `{"a":}`}, err: "inline config is not valid JSON: at offset 6: invalid character '}' looking for beginning of value"},
+		{name: "invalid positional json", args: []string{`{"a" 1}`}, err: "inline config is not valid JSON: at offset 6: invalid character '1' after object key"},
+		{name: "missing file", flags: map[string]string{"config-file": "missing.json"}, err: "failed to read config file 'missing.json': open missing.json: no such file or directory"},
+		{name: "missing positional file", args: []string{"missing.json"}, err: "failed to read config file 'missing.json': open missing.json: no such file or directory"},
+		{name: "invalid file", flags: map[string]string{"config-file": invalidFile}, err: "config file '" + invalidFile + "' is not valid JSON: at offset 2: invalid character 'n' looking for beginning of object key string"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusCreated, "{}")}}
+			client := &cmd.Client{HTTP: stub}
+
+			set := flag.NewFlagSet("cli", 0)
+			set.String("id", "devnet", "")
+			set.String("config-json", "", "")
+			set.Var(&cli.StringSlice{}, "config-file", "")
+			set.String("config-url", "", "")
+			set.Var(&cli.StringSlice{}, "config-url-header", "")
+			set.Bool("no-render", true, "")
+			set.Bool("jsonc", false, "")
+			for k, v := range tt.flags {
+				require.NoError(t, set.Set(k, v))
+			}
+			for _, f := range tt.files {
+				require.NoError(t, set.Set("config-file", f))
+			}
+			require.NoError(t, set.Parse(tt.args))
+
+			err := client.CreateSolanaChain(cli.NewContext(nil, set, nil))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.Empty(t, stub.requests)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, stub.requests, 1)
+			var params struct {
+				ChainID string          `json:"chainID"`
+				Config  json.RawMessage `json:"config"`
+			}
+			require.NoError(t, json.Unmarshal(stub.requests[0].body, &params))
+			assert.Equal(t, "devnet", params.ChainID)
+			assert.JSONEq(t, tt.config, string(params.Config))
+		})
+	}
+}
+
+func TestSortSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	chain := func(id string, enabled bool, updated time.Time) cmd.SolanaChainPresenter {
+		return cmd.SolanaChainPresenter{SolanaChainResource: presenters.SolanaChainResource{
+			JAID:      presenters.NewJAID(id),
+			Enabled:   enabled,
+			CreatedAt: t0,
+			UpdatedAt: updated,
+		}}
+	}
+	chains := cmd.SolanaChainPresenters{
+		chain("c", true, t0),
+		chain("a", false, t0.Add(time.Hour)),
+		chain("d", true, t0.Add(time.Hour)),
+		chain("b", false, t0),
+	}
+	ids := func() (ids []string) {
+		for _, c := range chains {
+			ids = append(ids, c.ID)
+		}
+		return
+	}
+
+	for _, tt := range []struct {
+		key string
+		exp []string
+	}{
+		{"id", []string{"a", "b", "c", "d"}},
+		{"-id", []string{"d", "c", "b", "a"}},
+		{"updated", []string{"b", "c", "a", "d"}},
+		{"-updated", []string{"a", "d", "b", "c"}},
+		{"created", []string{"a", "b", "c", "d"}},
+		{"-enabled", []string{"c", "d", "a", "b"}},
+	} {
+		require.NoError(t, cmd.SortSolanaChains(chains, tt.key))
+		assert.Equal(t, tt.exp, ids(), tt.key)
+	}
+
+	assert.EqualError(t, cmd.SortSolanaChains(chains, "name"), `unsupported sort key "name" (options: id, enabled, created, updated)`)
+}
+
+func TestClient_RenameSolanaChain(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"old","attributes":{"enabled":false,"config":{"Commitment":"confirmed"}}}}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_node","id":"7","attributes":{"name":"primary","solanaChainID":"old","solanaURL":"http://solana.invalid"}}]}`),
+		stubResponse(http.StatusCreated, `{}`),
+		stubResponse(http.StatusOK, `{}`),
+		stubResponse(http.StatusNoContent, ``),
+		stubResponse(http.StatusCreated, `{}`),
+		stubResponse(http.StatusNoContent, ``),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "old", "")
+	set.String("new-id", "new", "")
+	require.NoError(t, client.RenameSolanaChain(cli.NewContext(nil, set, nil)))
+
+	var calls []string
+	for _, r := range stub.requests {
+		calls = append(calls, r.method+" "+r.path)
+	}
+	assert.Equal(t, []string{
+		"GET /v2/chains/solana/old",
+		"GET /v2/chains/solana/old/nodes",
+		"POST /v2/chains/solana",
+		"PATCH /v2/chains/solana/new",
+		"DELETE /v2/nodes/solana/7",
+		"POST /v2/nodes/solana",
+		"DELETE /v2/chains/solana/old",
+	}, calls)
+
+	var created struct {
+		ChainID string      `json:"chainID"`
+		Config  db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[2].body, &created))
+	assert.Equal(t, "new", created.ChainID)
+	assert.Equal(t, "confirmed", created.Config.Commitment.String)
+	assert.JSONEq(t, `{"name":"primary","solanaChainId":"new","solanaURL":"http://solana.invalid"}`, string(stub.requests[5].body))
+
+	t.Run("same id", func(t *testing.T) {
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "old", "")
+		set.String("new-id", "old", "")
+		assert.EqualError(t, client.RenameSolanaChain(cli.NewContext(nil, set, nil)), "--new-id must differ from --id")
+	})
+}
+
+func TestClient_ShowSolanaChain_ConfigDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "devnet.json"), []byte(`{"SkipPreflight": true, "Commitment": "confirmed"}`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "testnet.json"), []byte(`{"Commitment": "confirmed"}`), 0600))
+	chain := func(id, commitment string) *http.Response {
+		return stubResponse(http.StatusOK, fmt.Sprintf(`{"data":{"type":"solana_chain","id":%q,"attributes":{"config":{"Commitment":%q,"SkipPreflight":true}}}}`, id, commitment))
+	}
+
+	// Key order is ignored
+	stub := &stubHTTPClient{responses: []*http.Response{chain("devnet", "confirmed")}}
+	var b bytes.Buffer
+	require.NoError(t, cmd.DiffSolanaChainBaselines(&cmd.Client{HTTP: stub}, &b, []string{"devnet"}, filepath.Join(dir, "devnet.json")))
+	assert.Empty(t, b.String())
+
+	stub = &stubHTTPClient{responses: []*http.Response{chain("devnet", "finalized")}}
+	err := cmd.DiffSolanaChainBaselines(&cmd.Client{HTTP: stub}, &b, []string{"devnet"}, filepath.Join(dir, "devnet.json"))
+	assert.EqualError(t, err, fmt.Sprintf("config of chain devnet differs from %s", filepath.Join(dir, "devnet.json")))
+	assert.Equal(t, fmt.Sprintf(`--- %s
++++ chain devnet
+-Commitment: "confirmed"
++Commitment: "finalized"
+`, filepath.Join(dir, "devnet.json")), b.String())
+
+	// A directory holds a baseline per chain
+	b.Reset()
+	stub = &stubHTTPClient{responses: []*http.Response{chain("devnet", "confirmed"), chain("testnet", "confirmed")}}
+	err = cmd.DiffSolanaChainBaselines(&cmd.Client{HTTP: stub}, &b, []string{"devnet", "testnet", "mainnet"}, dir)
+	assert.EqualError(t, err, fmt.Sprintf("config of 2 of 3 chains differs from the baselines in %s", dir))
+	assert.Contains(t, b.String(), "+++ chain testnet\n-SkipPreflight: null\n+SkipPreflight: true\n")
+	assert.Contains(t, b.String(), "Chain mainnet: ")
+	assert.NotContains(t, b.String(), "chain devnet")
+}
+
+func TestClient_ShowSolanaChain_ExplainDiff(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "devnet.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"SkipPreflight": true, "Commitment": "confirmed", "Custom": 1}`), 0600))
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"config":{"Commitment":"finalized","SkipPreflight":true}}}}`),
+	}}
+	var b bytes.Buffer
+	err := cmd.ExplainSolanaChainBaselines(&cmd.Client{HTTP: stub}, &b, []string{"devnet"}, file)
+	assert.EqualError(t, err, fmt.Sprintf("config of chain devnet differs from %s", file))
+	// Only differing fields known to the schema are explained
+	assert.Equal(t, fmt.Sprintf(`--- %s
++++ chain devnet
+-Commitment: "confirmed"
++Commitment: "finalized"
+# Commitment: The commitment level for reading chain state, one of processed, confirmed or finalized.
+-Custom: 1
++Custom: null
+`, file), b.String())
+
+	set := flag.NewFlagSet("test", 0)
+	set.String("id", "devnet", "")
+	set.Bool("explain-diff", true, "")
+	err = (&cmd.Client{HTTP: stub}).ShowSolanaChain(cli.NewContext(nil, set, nil))
+	assert.EqualError(t, err, "--explain-diff requires --config-diff")
+}
+
+func TestClient_CopySolanaChain(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":true,"config":{"Commitment":"confirmed","SkipPreflight":true}}}}`),
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"foo-staging","attributes":{"enabled":true,"config":{"Commitment":"finalized","SkipPreflight":true}}}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("from", "foo", "")
+	set.String("to", "foo-staging", "")
+	require.NoError(t, set.Parse([]string{"Commitment=finalized"}))
+	require.NoError(t, client.CopySolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 3)
+	assert.Equal(t, "/v2/chains/solana/foo-staging", stub.requests[1].path)
+	var created struct {
+		ChainID string      `json:"chainID"`
+		Config  db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[2].body, &created))
+	assert.Equal(t, "foo-staging", created.ChainID)
+	assert.Equal(t, "finalized", created.Config.Commitment.String)
+	assert.True(t, created.Config.SkipPreflight.Bool)
+	require.Len(t, r.Renders, 1)
+	assert.Equal(t, "foo-staging", r.Renders[0].(*cmd.SolanaChainPresenter).ID)
+
+	t.Run("destination exists", func(t *testing.T) {
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo-staging","attributes":{}}}`),
+		}}}
+		assert.EqualError(t, client.CopySolanaChain(cli.NewContext(nil, set, nil)), "chain foo-staging already exists")
+	})
+}
+
+func TestClient_DisableSolanaChain(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":false,"config":{"Commitment":"confirmed"}}}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "foo", "")
+	require.NoError(t, client.DisableSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	var patch struct {
+		Enabled bool        `json:"enabled"`
+		Config  db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &patch))
+	assert.False(t, patch.Enabled)
+	assert.Equal(t, "confirmed", patch.Config.Commitment.String)
+	require.Len(t, r.Renders, 1)
+	assert.False(t, r.Renders[0].(*cmd.SolanaChainPresenter).Enabled)
+}
+
+func TestClient_EnableSolanaChain_NotFound(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":false}}]}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnt", "")
+	err := client.EnableSolanaChain(cli.NewContext(nil, set, nil))
+	assert.EqualError(t, err, "chain devnt not found; similar chain IDs: devnet")
+	var coder cli.ExitCoder
+	require.True(t, errors.As(err, &coder))
+	assert.Equal(t, cmd.ExitCodeNotFound, coder.ExitCode())
+	// Nothing is patched
+	for _, req := range stub.requests {
+		assert.Equal(t, http.MethodGet, req.method)
+	}
+
+	client = &cmd.Client{HTTP: &stubHTTPClient{}, Renderer: &cltest.RendererMock{}}
+	err = client.DisableSolanaChain(cli.NewContext(nil, flag.NewFlagSet("cli", 0), nil))
+	assert.EqualError(t, err, "missing chain ID [-id string | --all | --match pattern | --ids-stdin]")
+}
+
+func TestClient_SetSolanaChainEnabled(t *testing.T) {
+	t.Parallel()
+
+	setEnabled := func(t *testing.T, stub *stubHTTPClient, args ...string) error {
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "foo", "")
+		set.String("value", "", "")
+		require.NoError(t, set.Parse(args))
+		return client.SetSolanaChainEnabled(cli.NewContext(nil, set, nil))
+	}
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":false,"config":{"TxTimeout":"1m0s"}}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s"}}}}`),
+	}}
+	require.NoError(t, setEnabled(t, stub, "-value", "Yes"))
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	assert.JSONEq(t, `{"enabled":true,"config":{"BalancePollPeriod":null,"ConfirmPollPeriod":null,"OCR2CachePollPeriod":null,"OCR2CacheTTL":null,"TxTimeout":"1m0s","SkipPreflight":null,"Commitment":null}}`, string(stub.requests[1].body))
+
+	// Nothing is patched if the chain is already in the given state
+	stub = &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":false}}}`),
+	}}
+	require.NoError(t, setEnabled(t, stub, "-value", "0"))
+	assert.Len(t, stub.requests, 1)
+
+	for _, value := range []string{"t", "enabled", ""} {
+		err := setEnabled(t, &stubHTTPClient{}, "-value", value)
+		assert.EqualError(t, err, fmt.Sprintf("invalid --value: '%s' is not a boolean, must be one of true, false, yes, no, on, off, 1 or 0", value))
+	}
+	assert.EqualError(t, setEnabled(t, &stubHTTPClient{}), "missing --value, must be true or false")
+}
+
+func TestClient_DisableSolanaChain_All(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"foo","attributes":{"enabled":true}},{"type":"solana_chain","id":"bar","attributes":{"enabled":false}}]}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":true}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"foo","attributes":{"enabled":false}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"bar","attributes":{"enabled":false}}}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.Bool("all", true, "")
+	set.Bool("force", true, "")
+	require.NoError(t, client.DisableSolanaChain(cli.NewContext(nil, set, nil)))
+
+	// bar is already disabled, so only foo is patched
+	require.Len(t, stub.requests, 4)
+	assert.Equal(t, http.MethodPatch, stub.requests[2].method)
+	assert.Equal(t, "/v2/chains/solana/foo", stub.requests[2].path)
+	assert.Equal(t, http.MethodGet, stub.requests[3].method)
+
+	t.Run("conflicting selection", func(t *testing.T) {
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "", "")
+		set.Bool("all", false, "")
+		require.NoError(t, set.Parse([]string{"-id", "foo", "-all"}))
+		client := &cmd.Client{HTTP: &stubHTTPClient{}}
+		assert.Error(t, client.EnableSolanaChain(cli.NewContext(nil, set, nil)))
+	})
+}
+
+func TestClient_RunSolanaDoctor(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{}`),
+		stubResponse(http.StatusOK, `{"version":"1.2.0","commitSHA":"abc"}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{}},{"type":"solana_chain","id":"testnet","attributes":{}}]}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_node","id":"1","attributes":{"name":"primary"}}]}`),
+		stubResponse(http.StatusOK, `{"data":[]}`),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	p := client.RunSolanaDoctor("1.2.0", "abc")
+	assert.Equal(t, []cmd.DoctorCheck{
+		{Name: "connectivity", Status: "pass", Detail: "node is reachable"},
+		{Name: "authentication", Status: "pass", Detail: "session is valid"},
+		{Name: "version", Status: "pass", Detail: "node and CLI builds match (1.2.0@abc)"},
+		{Name: "chains", Status: "pass", Detail: "2 Solana chain(s)"},
+		{Name: "chain devnet nodes", Status: "pass", Detail: "1 node(s)"},
+		{Name: "chain testnet nodes", Status: "fail", Detail: "no nodes", Hint: "add one with 'chainlink nodes solana create --chain-id testnet'"},
+	}, p.Checks)
+	assert.Equal(t, 1, p.Failed())
+
+	t.Run("unauthorized", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{}`),
+			stubResponse(http.StatusUnauthorized, ``),
+		}}
+		client := &cmd.Client{HTTP: stub}
+
+		p := client.RunSolanaDoctor("1.2.0", "abc")
+		require.Len(t, p.Checks, 4)
+		assert.Equal(t, "fail", p.Checks[1].Status)
+		assert.Equal(t, "log in with 'chainlink admin login', or set CL_SESSION_COOKIE", p.Checks[1].Hint)
+		assert.Equal(t, "skip", p.Checks[2].Status)
+		assert.Equal(t, 1, p.Failed())
+	})
+}
+
+func TestClient_ConfigureSolanaChain_ConfigFile(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), "config.jsonc")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`{
+	// tuned for devnet
+	"Commitment": "finalized",
+	"SkipPreflight": true,
+}`), 0600))
+
+	chain := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, chain),
+		stubResponse(http.StatusOK, chain),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("config-file", configFile, "")
+	set.Bool("jsonc", true, "")
+	require.NoError(t, set.Parse([]string{"SkipPreflight=false"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 2)
+	var params struct {
+		Config db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &params))
+	assert.Equal(t, "finalized", params.Config.Commitment.String)
+	assert.False(t, params.Config.SkipPreflight.Bool)
+	assert.True(t, params.Config.SkipPreflight.Valid)
+}
+
+func TestClient_IndexSolanaChains_Wide(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}},{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}]}`),
+		stubResponse(http.StatusOK, `{"data":[],"meta":{"count":2}}`),
+		stubResponse(http.StatusInternalServerError, `{}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.Bool("wide", true, "")
+	require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)))
+
+	assert.Equal(t, "/v2/chains/solana/devnet/nodes?size=1", stub.requests[1].path)
+	assert.Equal(t, "/v2/chains/solana/testnet/nodes?size=1", stub.requests[2].path)
+	wide := *r.Renders[0].(*cmd.WideChainsPresenter)
+	require.Len(t, wide, 2)
+	assert.Equal(t, "solana", wide[0].Family)
+	assert.Equal(t, 2, *wide[0].Nodes)
+	assert.Nil(t, wide[1].Nodes)
+	assert.Equal(t, wide[0].ConfigHash, wide[1].ConfigHash)
+	assert.Equal(t, 1, wide[0].ConfigKeys)
+	configJSON, err := json.Marshal(wide[0].Chain.(*cmd.SolanaChainPresenter).Config)
+	require.NoError(t, err)
+	assert.Equal(t, len(configJSON), wide[0].ConfigSize)
+
+	var b bytes.Buffer
+	require.NoError(t, wide.RenderTable(cmd.RendererTable{Writer: &b}))
+	assert.Contains(t, b.String(), "Config Hash: "+wide[0].ConfigHash)
+	assert.Contains(t, b.String(), "Nodes:         2")
+	assert.Contains(t, b.String(), "Nodes:       n/a")
+	assert.Contains(t, b.String(), "Keys:        1\nSize:        161")
+}
+
+func TestClient_HistorySolanaChain(t *testing.T) {
+	t.Parallel()
+
+	chain := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`
+	notFound := `{"errors":[{"detail":"not found"}]}`
+	for _, tt := range []struct {
+		name      string
+		responses []*http.Response
+		err       string
+		versions  []string
+	}{
+		{
+			name: "history",
+			responses: []*http.Response{stubResponse(http.StatusOK,
+				`{"data":[{"type":"solana_chain_config_versions","id":"1","attributes":{"config":{},"changedBy":"admin"}},`+
+					`{"type":"solana_chain_config_versions","id":"2","attributes":{"config":{"Commitment":"confirmed"}}}]}`)},
+			versions: []string{"1", "2"},
+		},
+		{
+			name:      "unsupported",
+			responses: []*http.Response{stubResponse(http.StatusNotFound, notFound), stubResponse(http.StatusOK, chain)},
+			err:       "this node does not record chain config history: 'solana chains history' requires a node which serves /v2/chains/solana/:ID/history",
+		},
+		{
+			name:      "missing chain",
+			responses: []*http.Response{stubResponse(http.StatusNotFound, notFound), stubResponse(http.StatusNotFound, notFound)},
+			err:       "failed to get chain devnet",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubHTTPClient{responses: tt.responses}
+			r := &cltest.RendererMock{}
+			client := &cmd.Client{HTTP: stub, Renderer: r}
+
+			set := flag.NewFlagSet("cli", 0)
+			set.String("id", "devnet", "")
+			err := client.HistorySolanaChain(cli.NewContext(nil, set, nil))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "/v2/chains/solana/devnet/history", stub.requests[0].path)
+			require.Len(t, r.Renders, 1)
+			history := *r.Renders[0].(*cmd.SolanaChainConfigVersionPresenters)
+			var versions []string
+			for _, v := range history {
+				versions = append(versions, v.GetID())
+			}
+			assert.Equal(t, tt.versions, versions)
+			assert.Equal(t, "admin", history[0].ChangedBy)
+			assert.Equal(t, "confirmed", history[1].Config.Commitment.String)
+		})
+	}
+}
+
+func TestClient_IndexSolanaChains_OnlyUnhealthy(t *testing.T) {
+	t.Parallel()
+
+	chains := `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}},{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{}}}]}`
+	nodes := `{"data":[{"type":"solana_node","id":"1","attributes":{"name":"a","solanaChainID":"devnet"}},{"type":"solana_node","id":"2","attributes":{"name":"b","solanaChainID":"testnet"}},{"type":"solana_node","id":"3","attributes":{"name":"c","solanaChainID":"testnet"}}]}`
+	for _, tt := range []struct {
+		name   string
+		health *http.Response
+		chains []string
+		err    string
+	}{
+		{
+			name: "unhealthy",
+			health: stubResponse(http.StatusServiceUnavailable, `{"data":[`+
+				`{"type":"checks","id":"Solana.devnet.a","attributes":{"name":"Solana.devnet.a","status":"passing"}},`+
+				`{"type":"checks","id":"Solana.testnet.c","attributes":{"name":"Solana.testnet.c","status":"failing"}}]}`),
+			chains: []string{"testnet"},
+		},
+		{
+			name:   "unsupported",
+			health: stubResponse(http.StatusOK, `{"data":[{"type":"checks","id":"*solana.chainSet","attributes":{"name":"*solana.chainSet","status":"passing"}}]}`),
+			err:    "this node does not report the health of its Solana nodes, so --only-unhealthy cannot be applied",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubHTTPClient{responses: []*http.Response{
+				stubResponse(http.StatusOK, chains),
+				stubResponse(http.StatusOK, nodes),
+				tt.health,
+			}}
+			r := &cltest.RendererMock{}
+			client := &cmd.Client{HTTP: stub, Renderer: r}
+
+			set := flag.NewFlagSet("cli", 0)
+			set.Bool("only-unhealthy", true, "")
+			err := client.IndexSolanaChains(cli.NewContext(nil, set, nil))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "/health", stub.requests[2].path)
+			var ids []string
+			for _, chain := range *r.Renders[0].(*cmd.SolanaChainPresenters) {
+				ids = append(ids, chain.ID)
+			}
+			assert.Equal(t, tt.chains, ids)
+		})
+	}
+}
+
+func TestClient_RemoveSolanaChain_IgnoreNotFound(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name           string
+		ignoreNotFound bool
+		getStatus      int
+		err            string
+	}{
+		{name: "absent", ignoreNotFound: true, getStatus: http.StatusNotFound},
+		{name: "present", ignoreNotFound: true, getStatus: http.StatusOK, err: "failed to delete chain gone"},
+		{name: "without flag", err: "failed to delete chain gone"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubHTTPClient{responses: []*http.Response{
+				stubResponse(http.StatusNoContent, ``),
+				stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"sql: no rows in result set"}]}`),
+				stubResponse(tt.getStatus, `{}`),
+			}}
+			client := &cmd.Client{HTTP: stub}
+
+			set := flag.NewFlagSet("cli", 0)
+			set.Bool("ignore-not-found", tt.ignoreNotFound, "")
+			require.NoError(t, set.Parse([]string{"present", "gone"}))
+			err := client.RemoveSolanaChain(cli.NewContext(nil, set, nil))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, stub.requests, 3)
+			assert.Equal(t, "GET /v2/chains/solana/gone", stub.requests[2].method+" "+stub.requests[2].path)
+		})
+	}
+}
+
+func TestNormalizeSolanaChainConfig(t *testing.T) {
+	t.Parallel()
+
+	timeout := models.MustMakeDuration(90 * time.Second)
+	config := db.ChainCfg{TxTimeout: &timeout, Commitment: null.StringFrom("Finalized")}
+
+	var w bytes.Buffer
+	assert.Equal(t, config, cmd.NormalizeSolanaChainConfig(&w, config, false))
+	assert.Equal(t, "WARNING: Commitment \"Finalized\" is not supported, the node uses \"confirmed\" instead\n", w.String())
+
+	w.Reset()
+	normalized := cmd.NormalizeSolanaChainConfig(&w, db.ChainCfg{TxTimeout: &timeout}, true)
+	assert.Empty(t, w.String())
+	b, err := json.Marshal(normalized)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"BalancePollPeriod": "5s",
+		"ConfirmPollPeriod": "1s",
+		"OCR2CachePollPeriod": "1s",
+		"OCR2CacheTTL": "1m0s",
+		"TxTimeout": "1m30s",
+		"SkipPreflight": true,
+		"Commitment": "confirmed"
+	}`, string(b))
+}
+
+func TestReportChainDeletes(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{"a", "b", "c"}
+	errs := []error{nil, cmd.ErrChainAbsent, errors.New("boom")}
+
+	var b, stderr bytes.Buffer
+	err := cmd.ReportChainDeletes(&b, &stderr, false, ids, errs)
+	assert.EqualError(t, err, "failed to delete chain c: boom")
+	assert.Equal(t, "Chain a deleted\nChain b already absent\n", b.String())
+
+	b.Reset()
+	err = cmd.ReportChainDeletes(&b, &stderr, true, ids, errs)
+	assert.EqualError(t, err, "failed to delete chain c: boom")
+	assert.JSONEq(t, `[{"id":"a","deleted":true},{"id":"b","deleted":false,"absent":true},{"id":"c","deleted":false,"error":"boom"}]`, b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.ReportChainDeletes(&b, &stderr, true, []string{"a"}, []error{nil}))
+	assert.Equal(t, `{"id":"a","deleted":true}`+"\n", b.String())
+}
+
+func TestClient_ExportSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"a","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}},{"type":"solana_chain","id":"b","attributes":{"enabled":false,"config":{}}}],"links":{"next":"/v2/chains/solana?page=2"}}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"c","attributes":{"enabled":true,"config":{}}}],"links":{"next":"/v2/chains/solana?page=3"}}`),
+		stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	file := filepath.Join(t.TempDir(), "chains.jsonl")
+	set := flag.NewFlagSet("cli", 0)
+	set.String("file", file, "")
+	set.String("output", "jsonl", "")
+	require.Error(t, client.ExportSolanaChains(cli.NewContext(nil, set, nil)))
+
+	// The pages exported before the failure are complete lines.
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"id":"a","enabled":true,"config":{"BalancePollPeriod":null,"ConfirmPollPeriod":null,"OCR2CachePollPeriod":null,"OCR2CacheTTL":null,"TxTimeout":null,"SkipPreflight":null,"Commitment":"confirmed"}}`, lines[0])
+	for i, id := range []string{"a", "b", "c"} {
+		var chain struct{ ID string }
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &chain))
+		assert.Equal(t, id, chain.ID)
+	}
+	assert.Equal(t, "/v2/chains/solana?page=3", stub.requests[2].path)
+}
+
+func TestClient_ValidateAllSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	validate := func(t *testing.T, strict bool, page string) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, page)}}, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.Bool("strict", strict, "")
+		err := client.ValidateAllSolanaChains(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	t.Run("issues", func(t *testing.T) {
+		out, err := validate(t, false, `{"data":[`+
+			`{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}},`+
+			`{"type":"solana_chain","id":"mainnet","attributes":{"enabled":true,"config":{"RPC":{},"txTimeout":"1m"}}},`+
+			`{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{"commitment":"confirmed"}}}]}`)
+		assert.EqualError(t, err, "1 of 3 chain(s) have invalid configs: mainnet")
+		assert.NotContains(t, out, "devnet")
+		assert.Contains(t, out, "unknown field")
+		assert.Contains(t, out, "║ testnet  ║ warning  ║ commitment ║")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := validate(t, true, `{"data":[{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{"commitment":"confirmed"}}}]}`)
+		assert.EqualError(t, err, "1 of 1 chain(s) have invalid configs: testnet")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		out, err := validate(t, true, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}]}`)
+		require.NoError(t, err)
+		assert.Equal(t, "No config issues found\n", out)
+	})
+}
+
+func TestClient_SolanaChainConfigHash(t *testing.T) {
+	t.Parallel()
+
+	chain := func(id, config string) string {
+		return `{"type":"solana_chain","id":"` + id + `","attributes":{"enabled":true,"config":` + config + `}}`
+	}
+	hash := func(t *testing.T, set *flag.FlagSet, responses ...*http.Response) []string {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: responses}, Renderer: cmd.RendererTable{Writer: &b}}
+		require.NoError(t, client.SolanaChainConfigHash(cli.NewContext(nil, set, nil)))
+		return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	one := hash(t, set, stubResponse(http.StatusOK, `{"data":`+chain("devnet", `{"TxTimeout": "1m", "Commitment": "confirmed"}`)+`}`))
+	require.Len(t, one, 1)
+	assert.Len(t, one[0], 64)
+
+	set = flag.NewFlagSet("cli", 0)
+	set.Bool("all", true, "")
+	all := hash(t, set, stubResponse(http.StatusOK, `{"data":[`+
+		chain("devnet", `{"Commitment":"confirmed","TxTimeout":"1m0s"}`)+`,`+
+		chain("testnet", `{"Commitment":"finalized"}`)+`]}`))
+	require.Len(t, all, 2)
+	assert.Equal(t, one[0]+"  devnet", all[0])
+	assert.True(t, strings.HasSuffix(all[1], "  testnet"))
+	assert.NotEqual(t, one[0], strings.Fields(all[1])[0])
+}
+
+func TestClient_IndexSolanaChains_MaxRows(t *testing.T) {
+	t.Parallel()
+
+	chains := `{"data":[` +
+		`{"type":"solana_chain","id":"c","attributes":{"enabled":true,"config":{}}},` +
+		`{"type":"solana_chain","id":"a","attributes":{"enabled":true,"config":{}}},` +
+		`{"type":"solana_chain","id":"b","attributes":{"enabled":true,"config":{}}}]}`
+	var stderr bytes.Buffer
+	list := func(maxRows int) ([]string, error) {
+		stderr.Reset()
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, chains)}}, Renderer: r, Stderr: &stderr}
+		set := flag.NewFlagSet("cli", 0)
+		set.Int("max-rows", maxRows, "")
+		set.String("sort", "id", "")
+		if err := client.IndexSolanaChains(cli.NewContext(nil, set, nil)); err != nil {
+			return nil, err
+		}
+		require.Len(t, r.Renders, 1)
+		var ids []string
+		for _, chain := range *r.Renders[0].(*cmd.SolanaChainPresenters) {
+			ids = append(ids, chain.ID)
+		}
+		return ids, nil
+	}
+
+	// Truncated after sorting
+	ids, err := list(2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+	assert.Equal(t, "... 1 more not shown\n", stderr.String())
+
+	ids, err = list(5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.Empty(t, stderr.String())
+
+	_, err = list(-1)
+	assert.EqualError(t, err, "invalid --max-rows -1: must be at least 1")
+}
+
+func TestClient_ApplySolanaChainPatch(t *testing.T) {
+	t.Parallel()
+
+	apply := func(patch string, dryRun bool) (*stubHTTPClient, string, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","SkipPreflight":true}}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`),
+		}}
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.Bool("patch-from-stdin", true, "")
+		set.Bool("dry-run", dryRun, "")
+		err := cmd.ApplySolanaChainPatchFrom(client, cli.NewContext(nil, set, nil), strings.NewReader(patch))
+		return stub, b.String(), err
+	}
+
+	// Null fields are unset
+	stub, out, err := apply(`{"Commitment": "finalized", "SkipPreflight": null}`, false)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Chain devnet updated\n")
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	assert.Contains(t, string(stub.requests[1].body), `"Commitment":"finalized"`)
+	assert.Contains(t, string(stub.requests[1].body), `"SkipPreflight":null`)
+
+	stub, out, err = apply(`{"Commitment": "finalized"}`, true)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Would update chain devnet\n")
+	assert.Len(t, stub.requests, 1)
+
+	stub, out, err = apply(`{"Commitment": "confirmed"}`, false)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Chain devnet unchanged\n")
+	assert.Len(t, stub.requests, 1)
+
+	stub, _, err = apply(`["Commitment"]`, false)
+	assert.EqualError(t, err, "config patch from stdin must contain a JSON object")
+	assert.Empty(t, stub.requests)
+}
+
+func TestClient_ConfigSetManySolanaChains(t *testing.T) {
+	t.Parallel()
+
+	patch := filepath.Join(t.TempDir(), "patch.json")
+	require.NoError(t, ioutil.WriteFile(patch, []byte(`{"Commitment": "finalized", "TxTimeout": "1m"}`), 0600))
+	chain := func(id, config string) string {
+		return `{"type":"solana_chain","id":"` + id + `","attributes":{"enabled":true,"config":` + config + `}}`
+	}
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[`+chain("testnet-a", `{"Commitment":"confirmed"}`)+`,`+chain("testnet-b", `{"Commitment":"finalized","TxTimeout":"1m0s"}`)+`,`+chain("mainnet", `{}`)+`]}`),
+		stubResponse(http.StatusOK, `{"data":`+chain("testnet-a", `{"Commitment":"confirmed"}`)+`}`),
+		stubResponse(http.StatusOK, `{"data":`+chain("testnet-b", `{"Commitment":"finalized","TxTimeout":"1m0s"}`)+`}`),
+		stubResponse(http.StatusOK, `{"data":`+chain("testnet-a", `{"Commitment":"finalized","TxTimeout":"1m0s"}`)+`}`),
+	}}
+	var b bytes.Buffer
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("patch", patch, "")
+	set.String("match", "testnet-*", "")
+	set.Bool("yes", true, "")
+	require.NoError(t, client.ConfigSetManySolanaChains(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 4)
+	assert.Equal(t, http.MethodPatch, stub.requests[3].method)
+	assert.Equal(t, "/v2/chains/solana/testnet-a", stub.requests[3].path)
+	assert.Contains(t, string(stub.requests[3].body), `"Commitment":"finalized"`)
+	assert.Contains(t, string(stub.requests[3].body), `"TxTimeout":"1m0s"`)
+	out := b.String()
+	assert.Contains(t, out, `║ TxTimeout  ║ null        ║ "1m0s"      ║`)
+	assert.Contains(t, out, "No config changes for chain testnet-b\n")
+	assert.Contains(t, out, "Chain testnet-a updated\nChain testnet-b unchanged\n")
+
+	set = flag.NewFlagSet("cli", 0)
+	set.String("match", "testnet-*", "")
+	assert.EqualError(t, client.ConfigSetManySolanaChains(cli.NewContext(nil, set, nil)), "must pass the file of the config patch [--patch FILE]")
+}
+
+func TestClient_WaitSolanaChain(t *testing.T) {
+	t.Parallel()
+
+	chain := stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`)
+	nodes := `{"data":[{"type":"solana_node","id":"1","attributes":{"name":"a","solanaChainID":"devnet"}}]}`
+	check := func(status string) *http.Response {
+		return stubResponse(http.StatusOK, `{"data":[{"type":"checks","id":"Solana.devnet.a","attributes":{"name":"Solana.devnet.a","status":"`+status+`"}}]}`)
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			chain, stubResponse(http.StatusOK, nodes), check("failing"),
+			chain, stubResponse(http.StatusOK, nodes), check("passing"),
+		}}
+		client := &cmd.Client{HTTP: stub}
+		var w bytes.Buffer
+		require.NoError(t, client.WaitSolanaChainWith(&w, "devnet", time.Minute, time.Millisecond))
+		assert.Equal(t, "Chain devnet is not ready: 0 of 1 node(s) alive\nChain devnet is ready: 1 of 1 node(s) alive\n", w.String())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			chain, stubResponse(http.StatusOK, `{"data":[]}`),
+		}}
+		client := &cmd.Client{HTTP: stub}
+		var w bytes.Buffer
+		err := client.WaitSolanaChainWith(&w, "devnet", 10*time.Millisecond, 20*time.Millisecond)
+		assert.EqualError(t, err, "chain devnet not ready after 10ms: chain has no nodes")
+		assert.Equal(t, "Chain devnet is not ready: chain has no nodes\n", w.String())
+	})
+}
+
+func TestClient_RemoveSolanaChain_Match(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"testnet-a","attributes":{}},{"type":"solana_chain","id":"devnet","attributes":{}},{"type":"solana_chain","id":"testnet-b","attributes":{}}]}`),
+		stubResponse(http.StatusNoContent, ``),
+		stubResponse(http.StatusNoContent, ``),
+	}}
+	client := &cmd.Client{HTTP: stub}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("match", "testnet-*", "")
+	set.Bool("force", true, "")
+	require.NoError(t, client.RemoveSolanaChain(cli.NewContext(nil, set, nil)))
+
+	var calls []string
+	for _, r := range stub.requests {
+		calls = append(calls, r.method+" "+r.path)
+	}
+	assert.Equal(t, []string{
+		"GET /v2/chains/solana",
+		"DELETE /v2/chains/solana/testnet-a",
+		"DELETE /v2/chains/solana/testnet-b",
+	}, calls)
+
+	t.Run("without --force", func(t *testing.T) {
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"testnet-a","attributes":{}}]}`),
+		}}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("match", "testnet-*", "")
+		set.Bool("force", false, "")
+		assert.EqualError(t, client.RemoveSolanaChain(cli.NewContext(nil, set, nil)), "refusing to delete 1 chain(s) matching 'testnet-*' without confirmation: pass --force")
+	})
+}
+
+func TestClient_RemoveSolanaChain_ConfirmExists(t *testing.T) {
+	t.Parallel()
+
+	remove := func(stub *stubHTTPClient, force bool) error {
+		set := flag.NewFlagSet("cli", 0)
+		set.Bool("confirm-exists", true, "")
+		set.Bool("force", force, "")
+		require.NoError(t, set.Parse([]string{"devnet"}))
+		return (&cmd.Client{HTTP: stub}).RemoveSolanaChain(cli.NewContext(nil, set, nil))
+	}
+	calls := func(stub *stubHTTPClient) (calls []string) {
+		for _, r := range stub.requests {
+			calls = append(calls, r.method+" "+r.path)
+		}
+		return calls
+	}
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+		stubResponse(http.StatusNoContent, ``),
+	}}
+	require.NoError(t, remove(stub, true))
+	assert.Equal(t, []string{"GET /v2/chains/solana/devnet", "DELETE /v2/chains/solana/devnet"}, calls(stub))
+
+	// A typo is caught before anything is deleted
+	stub = &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet-2","attributes":{}}]}`),
+	}}
+	assert.EqualError(t, remove(stub, true), "chain devnet not found; similar chain IDs: devnet-2")
+	assert.Equal(t, []string{"GET /v2/chains/solana/devnet", "GET /v2/chains/solana"}, calls(stub))
+
+	// Without a terminal, --force is required, but the check is still made
+	stub = &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+	}}
+	assert.EqualError(t, remove(stub, false), "refusing to delete the 1 chain(s) above without confirmation: pass --force")
+	assert.Equal(t, []string{"GET /v2/chains/solana/devnet"}, calls(stub))
+}
+
+func TestClient_RemoveSolanaChain_Output(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	client := &cmd.Client{
+		HTTP:     &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusNoContent, ``)}},
+		Renderer: cmd.RendererTable{Writer: &b},
+	}
+	set := flag.NewFlagSet("cli", 0)
+	require.NoError(t, set.Parse([]string{"devnet", "testnet"}))
+	require.NoError(t, client.RemoveSolanaChain(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, "Chain devnet deleted\nChain testnet deleted\n", b.String())
+}
+
+func TestClient_RemoveSolanaChain_OnError(t *testing.T) {
+	t.Parallel()
+
+	remove := func(t *testing.T, policy string) (*stubHTTPClient, string, error) {
+		var b bytes.Buffer
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusNoContent, ``),
+			stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`),
+			stubResponse(http.StatusNoContent, ``),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("on-error", policy, "")
+		require.NoError(t, set.Parse([]string{"a", "b", "c"}))
+		err := client.RemoveSolanaChain(cli.NewContext(nil, set, nil))
+		return stub, b.String(), err
+	}
+
+	t.Run("continue", func(t *testing.T) {
+		stub, out, err := remove(t, "continue")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete chain b")
+		assert.Len(t, stub.requests, 3)
+		assert.Equal(t, "Chain a deleted\nChain c deleted\n", out)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		stub, out, err := remove(t, "abort")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to delete chain b")
+		assert.NotContains(t, err.Error(), "chain c")
+		assert.Len(t, stub.requests, 2)
+		assert.Equal(t, "Chain a deleted\nAborted after the first failure, skipping 1 of 3 chains\n", out)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := remove(t, "maybe")
+		assert.EqualError(t, err, "unsupported --on-error 'maybe', options: [continue, abort]")
+	})
+}
+
+func TestClient_ShowSolanaChain_SortConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	show := func(t *testing.T, renderer cmd.Renderer, args ...string) string {
+		var b bytes.Buffer
+		switch r := renderer.(type) {
+		case cmd.RendererJSON:
+			r.Writer = &b
+			renderer = r
+		case cmd.RendererTable:
+			r.Writer = &b
+			renderer = r
+		}
+		client := &cmd.Client{Renderer: renderer, HTTP: &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"config":{"ConfirmPollPeriod":"1s","Commitment":"confirmed"}}}}`),
+		}}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.Bool("show-secrets", true, "")
+		set.Bool("sort-config-keys", false, "")
+		require.NoError(t, set.Parse(args))
+		require.NoError(t, client.ShowSolanaChain(cli.NewContext(nil, set, nil)))
+		return b.String()
+	}
+	sorted := func(out string) bool {
+		return strings.Index(out, `"Commitment"`) < strings.Index(out, `"ConfirmPollPeriod"`)
+	}
+
+	assert.True(t, sorted(show(t, cmd.RendererJSON{})), "JSON output should be sorted by default")
+	assert.False(t, sorted(show(t, cmd.RendererJSON{}, "--sort-config-keys=false")))
+	assert.False(t, sorted(show(t, cmd.RendererTable{})))
+	assert.True(t, sorted(show(t, cmd.RendererTable{}, "--sort-config-keys")))
+}
+
+func TestClient_GetSolanaChainConfig(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "devnet.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous backup"), 0600))
+
+	client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"config":{"SkipPreflight":true,"Commitment":"confirmed"}}}}`),
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+	}}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("file", path, "")
+	require.NoError(t, client.GetSolanaChainConfig(cli.NewContext(nil, set, nil)))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var config db.ChainCfg
+	require.NoError(t, json.Unmarshal(b, &config))
+	assert.Equal(t, "confirmed", config.Commitment.String)
+	assert.True(t, config.SkipPreflight.Bool)
+	assert.Less(t, strings.Index(string(b), `"Commitment"`), strings.Index(string(b), `"SkipPreflight"`), "keys should be sorted")
+
+	// A failed request leaves the existing file alone
+	assert.Error(t, client.GetSolanaChainConfig(cli.NewContext(nil, set, nil)))
+	after, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, b, after)
+	entries, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files should be left behind")
+}
+
+func TestClient_ConfigureSolanaChain_References(t *testing.T) {
+	t.Parallel()
+
+	configure := func(stub *stubHTTPClient, args ...string) error {
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		require.NoError(t, set.Parse(args))
+		return client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	}
+	current := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s","Commitment":null}}}}`
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, current),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{}}}`),
+	}}
+	require.NoError(t, configure(stub, "OCR2CacheTTL={{.TxTimeout}}", "TxTimeout=2m0s"))
+	require.Len(t, stub.requests, 2)
+	var patch struct {
+		Config db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &patch))
+	// References resolve against the current config, not the other updates
+	assert.Equal(t, time.Minute, patch.Config.OCR2CacheTTL.Duration())
+	assert.Equal(t, 2*time.Minute, patch.Config.TxTimeout.Duration())
+
+	stub = &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, current)}}
+	err := configure(stub, "Commitment={{.Commitment}}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to resolve Commitment={{.Commitment}}")
+	assert.Len(t, stub.requests, 1)
+}
+
+func TestConfirm(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name    string
+		answers []string
+		def     bool
+		want    error
+	}{
+		{"yes", []string{"y"}, false, nil},
+		{"no", []string{"No"}, true, cmd.ErrAborted},
+		{"default no", []string{""}, false, cmd.ErrAborted},
+		{"default yes", []string{""}, true, nil},
+		{"asked again", []string{"maybe", "yes"}, false, nil},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: tt.answers}
+			assert.Equal(t, tt.want, cmd.Confirm(prompter, "Delete devnet?", tt.def))
+			assert.Equal(t, len(tt.answers), prompter.Count)
+		})
+	}
+
+	assert.Equal(t, cmd.ErrNoTerminal, cmd.Confirm(&cltest.MockCountingPrompter{T: t, NotTerminal: true}, "Delete devnet?", true))
+}
+
+func TestConfirmBulkConfigure(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	changes := []string{"Commitment=finalized", "SkipPreflight=false"}
+	require.NoError(t, cmd.ConfirmBulkConfigure(&cltest.MockCountingPrompter{T: t, EnteredStrings: []string{"y"}}, &b, changes, []string{"devnet", "testnet"}))
+	assert.Equal(t, "About to apply {Commitment=finalized, SkipPreflight=false} to 2 chain(s): devnet, testnet.\n", b.String())
+
+	// Only the count of very many chains is shown
+	b.Reset()
+	ids := make([]string, 37)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("chain-%d", i)
+	}
+	assert.Equal(t, cmd.ErrAborted, cmd.ConfirmBulkConfigure(&cltest.MockCountingPrompter{T: t, EnteredStrings: []string{""}}, &b, changes, ids))
+	assert.Equal(t, "About to apply {Commitment=finalized, SkipPreflight=false} to 37 chain(s).\n", b.String())
+
+	assert.EqualError(t, cmd.ConfirmBulkConfigure(&cltest.MockCountingPrompter{T: t, NotTerminal: true}, &b, changes, ids),
+		"refusing to configure 37 chain(s) without confirmation: pass --yes")
+}
+
+func TestClient_ConfigureSolanaChain_SeveralIDs(t *testing.T) {
+	t.Parallel()
+
+	chain := func(id string) *http.Response {
+		return stubResponse(http.StatusOK, fmt.Sprintf(`{"data":{"type":"solana_chain","id":%q,"attributes":{"enabled":true}}}`, id))
+	}
+	stub := &stubHTTPClient{responses: []*http.Response{chain("devnet"), chain("devnet"), chain("testnet"), chain("testnet")}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.Var(&cli.StringSlice{}, "id", "")
+	set.Bool("yes", false, "")
+	require.NoError(t, set.Parse([]string{"-id", "devnet", "-id", "testnet", "-yes", "Commitment=finalized"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 4)
+	assert.Equal(t, "/v2/chains/solana/devnet", stub.requests[1].path)
+	assert.Equal(t, "/v2/chains/solana/testnet", stub.requests[3].path)
+	assert.Len(t, r.Renders, 2)
+}
+
+func TestClient_IndexSolanaChains_Cursors(t *testing.T) {
+	t.Parallel()
+
+	list := func(t *testing.T, body string, args ...string) (*stubHTTPClient, string, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, body)}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("after", "", "")
+		set.String("before", "", "")
+		require.NoError(t, set.Parse(args))
+		var b bytes.Buffer
+		err := client.RenderPageWithCursors(cli.NewContext(nil, set, nil), &b, "/v2/chains/solana?size=2", &cmd.SolanaChainPresenters{})
+		return stub, b.String(), err
+	}
+	const chains = `"data":[{"type":"solana_chain","id":"devnet","attributes":{}}]`
+
+	t.Run("cursors", func(t *testing.T) {
+		stub, out, err := list(t, `{`+chains+`,"links":{"next":"/v2/chains/solana?size=2&after=bTpkZXZuZXQ"}}`, "-after", "bTptYWlubmV0")
+		require.NoError(t, err)
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, "/v2/chains/solana?after=bTptYWlubmV0&size=2", stub.requests[0].path)
+		assert.Equal(t, "Next cursor: bTpkZXZuZXQ\n", out)
+
+		// Cursors are printed without --after too, to start from
+		_, out, err = list(t, `{`+chains+`,"links":{"next":"/v2/chains/solana?size=2&after=bTpkZXZuZXQ"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "Next cursor: bTpkZXZuZXQ\n", out)
+	})
+
+	t.Run("page numbers", func(t *testing.T) {
+		_, out, err := list(t, `{`+chains+`,"links":{"next":"/v2/chains/solana?page=2&size=2"}}`)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+
+		stub, out, err := list(t, `{`+chains+`,"links":{"next":"/v2/chains/solana?page=3&size=2","prev":"/v2/chains/solana?page=1&size=2"}}`, "-after", "page:2")
+		require.NoError(t, err)
+		assert.Equal(t, "/v2/chains/solana?page=2&size=2", stub.requests[0].path)
+		assert.Equal(t, "Next cursor: page:3\n", out)
+
+		_, out, err = list(t, `{`+chains+`,"links":{"next":"/v2/chains/solana?page=2&size=2"}}`, "-before", "page:2")
+		require.NoError(t, err)
+		assert.Equal(t, "No previous pages\nNext cursor: page:2\n", out)
+
+		_, out, err = list(t, `{`+chains+`}`, "-after", "page:2")
+		require.NoError(t, err)
+		assert.Equal(t, "No more pages\n", out)
+
+		_, _, err = list(t, `{`+chains+`}`, "-after", "page:0")
+		assert.EqualError(t, err, "invalid --after cursor 'page:0'")
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		set := flag.NewFlagSet("cli", 0)
+		set.String("after", "", "")
+		set.String("before", "", "")
+		require.NoError(t, set.Parse([]string{"-after", "a", "-before", "b"}))
+		err := (&cmd.Client{}).IndexSolanaChains(cli.NewContext(nil, set, nil))
+		assert.EqualError(t, err, "--after cannot be combined with --before")
+	})
+}
+
+func TestClient_CreateSolanaChain_DumpRequest(t *testing.T) {
+	t.Parallel()
+
+	create := func(t *testing.T, stub *stubHTTPClient, args ...string) (string, string) {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		path := filepath.Join(t.TempDir(), "req.json")
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("config-json", `{"Commitment":"finalized","SecretKeyPath":"/key"}`, "")
+		set.String("dump-request", path, "")
+		set.Bool("dry-run", false, "")
+		set.Var(&cli.StringSlice{}, "redact", "")
+		require.NoError(t, set.Parse(args))
+		require.NoError(t, client.CreateSolanaChain(cli.NewContext(nil, set, nil)))
+		// The file is missing if --dump-request was cleared
+		dumped, _ := ioutil.ReadFile(path)
+		return string(dumped), b.String()
+	}
+	const body = `{"chainID":"devnet","config":{"Commitment":"finalized","SecretKeyPath":"/key"}}`
+
+	t.Run("sent", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+		}}
+		dumped, _ := create(t, stub)
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, body, string(stub.requests[0].body))
+		assert.Equal(t, body+"\n", dumped)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		stub := &stubHTTPClient{}
+		dumped, _ := create(t, stub, "-dry-run")
+		assert.Empty(t, stub.requests)
+		assert.Equal(t, body+"\n", dumped)
+
+		// Without a file, the body is written to stdout
+		_, out := create(t, stub, "-dry-run", "-dump-request", "")
+		assert.Empty(t, stub.requests)
+		assert.Equal(t, body+"\n", out)
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		dumped, _ := create(t, &stubHTTPClient{}, "-dry-run", "-redact", "key")
+		assert.Equal(t, `{"chainID":"devnet","config":{"Commitment":"finalized","SecretKeyPath":"***"}}`+"\n", dumped)
+	})
+}
+
+func TestClient_ConfigureSolanaChain_DryRun(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+	path := filepath.Join(t.TempDir(), "req.json")
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("dump-request", path, "")
+	set.Bool("dry-run", true, "")
+	require.NoError(t, set.Parse([]string{"Commitment=finalized"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+
+	// The chain is fetched, but not patched
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, http.MethodGet, stub.requests[0].method)
+	assert.Empty(t, r.Renders)
+	dumped, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var patch struct {
+		Enabled bool        `json:"enabled"`
+		Config  db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(dumped, &patch))
+	assert.True(t, patch.Enabled)
+	assert.Equal(t, "finalized", patch.Config.Commitment.String)
+}
+
+func TestClient_IndexSolanaChains_Enabled(t *testing.T) {
+	t.Parallel()
+
+	list := func(t *testing.T, enabled string, body string) (*stubHTTPClient, *cltest.RendererMock, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, body)}}
+		r := &cltest.RendererMock{}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("enabled", "", "")
+		require.NoError(t, set.Parse([]string{"-enabled", enabled}))
+		err := (&cmd.Client{HTTP: stub, Renderer: r}).IndexSolanaChains(cli.NewContext(nil, set, nil))
+		return stub, r, err
+	}
+	ids := func(r *cltest.RendererMock) (ids []string) {
+		require.Len(t, r.Renders, 1)
+		for _, chain := range *r.Renders[0].(*cmd.SolanaChainPresenters) {
+			ids = append(ids, chain.ID)
+		}
+		return ids
+	}
+
+	// The filter is sent to the node, and applied again if the node ignores it
+	stub, r, err := list(t, "false", `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}},{"type":"solana_chain","id":"testnet","attributes":{"enabled":false}}]}`)
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/v2/chains/solana?enabled=false&size=100", stub.requests[0].path)
+	assert.Equal(t, []string{"testnet"}, ids(r))
+
+	_, r, err = list(t, "1", `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"devnet"}, ids(r))
+
+	_, _, err = list(t, "maybe", `{"data":[]}`)
+	assert.EqualError(t, err, "invalid --enabled 'maybe': must be true or false")
+}
+
+func TestClient_ApplySolanaChains(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "chains.jsonl")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"id":"new","enabled":true,"config":{"Commitment":"finalized"}}
+{"id":"same","enabled":true,"config":{"Commitment":"confirmed"}}
+{"id":"changed","enabled":false,"config":{"Commitment":"confirmed"}}
+{"id":"broken","enabled":true,"config":{}}
+`), 0600))
+	chain := func(id string, enabled bool) *http.Response {
+		return stubResponse(http.StatusOK, fmt.Sprintf(`{"data":{"type":"solana_chain","id":%q,"attributes":{"enabled":%t,"config":{"Commitment":"confirmed"}}}}`, id, enabled))
+	}
+	apply := func(t *testing.T, output string) (*stubHTTPClient, string, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusNotFound, `{"errors":[{"detail":"chain not found"}]}`),
+			stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"new","attributes":{"enabled":true}}}`),
+			chain("same", true),
+			chain("changed", true),
+			chain("changed", false),
+			stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`),
+		}}
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("file", path, "")
+		set.String("output", output, "")
+		err := client.ApplySolanaChains(cli.NewContext(nil, set, nil))
+		return stub, b.String(), err
+	}
+
+	stub, out, err := apply(t, "")
+	require.Error(t, err)
+	assert.Equal(t, "failed to apply 1 of 4 chains", err.Error())
+	assert.Equal(t, http.MethodPost, stub.requests[1].method)
+	assert.Equal(t, http.MethodPatch, stub.requests[4].method)
+	assert.Equal(t, "/v2/chains/solana/changed", stub.requests[4].path)
+	assert.Contains(t, out, "Chain new created\nChain same unchanged\nChain changed updated\nChain broken failed: ")
+	assert.True(t, strings.HasSuffix(out, "\n1 created, 1 updated, 1 unchanged, 1 failed\n"), out)
+
+	_, out, err = apply(t, "json")
+	require.Error(t, err)
+	assert.Equal(t, `{"created":1,"updated":1,"unchanged":1,"failed":1}`+"\n", out)
+
+	// Nothing is applied if any line of the file is invalid
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"id":"new","config":{}}
+{"id":" ","config":{}}
+`), 0600))
+	stub, _, err = apply(t, "")
+	assert.EqualError(t, err, path+": invalid chain 2: missing id")
+	assert.Empty(t, stub.requests)
+}
+
+func TestClient_SolanaChains_CheckMode(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, stub *stubHTTPClient, args ...string) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{Config: cltest.NewTestGeneralConfig(t), HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		app := cmd.NewApp(client)
+		app.ExitErrHandler = func(*cli.Context, error) {}
+		err := app.Run(append([]string{"chainlink", "--check", "chains", "solana"}, args...))
+		return b.String(), err
+	}
+
+	t.Run("create", func(t *testing.T) {
+		stub := &stubHTTPClient{}
+		out, err := run(t, stub, "create", "-id", "devnet", "--config-json", `{}`)
+		require.NoError(t, err)
+		assert.Equal(t, "Would create chain devnet\n", out)
+		assert.Empty(t, stub.requests)
+	})
+
+	t.Run("configure", func(t *testing.T) {
+		chain := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, chain)}}
+		out, err := run(t, stub, "configure", "-id", "devnet", "Commitment=finalized", "TxTimeout=1m")
+		require.NoError(t, err)
+		assert.Equal(t, "Would update chain devnet: Commitment, TxTimeout\n", out)
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, http.MethodGet, stub.requests[0].method)
+
+		stub = &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, chain)}}
+		out, err = run(t, stub, "configure", "-id", "devnet", "Commitment=confirmed")
+		require.NoError(t, err)
+		assert.Equal(t, "Would leave chain devnet unchanged\n", out)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		stub := &stubHTTPClient{}
+		out, err := run(t, stub, "delete", "devnet", "testnet")
+		require.NoError(t, err)
+		assert.Equal(t, "Would delete chain devnet\nWould delete chain testnet\n", out)
+		assert.Empty(t, stub.requests)
+	})
+
+	t.Run("other commands", func(t *testing.T) {
+		// Commands without their own --check handling still cannot mutate
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false}}}`),
+		}}
+		_, err := run(t, stub, "enable", "-id", "devnet")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refusing to PATCH /v2/chains/solana/devnet in --check mode")
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, http.MethodGet, stub.requests[0].method)
+	})
+}
+
+func TestClient_ShowSolanaChain_Tree(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	client := &cmd.Client{Renderer: cmd.RendererTable{Writer: &b}, HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s","Commitment":"confirmed","SecretKeyPath":"/key"}}}}`),
+	}}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("output", "tree", "")
+	require.NoError(t, set.Parse(nil))
+	require.NoError(t, client.ShowSolanaChain(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, "devnet (enabled)\n  Commitment: confirmed\n  TxTimeout: 1m0s\n", b.String())
+}
+
+func TestClient_ShowSolanaChain_IncludeNodes(t *testing.T) {
+	t.Parallel()
+
+	show := func(t *testing.T, responses ...*http.Response) (*stubHTTPClient, cmd.SolanaChainWithNodesPresenter) {
+		r := &cltest.RendererMock{}
+		stub := &stubHTTPClient{responses: responses}
+		client := &cmd.Client{Renderer: r, HTTP: stub}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("include", "nodes", "")
+		require.NoError(t, set.Parse(nil))
+		require.NoError(t, client.ShowSolanaChain(cli.NewContext(nil, set, nil)))
+		require.Len(t, r.Renders, 1)
+		return stub, *r.Renders[0].(*cmd.SolanaChainWithNodesPresenter)
+	}
+
+	t.Run("included", func(t *testing.T) {
+		stub, p := show(t, stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}},"included":[`+
+			`{"type":"solana_node","id":"1","attributes":{"name":"primary","solanaChainID":"devnet","solanaURL":"http://primary"}},`+
+			`{"type":"other","id":"2","attributes":{}}]}`))
+		require.Len(t, stub.requests, 1)
+		assert.Equal(t, "/v2/chains/solana/devnet?include=nodes", stub.requests[0].path)
+		assert.Equal(t, "devnet", p.ID)
+		require.Len(t, p.Nodes, 1)
+		assert.Equal(t, "primary", p.Nodes[0].Name)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		stub, p := show(t,
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+			stubResponse(http.StatusOK, `{"data":[{"type":"solana_node","id":"1","attributes":{"name":"backup","solanaChainID":"devnet"}}]}`),
+		)
+		require.Len(t, stub.requests, 2)
+		assert.Equal(t, "/v2/chains/solana/devnet/nodes", stub.requests[1].path)
+		require.Len(t, p.Nodes, 1)
+		assert.Equal(t, "backup", p.Nodes[0].Name)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		client := &cmd.Client{Renderer: &cltest.RendererMock{}, HTTP: &stubHTTPClient{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("include", "jobs", "")
+		require.NoError(t, set.Parse(nil))
+		err := client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported --include 'jobs'")
+	})
+}
+
+func TestSolanaChainPresenter_FormatConfig(t *testing.T) {
+	t.Parallel()
+
+	var chain cmd.SolanaChainPresenter
+	chain.ID = "devnet"
+	chain.Config = db.ChainCfg{Commitment: null.StringFrom("confirmed"), SkipPreflight: null.BoolFrom(true)}
+
+	pretty := "{\n    \"BalancePollPeriod\": null,\n    \"Commitment\": \"confirmed\",\n"
+	for _, tc := range []struct {
+		format, expected string
+	}{
+		{"", pretty},
+		{"pretty", pretty},
+		{"minified", `{"BalancePollPeriod":null,"Commitment":"confirmed",`},
+		{"compact", "Commitment, SkipPreflight"},
+	} {
+		row := chain.ToRow(cmd.RendererTable{Writer: ioutil.Discard, ConfigFormat: tc.format, SortConfigKeys: true})
+		assert.True(t, strings.HasPrefix(row[2], tc.expected), "%s: %s", tc.format, row[2])
+	}
+	assert.NotContains(t, chain.ToRow(cmd.RendererTable{Writer: ioutil.Discard, ConfigFormat: "minified"})[2], "\n")
+
+	var empty cmd.SolanaChainPresenter
+	row := empty.ToRow(cmd.RendererTable{Writer: ioutil.Discard, ConfigFormat: "compact"})
+	assert.Equal(t, "(empty)", row[2])
+}
+
+func TestClient_IndexSolanaChains_InvalidFormatConfig(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{}
+	app := cmd.NewApp(&cmd.Client{Config: cltest.NewTestGeneralConfig(t), HTTP: stub, Renderer: cmd.RendererTable{Writer: ioutil.Discard}})
+	app.ExitErrHandler = func(*cli.Context, error) {}
+	err := app.Run([]string{"chainlink", "chains", "solana", "list", "--format-config", "tiny"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --format-config 'tiny', options: [pretty, minified, compact]")
+	assert.Empty(t, stub.requests)
+}
+
+func TestApp_SolanaChainsAliases(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, `{"data":[]}`)}}
+	var b bytes.Buffer
+	app := cmd.NewApp(&cmd.Client{Config: cltest.NewTestGeneralConfig(t), HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}})
+	app.Writer = &b
+	require.NoError(t, app.Run([]string{"chainlink", "chains", "solana", "ls"}))
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/v2/chains/solana?size=100", stub.requests[0].path)
+
+	// The aliases are listed in the help
+	b.Reset()
+	require.NoError(t, app.Run([]string{"chainlink", "chains", "solana", "--help"}))
+	for _, names := range []string{"create, add", "delete, rm, remove", "list, ls", "configure, set"} {
+		assert.Contains(t, b.String(), names)
+	}
+}
+
+func TestClient_StatsSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	chains := `{"data":[` +
+		`{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{},"createdAt":"2022-02-01T00:00:00Z"}},` +
+		`{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{},"createdAt":"2022-01-01T00:00:00Z"}},` +
+		`{"type":"solana_chain","id":"mainnet","attributes":{"enabled":false,"config":{},"createdAt":"2022-03-01T00:00:00Z"}}]}`
+	stats := func(t *testing.T, health bool, responses ...*http.Response) cmd.SolanaChainStatsPresenter {
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: append([]*http.Response{stubResponse(http.StatusOK, chains)}, responses...)}, Renderer: r}
+		set := flag.NewFlagSet("cli", 0)
+		set.Bool("health", health, "")
+		require.NoError(t, set.Parse(nil))
+		require.NoError(t, client.StatsSolanaChains(cli.NewContext(nil, set, nil)))
+		require.Len(t, r.Renders, 1)
+		return *r.Renders[0].(*cmd.SolanaChainStatsPresenter)
+	}
+
+	p := stats(t, false)
+	assert.Equal(t, 3, p.Total)
+	assert.Equal(t, 2, p.Enabled)
+	assert.Equal(t, 1, p.Disabled)
+	assert.Greater(t, p.AvgConfigBytes, 0.0)
+	assert.Nil(t, p.ZeroAliveNodes)
+	require.NotNil(t, p.Oldest)
+	assert.Equal(t, "testnet", p.Oldest.ID)
+	require.NotNil(t, p.Newest)
+	assert.Equal(t, "mainnet", p.Newest.ID)
+
+	p = stats(t, true,
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_node","id":"1","attributes":{"name":"a","solanaChainID":"devnet"}},{"type":"solana_node","id":"2","attributes":{"name":"b","solanaChainID":"testnet"}}]}`),
+		stubResponse(http.StatusServiceUnavailable, `{"data":[`+
+			`{"type":"checks","id":"Solana.devnet.a","attributes":{"name":"Solana.devnet.a","status":"passing"}},`+
+			`{"type":"checks","id":"Solana.testnet.b","attributes":{"name":"Solana.testnet.b","status":"failing"}}]}`),
+	)
+	require.NotNil(t, p.ZeroAliveNodes)
+	assert.Equal(t, 2, *p.ZeroAliveNodes)
+}
+
+func TestEncodeChainBody(t *testing.T) {
+	t.Parallel()
+
+	attributes := map[string]interface{}{"chainID": "devnet", "config": map[string]interface{}{"Commitment": "finalized"}}
+	for _, tt := range []struct {
+		format, expected string
+	}{
+		{"", `{"chainID":"devnet","config":{"Commitment":"finalized"}}`},
+		{"plain", `{"chainID":"devnet","config":{"Commitment":"finalized"}}`},
+		{"jsonapi", `{"data":{"attributes":{"chainID":"devnet","config":{"Commitment":"finalized"}},"type":"solana_chain"}}`},
+	} {
+		b, err := cmd.EncodeChainBody(tt.format, "solana_chain", attributes)
+		require.NoError(t, err, tt.format)
+		assert.Equal(t, tt.expected, string(b), tt.format)
+	}
+
+	_, err := cmd.EncodeChainBody("xml", "solana_chain", attributes)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported chain body format 'xml'")
+}
+
+func TestClient_CreateSolanaChain_JSONAPIBody(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+	}}
+	path := filepath.Join(t.TempDir(), "req.json")
+	client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}, ChainBodyFormat: "jsonapi"}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("config-json", `{"Commitment":"finalized","SecretKeyPath":"/key"}`, "")
+	set.String("dump-request", path, "")
+	set.Var(&cli.StringSlice{}, "redact", "")
+	require.NoError(t, set.Parse([]string{"-redact", "key"}))
+	require.NoError(t, client.CreateSolanaChain(cli.NewContext(nil, set, nil)))
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, `{"data":{"attributes":{"chainID":"devnet","config":{"Commitment":"finalized","SecretKeyPath":"/key"}},"type":"solana_chain"}}`, string(stub.requests[0].body))
+
+	dumped, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":{"attributes":{"chainID":"devnet","config":{"Commitment":"finalized","SecretKeyPath":"***"}},"type":"solana_chain"}}`+"\n", string(dumped))
+}
+
+func TestTrimChainConfig(t *testing.T) {
+	t.Parallel()
+
+	trimmed, n, err := cmd.TrimChainConfig(json.RawMessage(`{"Commitment":"confirmed","TxTimeout":null,"Nested":{"A":null,"B":{}},"Nodes":[{"URL":null},null],"Count":0}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Commitment":"confirmed","Nodes":[{},null],"Count":0}`, string(trimmed))
+	assert.Equal(t, 5, n)
+
+	trimmed, n, err = cmd.TrimChainConfig(json.RawMessage(`{"TxTimeout":null}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(trimmed))
+	assert.Equal(t, 1, n)
+}
+
+func TestClient_CreateSolanaChain_TrimConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, trim := range []bool{false, true} {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("config-json", `{"Commitment":"finalized","TxTimeout":null}`, "")
+		set.Bool("trim-config", trim, "")
+		require.NoError(t, set.Parse(nil))
+		require.NoError(t, client.CreateSolanaChain(cli.NewContext(nil, set, nil)))
+		require.Len(t, stub.requests, 1)
+		expected := `{"chainID":"devnet","config":{"Commitment":"finalized","TxTimeout":null}}`
+		if trim {
+			expected = `{"chainID":"devnet","config":{"Commitment":"finalized"}}`
+		}
+		assert.Equal(t, expected, string(stub.requests[0].body))
+	}
+}
+
+func TestClient_ReconcileSolanaChains(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "devnet.json"), []byte(`{"Commitment":"finalized"}`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "mainnet.json"), []byte(`{}`), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte(`not a chain`), 0600))
+	list := `{"data":[` +
+		`{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"confirmed"}}},` +
+		`{"type":"solana_chain","id":"testnet","attributes":{"enabled":true,"config":{}}}]}`
+	reconcile := func(t *testing.T, stub *stubHTTPClient, yes bool) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("dir", dir, "")
+		set.Bool("prune", true, "")
+		set.Bool("yes", yes, "")
+		require.NoError(t, set.Parse(nil))
+		err := client.ReconcileSolanaChains(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+	const plan = "~ update devnet: Commitment\n+ create mainnet\n- delete testnet\n"
+
+	t.Run("unconfirmed", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, list)}}
+		out, err := reconcile(t, stub, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refusing to apply 3 change(s) without confirmation: pass --yes")
+		assert.Equal(t, plan, out)
+		assert.Len(t, stub.requests, 1)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, list)}}
+		prompter := &cltest.MockCountingPrompter{T: t, EnteredStrings: []string{"n"}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: ioutil.Discard}, ConfirmPrompter: prompter}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("dir", dir, "")
+		set.Bool("prune", true, "")
+		require.NoError(t, set.Parse(nil))
+		err := client.ReconcileSolanaChains(cli.NewContext(nil, set, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), cmd.ErrAborted.Error())
+		assert.Equal(t, 1, prompter.Count)
+		assert.Len(t, stub.requests, 1)
+	})
+
+	t.Run("applied", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, list),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"confirmed"}}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"finalized"}}}}`),
+			stubResponse(http.StatusNotFound, `{"errors":[{"detail":"chain not found"}]}`),
+			stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"mainnet","attributes":{"enabled":true,"config":{}}}}`),
+			stubResponse(http.StatusNoContent, ``),
+		}}
+		out, err := reconcile(t, stub, true)
+		require.NoError(t, err)
+		assert.Equal(t, plan+"Chain devnet updated\nChain mainnet created\nChain testnet deleted\n1 created, 1 updated, 0 unchanged, 1 deleted, 0 failed\n", out)
+		require.Len(t, stub.requests, 6)
+		assert.Equal(t, http.MethodPatch, stub.requests[2].method)
+		assert.JSONEq(t, `{"enabled":false,"config":{"BalancePollPeriod":null,"ConfirmPollPeriod":null,"OCR2CachePollPeriod":null,"OCR2CacheTTL":null,"TxTimeout":null,"SkipPreflight":null,"Commitment":"finalized"}}`, string(stub.requests[2].body))
+		assert.Equal(t, http.MethodPost, stub.requests[4].method)
+		assert.Equal(t, http.MethodDelete, stub.requests[5].method)
+		assert.Equal(t, "/v2/chains/solana/testnet", stub.requests[5].path)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, `{"data":[`+
+			`{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized"}}},`+
+			`{"type":"solana_chain","id":"mainnet","attributes":{"enabled":true,"config":{}}}]}`)}}
+		out, err := reconcile(t, stub, false)
+		require.NoError(t, err)
+		assert.Equal(t, "Nothing to reconcile, all 2 chains match "+dir+"\n", out)
+	})
+}
+
+func TestClient_IndexSolanaChains_NamesOnly(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "devnet (Dev cluster)", cmd.ChainDisplayName("devnet", json.RawMessage(`{"Name":"Dev cluster","Commitment":"confirmed"}`)))
+	assert.Equal(t, "devnet (Dev)", cmd.ChainDisplayName("devnet", json.RawMessage(`{"displayName":"Dev"}`)))
+	assert.Equal(t, "devnet", cmd.ChainDisplayName("devnet", json.RawMessage(`{"Name":""}`)))
+	assert.Equal(t, "devnet", cmd.ChainDisplayName("devnet", db.ChainCfg{}))
+
+	var b bytes.Buffer
+	client := &cmd.Client{Renderer: cmd.RendererTable{Writer: &b}, HTTP: &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"config":{}}},{"type":"solana_chain","id":"testnet","attributes":{"config":{}}}]}`),
+	}}}
+	set := flag.NewFlagSet("cli", 0)
+	set.Bool("names-only", true, "")
+	require.NoError(t, set.Parse(nil))
+	require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, "devnet\ntestnet\n", b.String())
+}
+
+func TestClient_CreateSolanaChain_StreamLargeConfig(t *testing.T) {
+	t.Parallel()
+
+	var config bytes.Buffer
+	config.WriteString(`{"Commitment": "confirmed", "Allowlist": [`)
+	for i := 0; config.Len() < 2<<20; i++ {
+		if i > 0 {
+			config.WriteString(",")
+		}
+		fmt.Fprintf(&config, `"address-%08d"`, i)
+	}
+	config.WriteString("]}\n")
+
+	create := func(t *testing.T, content []byte) (*stubHTTPClient, error) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, ioutil.WriteFile(path, content, 0600))
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.Var(&cli.StringSlice{}, "config-file", "")
+		require.NoError(t, set.Parse([]string{"-config-file", path}))
+		return stub, client.CreateSolanaChain(cli.NewContext(nil, set, nil))
+	}
+
+	stub, err := create(t, config.Bytes())
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 1)
+	body := stub.requests[0].body
+	assert.Equal(t, `{"chainID":"devnet","config":`+config.String()+`}`, string(body))
+	assert.True(t, json.Valid(body))
+
+	_, err = create(t, config.Bytes()[:config.Len()-3])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not valid JSON: unexpected end of file")
+}
+
+func TestClient_StatsSolanaChains_Markdown(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}]}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("output", "markdown", "")
+	require.NoError(t, set.Parse(nil))
+	require.NoError(t, client.StatsSolanaChains(cli.NewContext(nil, set, nil)))
+	assert.Regexp(t, `(?m)^\| +Metric +\| +Value +\|$`, b.String())
+	assert.Regexp(t, `(?m)^\|-+\|-+\|$`, b.String())
+	assert.NotContains(t, b.String(), "║")
+}
+
+func TestSolanaChainPresenters_Markdown(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	ps := cmd.SolanaChainPresenters{{
+		SolanaChainResource: presenters.SolanaChainResource{
+			JAID:    presenters.NewJAID("devnet"),
+			Enabled: true,
+			Config:  db.ChainCfg{Commitment: null.StringFrom("a|b")},
+		},
+	}}
+	require.NoError(t, ps.RenderTable(cmd.RendererTable{Writer: &b, Markdown: true, ConfigFormat: "pretty"}))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "| ID | Enabled | Config | Created | Updated |", lines[0])
+	assert.Equal(t, "|---|---:|---|---|---|", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], `| devnet | true | {"BalancePollPeriod":null,`), lines[2])
+	assert.Contains(t, lines[2], `"Commitment":"a\|b",`)
+}
+
+func TestSolanaChainWithNodesPresenter_Markdown(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	p := cmd.SolanaChainWithNodesPresenter{
+		SolanaChainPresenter: cmd.SolanaChainPresenter{SolanaChainResource: presenters.SolanaChainResource{JAID: presenters.NewJAID("devnet")}},
+		Nodes: cmd.SolanaNodePresenters{{SolanaNodeResource: presenters.SolanaNodeResource{
+			JAID:          presenters.NewJAID("1"),
+			Name:          "primary",
+			SolanaChainID: "devnet",
+			SolanaURL:     "http://solana",
+		}}},
+	}
+	require.NoError(t, p.RenderTable(cmd.RendererTable{Writer: &b, Markdown: true}))
+	// The nodes are a Markdown table like the chain, rather than a list
+	assert.NotContains(t, b.String(), "-----")
+	assert.Contains(t, b.String(), "| ID | Name | Chain ID | URL | Created | Updated |\n|---|---|---|---|---|---|\n| 1 | primary | devnet | http://solana |")
+}
+
+func TestClient_GetSolanaChainConfigValue(t *testing.T) {
+	t.Parallel()
+
+	get := func(t *testing.T, key string, flags ...string) (string, error) {
+		var b bytes.Buffer
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("key", key, "")
+		set.String("default", "", "")
+		set.Bool("required", false, "")
+		require.NoError(t, set.Parse(flags))
+		err := client.GetSolanaChainConfigValue(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	out, err := get(t, "Commitment")
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed\n", out)
+
+	// Null fields fall back to the default
+	out, err = get(t, "SkipPreflight", "-default", "true")
+	require.NoError(t, err)
+	assert.Equal(t, "true\n", out)
+
+	out, err = get(t, "RPC.URL")
+	require.NoError(t, err)
+	assert.Equal(t, "\n", out)
+
+	_, err = get(t, "SkipPreflight", "-required")
+	assert.EqualError(t, err, "chain devnet: config field SkipPreflight is not set")
+	_, err = get(t, "RPC.URL", "-required")
+	assert.EqualError(t, err, "chain devnet: config field RPC not found")
+}
+
+func TestClient_TouchSolanaChain(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"confirmed"},"updatedAt":"2022-01-01T00:00:00Z"}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":false,"config":{"Commitment":"confirmed"},"updatedAt":"2022-01-02T00:00:00Z"}}}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	require.NoError(t, set.Parse(nil))
+	require.NoError(t, client.TouchSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	var sent struct {
+		Enabled bool        `json:"enabled"`
+		Config  db.ChainCfg `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &sent))
+	assert.False(t, sent.Enabled)
+	assert.Equal(t, "confirmed", sent.Config.Commitment.String)
+	assert.Contains(t, b.String(), "Touched chain devnet, updated at 2022-01-02")
+}
+
+func TestClient_SolanaChains_AssumeYes(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, args ...string) (*stubHTTPClient, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{}},{"type":"solana_chain","id":"mainnet","attributes":{}}]}`),
+			stubResponse(http.StatusNoContent, ``),
+		}}
+		client := &cmd.Client{Config: cltest.NewTestGeneralConfig(t), HTTP: stub, Renderer: cmd.RendererTable{Writer: ioutil.Discard}}
+		app := cmd.NewApp(client)
+		app.ExitErrHandler = func(*cli.Context, error) {}
+		return stub, app.Run(append([]string{"chainlink"}, args...))
+	}
+
+	for _, yes := range []string{"--assume-yes", "-y"} {
+		stub, err := run(t, yes, "chains", "solana", "delete", "--match", "dev*")
+		require.NoError(t, err, yes)
+		require.Len(t, stub.requests, 2, yes)
+		assert.Equal(t, http.MethodDelete, stub.requests[1].method)
+		assert.Equal(t, "/v2/chains/solana/devnet", stub.requests[1].path)
+	}
+
+	// Without a terminal to prompt on, nothing is deleted otherwise
+	stub, err := run(t, "chains", "solana", "delete", "--match", "dev*")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "without confirmation")
+	assert.Len(t, stub.requests, 1)
+}
+
+func TestClient_SolanaChainConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := func(onlyFields string) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("only-fields", onlyFields, "")
+		require.NoError(t, set.Parse(nil))
+		err := client.SolanaChainConfigSchema(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	out, err := schema("")
+	require.NoError(t, err)
+	var full struct {
+		Type       string                            `json:"type"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &full))
+	assert.Equal(t, "object", full.Type)
+	assert.Len(t, full.Properties, 7)
+	assert.Equal(t, "boolean", full.Properties["SkipPreflight"]["type"])
+
+	out, err = schema("TxTimeout, Commitment")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{
+		"TxTimeout":{"type":"string","format":"duration","description":"How long to wait for a broadcast transaction to be confirmed before giving up on it."},
+		"Commitment":{"type":"string","description":"The commitment level for reading chain state, one of processed, confirmed or finalized."}}}`, out)
+
+	_, err = schema("RPC,Commitment")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown field 'RPC' in --only-fields, valid fields: BalancePollPeriod, Commitment, ConfirmPollPeriod, OCR2CachePollPeriod, OCR2CacheTTL, SkipPreflight, TxTimeout")
+}
+
+func TestClient_ExplainSolanaChainConfigField(t *testing.T) {
+	t.Parallel()
+
+	explain := func(args ...string) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		require.NoError(t, set.Parse(args))
+		err := client.ExplainSolanaChainConfigField(cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	out, err := explain("txtimeout")
+	require.NoError(t, err)
+	assert.Equal(t, `Field:    TxTimeout
+Type:     duration (e.g. 30s)
+Default:  1m0s
+Doc:      How long to wait for a broadcast transaction to be confirmed before giving up on it.
+`, out)
+
+	out, err = explain("SkipPreflight")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Type:     bool\nDefault:  true\n")
+
+	_, err = explain("Timeout")
+	assert.EqualError(t, err, "unknown field 'Timeout'; did you mean TxTimeout?")
+	_, err = explain("Commitment.Level")
+	assert.EqualError(t, err, "field Commitment has no fields, so Commitment.Level is not a field")
+	_, err = explain()
+	assert.EqualError(t, err, "must pass the name of the config field to explain")
+
+	// Every field is documented
+	cfgType := reflect.TypeOf(db.ChainCfg{})
+	for i := 0; i < cfgType.NumField(); i++ {
+		name := cfgType.Field(i).Name
+		out, err = explain(name)
+		require.NoError(t, err)
+		assert.NotContains(t, out, "(undocumented)", name)
+	}
+}
+
+func TestExplainConfigField_Nested(t *testing.T) {
+	t.Parallel()
+
+	type rpcConfig struct {
+		Timeout *models.Duration `doc:"How long to wait for RPC responses."`
+	}
+	type chainConfig struct {
+		RPC rpcConfig
+	}
+	var b bytes.Buffer
+	require.NoError(t, cmd.ExplainConfigField(&b, chainConfig{}, chainConfig{}, nil, "RPC.Timeout"))
+	assert.Equal(t, "Field:    RPC.Timeout\nType:     duration (e.g. 30s)\nDefault:  (none)\nDoc:      How long to wait for RPC responses.\n", b.String())
+
+	b.Reset()
+	require.NoError(t, cmd.ExplainConfigField(&b, chainConfig{}, chainConfig{}, nil, "RPC"))
+	assert.Contains(t, b.String(), "Type:     object\n")
+	assert.EqualError(t, cmd.ExplainConfigField(&b, chainConfig{}, chainConfig{}, nil, "RPC.Timeuot"), "unknown field 'RPC.Timeuot'; did you mean Timeout?")
+}
+
+func TestClient_ConfigureSolanaChain_NoContent(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"}}}}`),
+		stubResponse(http.StatusNoContent, ``),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized"}}}}`),
+	}}
+	r := &cltest.RendererMock{}
+	client := &cmd.Client{HTTP: stub, Renderer: r}
+
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	require.NoError(t, set.Parse([]string{"Commitment=finalized"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+
+	// The chain is fetched again, as the PATCH response has no body
+	require.Len(t, stub.requests, 3)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	assert.Equal(t, http.MethodGet, stub.requests[2].method)
+	assert.Equal(t, "/v2/chains/solana/devnet", stub.requests[2].path)
+	require.Len(t, r.Renders, 1)
+	assert.Equal(t, "finalized", r.Renders[0].(*cmd.SolanaChainPresenter).Config.Commitment.String)
+}
+
+func TestClient_ConfigureSolanaChain_AssertApplied(t *testing.T) {
+	t.Parallel()
+
+	configure := func(returned string, args ...string) error {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":`+returned+`}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.Bool("assert-applied", true, "")
+		require.NoError(t, set.Parse(args))
+		return client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	}
+
+	// Fields which were not changed are not checked
+	require.NoError(t, configure(`{"Commitment":"finalized","SkipPreflight":true}`, "Commitment=finalized"))
+
+	err := configure(`{"Commitment":"confirmed","TxTimeout":"1m0s"}`, "Commitment=finalized", "TxTimeout=2m", "SkipPreflight=true")
+	assert.EqualError(t, err, "chain devnet was updated, but the node did not apply: Commitment, SkipPreflight, TxTimeout")
+}
+
+func TestClient_ShowSolanaChain_ServerWarnings(t *testing.T) {
+	t.Parallel()
+
+	var warnings bytes.Buffer
+	resp := stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"config":{}}},"meta":{"warnings":[{"detail":"chain devnet is scheduled for removal"}]}}`)
+	resp.Header.Add("Warning", `299 - "config field Commitment is deprecated" "Thu, 01 Jan 2022 00:00:00 GMT"`)
+	resp.Header.Add("Warning", "unformatted warning")
+	client := &cmd.Client{HTTP: &stubHTTPClient{responses: []*http.Response{resp}}, Renderer: cmd.RendererTable{Writer: ioutil.Discard}, ServerWarnings: &warnings}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	require.NoError(t, client.ShowSolanaChain(cli.NewContext(nil, set, nil)))
+	assert.Equal(t, "WARNING: config field Commitment is deprecated\nWARNING: unformatted warning\nWARNING: chain devnet is scheduled for removal\n", warnings.String())
+}
+
+func TestClient_IndexSolanaChains_RestoresRenderer(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}]}`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+	index := func(output string) {
+		set := flag.NewFlagSet("cli", 0)
+		set.String("output", output, "")
+		require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)))
+	}
+
+	index("json")
+	assert.True(t, json.Valid(b.Bytes()), b.String())
+	assert.IsType(t, cmd.RendererTable{}, client.Renderer)
+
+	// The next command on the same client renders in its own format
+	b.Reset()
+	index("")
+	assert.False(t, json.Valid(b.Bytes()), b.String())
+	assert.Contains(t, b.String(), "devnet")
+}
+
+func TestClient_IndexSolanaChains_RedactsJSON(t *testing.T) {
+	t.Parallel()
+
+	index := func(showSecrets bool) map[string]interface{} {
+		var b bytes.Buffer
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","TxTimeout":"1m"}}}]}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererJSON{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.Bool("show-secrets", showSecrets, "")
+		set.Var(&cli.StringSlice{}, "redact", "")
+		require.NoError(t, set.Set("redact", "commitment"))
+		require.NoError(t, client.IndexSolanaChains(cli.NewContext(nil, set, nil)))
+
+		var chains []map[string]interface{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &chains), b.String())
+		require.Len(t, chains, 1)
+		return chains[0]["config"].(map[string]interface{})
+	}
+
+	config := index(false)
+	assert.Equal(t, "***", config["Commitment"])
+	assert.Equal(t, "1m0s", config["TxTimeout"])
+
+	config = index(true)
+	assert.Equal(t, "confirmed", config["Commitment"])
+}
+
+func TestClient_ConfigureSolanaChain_ServerWarningsJSON(t *testing.T) {
+	t.Parallel()
+
+	var out, warnings bytes.Buffer
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized"}}}}`, "Warning", `299 - "config field Commitment is deprecated"`),
+	}}
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &out}, ServerWarnings: &warnings}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	set.String("output", "json", "")
+	require.NoError(t, set.Parse([]string{"Commitment=finalized"}))
+	require.NoError(t, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)))
+
+	// In JSON mode, the warnings of the node are part of the output
+	assert.Empty(t, warnings.String())
+	var rendered struct{ Warnings []string }
+	require.NoError(t, json.Unmarshal(out.Bytes(), &rendered))
+	assert.Equal(t, []string{"config field Commitment is deprecated"}, rendered.Warnings)
+}
+
+func TestClient_UnsetSolanaChainConfig(t *testing.T) {
+	t.Parallel()
+
+	chain := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed","SkipPreflight":true,"TxTimeout":"1m0s"}}}}`
+	unset := func(ignoreMissing bool, args ...string) (*stubHTTPClient, *cltest.RendererMock, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, chain),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s"}}}}`),
+		}}
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: stub, Renderer: r}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.Bool("ignore-missing", ignoreMissing, "")
+		require.NoError(t, set.Parse(args))
+		return stub, r, client.UnsetSolanaChainConfig(cli.NewContext(nil, set, nil))
+	}
+
+	stub, r, err := unset(false, "Commitment", "SkipPreflight")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	assert.JSONEq(t, `{"enabled":true,"config":{"BalancePollPeriod":null,"ConfirmPollPeriod":null,"OCR2CachePollPeriod":null,"OCR2CacheTTL":null,"TxTimeout":"1m0s"}}`, string(stub.requests[1].body))
+	require.Len(t, r.Renders, 1)
+	assert.False(t, r.Renders[0].(*cmd.SolanaChainPresenter).Config.Commitment.Valid)
+
+	// Fields which are not set fail, unless ignored
+	stub, _, err = unset(false, "BalancePollPeriod")
+	assert.EqualError(t, err, "chain devnet: config field BalancePollPeriod is not set")
+	assert.Len(t, stub.requests, 1)
+	_, _, err = unset(true, "BalancePollPeriod", "Commitment")
+	require.NoError(t, err)
+
+	_, _, err = unset(false)
+	assert.EqualError(t, err, "must pass at least one config field to unset (usage: chainlink solana chains config-unset -id string key1 [key2 ...])")
+}
+
+func TestClient_RemoveSolanaChain_NoContent(t *testing.T) {
+	t.Parallel()
+
+	stub := &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusNoContent, ``)}}
+	var b bytes.Buffer
+	client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &b}}
+
+	set := flag.NewFlagSet("cli", 0)
+	require.NoError(t, set.Parse([]string{"devnet"}))
+	require.NoError(t, client.RemoveSolanaChain(cli.NewContext(nil, set, nil)))
+
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, http.MethodDelete, stub.requests[0].method)
+	assert.Equal(t, "Chain devnet deleted\n", b.String())
+}
+
+func TestClient_ConfigureSolanaChain_NotFound(t *testing.T) {
+	t.Parallel()
+
+	configure := func(responses ...*http.Response) error {
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: responses}, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnte", "")
+		require.NoError(t, set.Parse([]string{"SkipPreflight=false"}))
+		return client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	}
+	notFound := func() *http.Response {
+		return stubResponse(http.StatusNotFound, `{"errors":[{"detail":"chain not found"}]}`)
+	}
+
+	err := configure(notFound(), stubResponse(http.StatusOK, `{"data":[
+		{"type":"solana_chain","id":"testnet","attributes":{}},
+		{"type":"solana_chain","id":"devnet-2","attributes":{}},
+		{"type":"solana_chain","id":"devnet","attributes":{}}
+	]}`))
+	assert.EqualError(t, err, "chain devnte not found; similar chain IDs: devnet-2, devnet")
+
+	err = configure(notFound(), stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"mainnet","attributes":{}}]}`))
+	assert.EqualError(t, err, "chain devnte not found")
+}
+
+// unreachableHTTPClient fails every GET as if the node could not be reached.
+type unreachableHTTPClient struct {
+	stubHTTPClient
+}
+
+func (h *unreachableHTTPClient) Get(path string, _ ...map[string]string) (*http.Response, error) {
+	return nil, &url.Error{Op: "Get", URL: "http://localhost:6688" + path, Err: errors.New("connection refused")}
+}
+
+func TestClient_ShowSolanaChain_ExitCodes(t *testing.T) {
+	t.Parallel()
+
+	chain := `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`
+	for _, tt := range []struct {
+		name string
+		http cmd.HTTPClient
+		code int
+	}{
+		{"success", &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusOK, chain)}}, 0},
+		{"generic", &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusInternalServerError, `{"errors":[{"detail":"boom"}]}`)}}, cmd.ExitCodeError},
+		{"not found", &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusNotFound, `{"errors":[{"detail":"chain not found"}]}`),
+			stubResponse(http.StatusOK, `{"data":[]}`),
+		}}, cmd.ExitCodeNotFound},
+		{"unauthorized", &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusUnauthorized, `{"errors":[{"detail":"Unauthorized"}]}`)}}, cmd.ExitCodeAuth},
+		{"forbidden", &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusForbidden, `{"errors":[{"detail":"Forbidden"}]}`)}}, cmd.ExitCodeAuth},
+		{"validation", &stubHTTPClient{responses: []*http.Response{stubResponse(http.StatusUnprocessableEntity, `{"errors":[{"detail":"invalid chain ID"}]}`)}}, cmd.ExitCodeValidation},
+		{"network", &unreachableHTTPClient{}, cmd.ExitCodeNetwork},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &cmd.Client{HTTP: tt.http, Renderer: cmd.RendererTable{Writer: ioutil.Discard}}
+			set := flag.NewFlagSet("cli", 0)
+			set.String("id", "devnet", "")
+			err := client.ShowSolanaChain(cli.NewContext(nil, set, nil))
+			if tt.code == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var coder cli.ExitCoder
+			require.True(t, errors.As(err, &coder), "%v is not an exit coder", err)
+			assert.Equal(t, tt.code, coder.ExitCode())
+		})
+	}
+}
+
+func TestClient_ErrorOut_ExitCodes(t *testing.T) {
+	t.Parallel()
+
+	client := &cmd.Client{}
+	for _, tt := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"generic", errors.New("boom"), cmd.ExitCodeError},
+		{"timeout", fmt.Errorf("listing chains: %w", context.DeadlineExceeded), cmd.ExitCodeNetwork},
+		// Codes survive wrapping, and commands calling errorOut on errors
+		// which were already translated
+		{"wrapped", fmt.Errorf("chain devnet: %w", cmd.WithExitCode(errors.New("not found"), cmd.ExitCodeNotFound)), cmd.ExitCodeNotFound},
+		{"nested", client.ErrorOut(cmd.WithExitCode(errors.New("invalid"), cmd.ExitCodeValidation)), cmd.ExitCodeValidation},
+	} {
+		var coder cli.ExitCoder
+		require.True(t, errors.As(client.ErrorOut(tt.err), &coder), tt.name)
+		assert.Equal(t, tt.code, coder.ExitCode(), tt.name)
+	}
+	assert.NoError(t, client.ErrorOut(nil))
+}
+
+func TestClient_RenderSolanaChainConfigTemplate(t *testing.T) {
+	t.Parallel()
+
+	render := func(preset, output string, args ...string) (string, error) {
+		var out bytes.Buffer
+		client := &cmd.Client{Renderer: cmd.RendererTable{Writer: &out}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("preset", preset, "")
+		set.String("output", output, "")
+		require.NoError(t, set.Parse(args))
+		err := client.RenderSolanaChainConfigTemplate(cli.NewContext(nil, set, nil))
+		return out.String(), err
+	}
+
+	out, err := render("devnet", "json", "TxTimeout=1m", "skippreflight=false", "Commitment=null")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"SkipPreflight": false, "TxTimeout": "1m"}`, out)
+
+	out, err = render("localnet", "yaml", "Commitment=confirmed")
+	require.NoError(t, err)
+	assert.Equal(t, "Commitment: confirmed\nConfirmPollPeriod: 100ms\nSkipPreflight: true\nTxTimeout: 10s\n", out)
+
+	_, err = render("", "json")
+	assert.EqualError(t, err, "must pass --preset, options: devnet, localnet, mainnet-base")
+	_, err = render("nope", "json")
+	assert.EqualError(t, err, "unknown preset 'nope', options: devnet, localnet, mainnet-base")
+	_, err = render("devnet", "toml")
+	assert.EqualError(t, err, "unsupported output format 'toml', options: json, yaml")
+	_, err = render("devnet", "json", "TxTimeot=1m")
+	assert.EqualError(t, err, "invalid parameter TxTimeot=1m: unknown field 'TxTimeot'; did you mean TxTimeout?")
+	_, err = render("devnet", "json", "TxTimeout=soon")
+	assert.Error(t, err)
+}
+
+func TestClient_ConfigureSolanaChain_ResetToDefault(t *testing.T) {
+	t.Parallel()
+
+	configure := func(resets []string, args ...string) (*stubHTTPClient, []string, error) {
+		var out bytes.Buffer
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized","TxTimeout":"5m0s"}}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: &out}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("output", "json", "")
+		reset := cli.StringSlice(resets)
+		set.Var(&reset, "reset-to-default", "")
+		require.NoError(t, set.Parse(args))
+		if err := client.ConfigureSolanaChain(cli.NewContext(nil, set, nil)); err != nil {
+			return stub, nil, err
+		}
+		var rendered struct{ Warnings []string }
+		require.NoError(t, json.Unmarshal(out.Bytes(), &rendered))
+		return stub, rendered.Warnings, nil
+	}
+
+	stub, reported, err := configure([]string{"txtimeout", "Commitment"}, "SkipPreflight=true")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, "PATCH", stub.requests[1].method)
+	var sent struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &sent))
+	assert.Equal(t, "1m0s", sent.Config["TxTimeout"])
+	assert.Equal(t, "confirmed", sent.Config["Commitment"])
+	assert.Equal(t, true, sent.Config["SkipPreflight"])
+	assert.Equal(t, []string{`Resetting Commitment to its default "confirmed"`, `Resetting TxTimeout to its default "1m0s"`}, reported)
+
+	_, _, err = configure([]string{"TxTimeot"})
+	assert.EqualError(t, err, "invalid --reset-to-default: unknown config field 'TxTimeot'; did you mean TxTimeout?")
+	_, _, err = configure([]string{"TxTimeout"}, "TxTimeout=2m")
+	assert.EqualError(t, err, "config field TxTimeout cannot be both set and reset to its default")
+}
+
+func TestConfigFieldDefaults(t *testing.T) {
+	t.Parallel()
+
+	type cfg struct {
+		Timeout *string
+		Name    *string
+	}
+	timeout := "1m"
+	defaults, err := cmd.ConfigFieldDefaults([]string{"timeout"}, cfg{}, cfg{Timeout: &timeout})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Timeout": "1m"}, defaults)
+
+	_, err = cmd.ConfigFieldDefaults([]string{"Name"}, cfg{}, cfg{Timeout: &timeout})
+	assert.EqualError(t, err, "config field Name has no known default")
+}
+
+func TestClient_ChainsSelfTest(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	client := &cmd.Client{Config: cltest.NewTestGeneralConfig(t), Renderer: cmd.RendererTable{Writer: &b}}
+	require.NoError(t, client.ChainsSelfTest(cli.NewContext(nil, flag.NewFlagSet("cli", 0), nil)))
+	assert.Equal(t, "ok  create\nok  show\nok  configure\nok  delete\nok  show deleted\nSelf-test passed\n", b.String())
+}
+
+func TestClient_ErrorOut_PrettyErrors(t *testing.T) {
+	t.Parallel()
+
+	cfg := cltest.NewTestGeneralConfig(t)
+	client := &cmd.Client{Config: cfg, PrettyErrors: true}
+	refused := &url.Error{Op: "Get", URL: cfg.ClientNodeURL() + "/v2/chains/solana", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}
+	auth := cmd.WithExitCode(errors.New("Unauthorized"), cmd.ExitCodeAuth)
+
+	assert.EqualError(t, client.ErrorOut(refused), refused.Error()+
+		"\nHint: check that the node is running, and that the host and port of its URL are right (CLIENT_NODE_URL is "+cfg.ClientNodeURL()+")")
+	assert.EqualError(t, client.ErrorOut(auth),
+		"Unauthorized\nHint: your session may have expired: log in again with 'chainlink admin login', or pass --auth-token")
+	// Errors already translated by a nested errorOut get no more hints
+	assert.EqualError(t, client.ErrorOut(client.ErrorOut(auth)),
+		"Unauthorized\nHint: your session may have expired: log in again with 'chainlink admin login', or pass --auth-token")
+	assert.EqualError(t, client.ErrorOut(errors.New("boom")), "boom")
+	assert.EqualError(t, (&cmd.Client{}).ErrorOut(auth), "Unauthorized")
+
+	// A 422 on an enum field lists its values
+	stub := &stubHTTPClient{responses: []*http.Response{
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}}`),
+		stubResponse(http.StatusUnprocessableEntity, `{"errors":[{"detail":"invalid Commitment: final"}]}`),
+	}}
+	client = &cmd.Client{HTTP: stub, Renderer: cmd.RendererTable{Writer: ioutil.Discard}, PrettyErrors: true}
+	set := flag.NewFlagSet("cli", 0)
+	set.String("id", "devnet", "")
+	require.NoError(t, set.Parse([]string{"Commitment=final"}))
+	err := client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Commitment: final")
+	assert.True(t, strings.HasSuffix(err.Error(), "\nHint: Commitment must be one of: processed, confirmed, finalized"), err.Error())
+}
+
+func TestClient_ConfigureSolanaChain_FromShow(t *testing.T) {
+	t.Parallel()
+
+	configure := func(source string, responses ...*http.Response) (*stubHTTPClient, *cltest.RendererMock, error) {
+		stub := &stubHTTPClient{responses: responses}
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: stub, Renderer: r}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "prod", "")
+		set.String("from-show", source, "")
+		require.NoError(t, set.Parse([]string{"SkipPreflight=false"}))
+		return stub, r, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	}
+
+	stub, r, err := configure("staging",
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"staging","attributes":{"enabled":true,"config":{"Commitment":"finalized","SkipPreflight":true}}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+		stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"Commitment":"finalized","SkipPreflight":false}}}}`),
+	)
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 3)
+	assert.Equal(t, "/v2/chains/solana/staging", stub.requests[0].path)
+	assert.Equal(t, "/v2/chains/solana/prod", stub.requests[1].path)
+	assert.Equal(t, "PATCH", stub.requests[2].method)
+	// The source's config replaces the target's, with the overrides applied
+	var sent struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[2].body, &sent))
+	assert.Equal(t, "finalized", sent.Config["Commitment"])
+	assert.Equal(t, false, sent.Config["SkipPreflight"])
+	assert.Nil(t, sent.Config["TxTimeout"])
+
+	// Only the changes to the target are rendered
+	require.Len(t, r.Renders, 1)
+	diff := r.Renders[0].(*cmd.ChainConfigDiff)
+	assert.Equal(t, "prod", diff.ID)
+	keys := make([]string, len(diff.Fields))
+	for i, f := range diff.Fields {
+		keys[i] = f.Key
+	}
+	assert.Equal(t, []string{"Commitment", "SkipPreflight", "TxTimeout"}, keys)
+
+	stub, _, err = configure("typo",
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusOK, `{"data":[]}`),
+	)
+	assert.EqualError(t, err, "--from-show: chain typo not found")
+	for _, req := range stub.requests {
+		assert.Equal(t, "GET", req.method)
+	}
+}
+
+func TestClient_CheckSolanaChainRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	check := func(id string, responses ...*http.Response) (*cltest.RendererMock, error) {
+		r := &cltest.RendererMock{}
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: responses}, Renderer: r}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", id, "")
+		return r, client.CheckSolanaChainRoundTrip(cli.NewContext(nil, set, nil))
+	}
+
+	r, err := check("prod", stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s","Commitment":"confirmed"}}}}`))
+	require.NoError(t, err)
+	require.Len(t, r.Renders, 1)
+	assert.Empty(t, r.Renders[0].(*cmd.ConfigRoundTrip).Issues)
+
+	r, err = check("prod", stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"TxTimeout":"1m","Commitment":"confirmed","Extra":"x"}}}}`))
+	assert.EqualError(t, err, "the config of chain prod does not round-trip: 2 issue(s)")
+	require.Len(t, r.Renders, 1)
+	issues := r.Renders[0].(*cmd.ConfigRoundTrip).Issues
+	require.Len(t, issues, 2)
+	assert.Equal(t, cmd.RoundTripIssue{Key: "Extra", Node: "x", Issue: "dropped: unknown to the CLI"}, issues[0])
+	assert.Equal(t, cmd.RoundTripIssue{Key: "TxTimeout", Node: "1m", CLI: "1m0s", Issue: "value re-encoded"}, issues[1])
+
+	_, err = check("typo",
+		stubResponse(http.StatusNotFound, `{"errors":[{"detail":"not found"}]}`),
+		stubResponse(http.StatusOK, `{"data":[]}`),
+	)
+	assert.EqualError(t, err, "chain typo not found")
+
+	_, err = check("")
+	assert.EqualError(t, err, "missing chain ID [-id string]")
+}
+
+func TestClient_SolanaChains_OutputJSON(t *testing.T) {
+	t.Parallel()
+
+	run := func(action func(*cmd.Client, *cli.Context) error, output string, args []string, responses ...*http.Response) (string, error) {
+		var b bytes.Buffer
+		client := &cmd.Client{HTTP: &stubHTTPClient{responses: responses}, Renderer: cmd.RendererTable{Writer: &b}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "devnet", "")
+		set.String("output", output, "")
+		require.NoError(t, set.Parse(args))
+		err := action(client, cli.NewContext(nil, set, nil))
+		return b.String(), err
+	}
+
+	out, err := run((*cmd.Client).IndexSolanaChains, "json", nil,
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"confirmed"},"createdAt":"2022-03-01T12:00:00Z"}}]}`))
+	require.NoError(t, err)
+	var chains []presenters.SolanaChainResource
+	require.NoError(t, json.Unmarshal([]byte(out), &chains), out)
+	require.Len(t, chains, 1)
+	assert.True(t, chains[0].Enabled)
+	assert.Equal(t, "confirmed", chains[0].Config.Commitment.String)
+	assert.Equal(t, 2022, chains[0].CreatedAt.Year())
+
+	out, err = run((*cmd.Client).CreateSolanaChain, "json", []string{`{"Commitment": "finalized"}`},
+		stubResponse(http.StatusCreated, `{"data":{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{"Commitment":"finalized"}}}}`))
+	require.NoError(t, err)
+	var chain presenters.SolanaChainResource
+	require.NoError(t, json.Unmarshal([]byte(out), &chain), out)
+	assert.True(t, chain.Enabled)
+	assert.Equal(t, "finalized", chain.Config.Commitment.String)
+
+	// Tables stay the default
+	out, err = run((*cmd.Client).IndexSolanaChains, "", nil,
+		stubResponse(http.StatusOK, `{"data":[{"type":"solana_chain","id":"devnet","attributes":{"enabled":true,"config":{}}}]}`))
+	require.NoError(t, err)
+	assert.Contains(t, out, "devnet")
+	assert.False(t, json.Valid([]byte(out)))
+
+	out, err = run((*cmd.Client).IndexSolanaChains, "yaml", nil)
+	assert.EqualError(t, err, `unsupported output format "yaml" (options: table, json, markdown)`)
+	assert.Empty(t, out)
+}
+
+func TestClient_ConfigureSolanaChain_Unset(t *testing.T) {
+	t.Parallel()
+
+	configure := func(args ...string) (*stubHTTPClient, error) {
+		stub := &stubHTTPClient{responses: []*http.Response{
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"BalancePollPeriod":"5s","Commitment":"confirmed","TxTimeout":"1m0s"}}}}`),
+			stubResponse(http.StatusOK, `{"data":{"type":"solana_chain","id":"prod","attributes":{"enabled":true,"config":{"TxTimeout":"1m0s"}}}}`),
+		}}
+		client := &cmd.Client{HTTP: stub, Renderer: &cltest.RendererMock{}}
+		set := flag.NewFlagSet("cli", 0)
+		set.String("id", "prod", "")
+		require.NoError(t, set.Parse(args))
+		return stub, client.ConfigureSolanaChain(cli.NewContext(nil, set, nil))
+	}
+
+	stub, err := configure("BalancePollPeriod=", "Commitment=__unset__")
+	require.NoError(t, err)
+	require.Len(t, stub.requests, 2)
+	assert.Equal(t, http.MethodPatch, stub.requests[1].method)
+	var sent struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(stub.requests[1].body, &sent))
+	// The unset fields are removed rather than set to empty strings or null
+	for _, key := range []string{"BalancePollPeriod", "Commitment"} {
+		_, ok := sent.Config[key]
+		assert.False(t, ok, key)
+	}
+	assert.Equal(t, "1m0s", sent.Config["TxTimeout"])
+	// Fields which were not unset are still sent, even if null
+	_, ok := sent.Config["SkipPreflight"]
+	assert.True(t, ok)
+
+	stub, err = configure("BalancePollPerio=5s")
+	assert.EqualError(t, err, "invalid parameter BalancePollPerio=5s: unknown field 'BalancePollPerio'; did you mean BalancePollPeriod?")
+	assert.Empty(t, stub.requests)
+}
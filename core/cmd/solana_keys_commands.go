@@ -27,7 +27,7 @@ func (p *SolanaKeyPresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Solana Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -55,7 +55,7 @@ func (ps SolanaKeyPresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Solana Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -27,8 +27,8 @@ func (p *SolanaNodePresenter) ToRow() []string {
 		p.Name,
 		p.SolanaChainID,
 		p.SolanaURL,
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 	}
 	return row
 }
@@ -39,7 +39,7 @@ var solanaNodeHeaders = []string{"ID", "Name", "Chain ID", "URL", "Created", "Up
 func (p SolanaNodePresenter) RenderTable(rt RendererTable) error {
 	var rows [][]string
 	rows = append(rows, p.ToRow())
-	renderList(solanaNodeHeaders, rows, rt.Writer)
+	rt.renderList(solanaNodeHeaders, rows)
 
 	return nil
 }
@@ -55,7 +55,7 @@ func (ps SolanaNodePresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(solanaNodeHeaders, rows, rt.Writer)
+	rt.renderList(solanaNodeHeaders, rows)
 
 	return nil
 }
@@ -111,16 +111,24 @@ func (cli *Client) RemoveSolanaNode(c *cli.Context) (err error) {
 	if !c.Args().Present() {
 		return cli.errorOut(errors.New("must pass the id of the node to be removed"))
 	}
-	nodeID := c.Args().First()
-	resp, err := cli.HTTP.Delete("/v2/nodes/solana/" + nodeID)
-	if err != nil {
-		return cli.errorOut(err)
-	}
-	_, err = cli.parseResponse(resp)
-	if err != nil {
+	if err = cli.removeSolanaNode(c.Args().First()); err != nil {
 		return cli.errorOut(err)
 	}
 
 	fmt.Printf("Node %v deleted\n", c.Args().First())
 	return nil
 }
+
+func (cli *Client) removeSolanaNode(nodeID string) (err error) {
+	resp, err := cli.HTTP.Delete("/v2/nodes/solana/" + nodeID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+	_, err = cli.parseResponse(resp)
+	return err
+}
@@ -30,7 +30,7 @@ func (p *SolanaMsgPresenter) RenderTable(rt RendererTable) error {
 		strconv.FormatUint(p.Amount, 10),
 	})
 
-	render(fmt.Sprintf("Solana Message %v", p.ID), table)
+	rt.render(fmt.Sprintf("Solana Message %v", p.ID), table)
 	return nil
 }
 
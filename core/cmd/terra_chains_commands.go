@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -21,32 +22,43 @@ type TerraChainPresenter struct {
 	presenters.TerraChainResource
 }
 
-// ToRow presents the TerraChainResource as a slice of strings.
-func (p *TerraChainPresenter) ToRow() []string {
-	// NOTE: it's impossible to omitempty null fields when serializing to JSON: https://github.com/golang/go/issues/11939
-	config, err := json.MarshalIndent(p.Config, "", "    ")
+// ToRow presents the TerraChainResource as a slice of strings. It panics if
+// the config cannot be formatted, see Row.
+func (p *TerraChainPresenter) ToRow(rt RendererTable) []string {
+	row, err := p.Row(rt)
 	if err != nil {
 		panic(err)
 	}
+	return row
+}
+
+// Row presents the TerraChainResource as a slice of strings, or returns an
+// error if its config cannot be formatted.
+func (p *TerraChainPresenter) Row(rt RendererTable) ([]string, error) {
+	config, err := rt.formatChainConfig(p.Config)
+	if err != nil {
+		return nil, err
+	}
 
 	row := []string{
 		p.GetID(),
 		strconv.FormatBool(p.Enabled),
-		string(config),
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		config,
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 	}
-	return row
+	return row, nil
 }
 
 // RenderTable implements TableRenderer
 // Just renders a single row
 func (p TerraChainPresenter) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
-	rows := [][]string{}
-	rows = append(rows, p.ToRow())
+	row, err := p.Row(rt)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render chain %s", p.GetID())
+	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, [][]string{row})
 
 	return nil
 }
@@ -56,31 +68,77 @@ type TerraChainPresenters []TerraChainPresenter
 
 // RenderTable implements TableRenderer
 func (ps TerraChainPresenters) RenderTable(rt RendererTable) error {
-	headers := []string{"ID", "Enabled", "Config", "Created", "Updated"}
 	rows := [][]string{}
+	var failed rowErrors
 
 	for _, p := range ps {
-		rows = append(rows, p.ToRow())
+		row, err := p.Row(rt)
+		if err != nil {
+			failed.add(p.GetID(), err)
+			continue
+		}
+		rows = append(rows, row)
 	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderTypedList(chainHeaders, chainColumnTypes, rows)
 
-	return nil
+	return failed.report(rt)
 }
 
 // IndexTerraChains returns all Terra chains.
 func (cli *Client) IndexTerraChains(c *cli.Context) (err error) {
-	return cli.getPage("/v2/chains/terra", c.Int("page"), &TerraChainPresenters{})
+	if err = checkFlagConflicts(c, indexChainsConflicts...); err != nil {
+		return cli.errorOut(err)
+	}
+	cli.setChainRenderOpts(c)
+	uri, err := chainsPageURI(c, "terra")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	if c.Bool("raw") {
+		return cli.errorOut(cli.writeRaw(cli.stdout(), uri, c.Int("page")))
+	}
+	if c.Bool("with-links") {
+		return cli.errorOut(cli.renderPageWithLinks(c, uri, &TerraChainPresenters{}))
+	}
+	proj, err := jqFlag(c)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	fields, templateFile := selectFields(c), c.String("template-file")
+	if len(fields) == 0 && !c.Bool("wide") && templateFile == "" && proj == nil {
+		return cli.getPage(uri, c.Int("page"), &TerraChainPresenters{})
+	}
+
+	var chains TerraChainPresenters
+	if err = cli.getAllPages(cli.HTTP, uri, &chains); err != nil {
+		return cli.errorOut(err)
+	}
+	if len(fields) > 0 {
+		return cli.errorOut(writeSelected(cli.stdout(), chains, fields, c.Bool("headers")))
+	}
+	if templateFile != "" {
+		return cli.errorOut(executeTemplateFile(cli.stdout(), templateFile, chains, c.Bool("template-once")))
+	}
+	if proj != nil {
+		return cli.errorOut(writeProjected(cli.stdout(), chains, proj))
+	}
+	wide, err := cli.wideChains("terra", chains)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(&wide))
 }
 
 // CreateTerraChain adds a new Terra chain.
 func (cli *Client) CreateTerraChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in the chain's parameters [-id string] [JSON blob | JSON filepath]"))
+		return cli.errorOut(usageError(c, errors.New("must pass in the chain's parameters [-id string] [JSON blob | JSON filepath]")))
 	}
 	chainID := c.String("id")
 	if chainID == "" {
-		return cli.errorOut(errors.New("missing chain ID [-id string]"))
+		return cli.errorOut(usageError(c, errors.New("missing chain ID [-id string]")))
 	}
 
 	buf, err := getBufferFromJSON(c.Args().First())
@@ -88,6 +146,9 @@ func (cli *Client) CreateTerraChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
+	warnChainFamilyMismatch(c, os.Stderr, "terra", buf.Bytes())
+	warnDeprecatedConfigKeys(os.Stderr, "terra", json.RawMessage(buf.Bytes()))
+
 	params := map[string]interface{}{
 		"chainID": chainID,
 		"config":  json.RawMessage(buf.Bytes()),
@@ -115,7 +176,7 @@ func (cli *Client) CreateTerraChain(c *cli.Context) (err error) {
 // RemoveTerraChain removes a specific Terra Chain by id.
 func (cli *Client) RemoveTerraChain(c *cli.Context) (err error) {
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass the id of the chain to be removed"))
+		return cli.errorOut(usageError(c, errors.New("must pass the id of the chain to be removed")))
 	}
 	chainID := c.Args().First()
 	resp, err := cli.HTTP.Delete("/v2/chains/terra/" + chainID)
@@ -127,19 +188,20 @@ func (cli *Client) RemoveTerraChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
-	fmt.Printf("Chain %v deleted\n", c.Args().First())
+	fmt.Fprintf(cli.stdout(), "Chain %v deleted\n", c.Args().First())
 	return nil
 }
 
 // ConfigureTerraChain configures an existing Terra chain.
 func (cli *Client) ConfigureTerraChain(c *cli.Context) (err error) {
+	cli.setChainRenderOpts(c)
 	chainID := c.String("id")
 	if chainID == "" {
-		return cli.errorOut(errors.New("missing chain ID (usage: chainlink terra chains configure [-id string] [key1=value1 key2=value2 ...])"))
+		return cli.errorOut(usageError(c, errors.New("missing chain ID (usage: chainlink terra chains configure [-id string] [key1=value1 key2=value2 ...])")))
 	}
 
 	if !c.Args().Present() {
-		return cli.errorOut(errors.New("must pass in at least one chain configuration parameters (usage: chainlink terra chains configure [-id string] [key1=value1 key2=value2 ...])"))
+		return cli.errorOut(usageError(c, errors.New("must pass in at least one chain configuration parameters (usage: chainlink terra chains configure [-id string] [key1=value1 key2=value2 ...])")))
 	}
 
 	// Fetch existing config
@@ -163,7 +225,7 @@ func (cli *Client) ConfigureTerraChain(c *cli.Context) (err error) {
 	for _, arg := range c.Args() {
 		parts := strings.SplitN(arg, "=", 2)
 		if len(parts) != 2 {
-			return cli.errorOut(errors.Errorf("invalid parameter: %v", arg))
+			return cli.errorOut(usageError(c, errors.Errorf("invalid parameter: %v", arg)))
 		}
 
 		var value interface{}
@@ -187,6 +249,8 @@ func (cli *Client) ConfigureTerraChain(c *cli.Context) (err error) {
 		return cli.errorOut(err)
 	}
 
+	warnDeprecatedConfigKeys(os.Stderr, "terra", config)
+
 	// Send the new config
 	params = map[string]interface{}{
 		"enabled": chain.Enabled,
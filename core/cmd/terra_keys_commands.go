@@ -27,7 +27,7 @@ func (p *TerraKeyPresenter) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Terra Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
@@ -55,7 +55,7 @@ func (ps TerraKeyPresenters) RenderTable(rt RendererTable) error {
 	if _, err := rt.Write([]byte("🔑 Terra Keys\n")); err != nil {
 		return err
 	}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 
 	return utils.JustError(rt.Write([]byte("\n")))
 }
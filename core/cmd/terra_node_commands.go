@@ -26,8 +26,8 @@ func (p *TerraNodePresenter) ToRow() []string {
 		p.Name,
 		p.TerraChainID,
 		p.TendermintURL,
-		p.CreatedAt.String(),
-		p.UpdatedAt.String(),
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
 	}
 	return row
 }
@@ -38,7 +38,7 @@ var terraNodeHeaders = []string{"ID", "Name", "Chain ID", "Tendermint URL", "Cre
 func (p TerraNodePresenter) RenderTable(rt RendererTable) error {
 	var rows [][]string
 	rows = append(rows, p.ToRow())
-	renderList(terraNodeHeaders, rows, rt.Writer)
+	rt.renderList(terraNodeHeaders, rows)
 
 	return nil
 }
@@ -54,7 +54,7 @@ func (ps TerraNodePresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(terraNodeHeaders, rows, rt.Writer)
+	rt.renderList(terraNodeHeaders, rows)
 
 	return nil
 }
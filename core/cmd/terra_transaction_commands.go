@@ -33,7 +33,7 @@ func (p *TerraMsgPresenter) RenderTable(rt RendererTable) error {
 		hash,
 	})
 
-	render(fmt.Sprintf("Terra Message %v", p.ID), table)
+	rt.render(fmt.Sprintf("Terra Message %v", p.ID), table)
 	return nil
 }
 
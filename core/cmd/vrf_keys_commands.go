@@ -25,7 +25,7 @@ type VRFKeyPresenter struct {
 func (p *VRFKeyPresenter) RenderTable(rt RendererTable) error {
 	headers := []string{"Compressed", "Uncompressed", "Hash"}
 	rows := [][]string{p.ToRow()}
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 	_, err := rt.Write([]byte("\n"))
 	return err
 }
@@ -49,7 +49,7 @@ func (ps VRFKeyPresenters) RenderTable(rt RendererTable) error {
 		rows = append(rows, p.ToRow())
 	}
 
-	renderList(headers, rows, rt.Writer)
+	rt.renderList(headers, rows)
 	_, err := rt.Write([]byte("\n"))
 	return err
 }
@@ -35,7 +35,7 @@ func NewProductionClient() *cmd.Client {
 	cfg := config.NewGeneralConfig(lggr)
 
 	prompter := cmd.NewTerminalPrompter()
-	cookieAuth := cmd.NewSessionCookieAuthenticator(cfg, cmd.DiskCookieStore{Config: cfg}, lggr)
+	cookieAuth := cmd.NewSessionCookieAuthenticator(cfg, cmd.EnvCookieStore{CookieStore: cmd.DiskCookieStore{Config: cfg}}, lggr)
 	sr := sessions.SessionRequest{}
 	sessionRequestBuilder := cmd.NewFileSessionRequestBuilder(lggr)
 	if credentialsFile := cfg.AdminCredentialsFile(); credentialsFile != "" {